@@ -0,0 +1,332 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/cluster"
+	"github.com/spectrum-proxy/spectrum/party"
+	"github.com/spectrum-proxy/spectrum/server"
+	"github.com/spectrum-proxy/spectrum/session"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Controller controls the state of the proxy that the Admin API exposes. It is implemented by
+// spectrum.Spectrum.
+type Controller interface {
+	// SetMaintenance enables or disables maintenance mode.
+	SetMaintenance(enabled bool)
+	// Maintenance returns true if the proxy is in maintenance mode.
+	Maintenance() bool
+	// Reload reloads the configuration of the proxy.
+	Reload() error
+	// Circuits returns the state of the circuit breaker of every server, keyed by the address of the server.
+	Circuits() map[string]server.CircuitState
+	// Parties returns the manager of the parties on the proxy.
+	Parties() *party.Manager
+	// Presence looks up whether the player with the XUID or display name passed is online on the proxy or its
+	// cluster, and the server they are connected to.
+	Presence(player string) (cluster.Presence, bool, error)
+	// Presences looks up the presence of each of the players passed, returning the presences of the players
+	// online keyed by the XUID or display name they were looked up by.
+	Presences(players ...string) (map[string]cluster.Presence, error)
+}
+
+// Admin serves an HTTP API used to control the proxy while it is running. Every request must carry the token
+// of the Admin as bearer token in its Authorization header.
+//
+// The following endpoints are served:
+//
+//	GET  /sessions                  lists all sessions
+//	POST /sessions/{player}/kick    disconnects a player, {"reason": "..."}
+//	POST /sessions/{player}/transfer transfers a player, {"addr": "..."}
+//	DELETE /sessions/{player}/transfer cancels the transfer of a player in progress
+//	GET  /sessions/{player}/traffic returns the traffic of a player per packet ID
+//	GET  /sessions/{player}/stats   returns the join time, transfers, traffic and server of a player
+//	POST /broadcast                 sends a message to all players, {"message": "..."}
+//	GET  /maintenance               returns the maintenance mode
+//	POST /maintenance               changes the maintenance mode, {"enabled": true}
+//	POST /reload                    reloads the configuration
+//	GET  /circuits                  returns the circuit breaker state of every server
+//	GET  /traffic                   returns the traffic of all players per packet ID
+//	GET  /parties                   lists all parties
+//	GET  /sessions/{player}/party   returns the party of a player
+//	GET  /presence/{player}         returns whether a player is online, their server and latency
+//	GET  /presence?players=a,b      returns the presences of the players listed that are online
+//
+// Players are identified by their XUID or display name.
+type Admin struct {
+	logger     *slog.Logger
+	sessions   *session.Registry
+	controller Controller
+	token      string
+
+	server *http.Server
+}
+
+// NewAdmin returns a new Admin controlling the sessions and the proxy passed. Requests are only accepted if
+// they carry the token passed, which must not be empty.
+func NewAdmin(logger *slog.Logger, sessions *session.Registry, controller Controller, token string) *Admin {
+	return &Admin{
+		logger:     logger,
+		sessions:   sessions,
+		controller: controller,
+		token:      token,
+	}
+}
+
+// ListenAndServe serves the API on the address passed. It blocks until the Admin is closed, in which case
+// http.ErrServerClosed is returned.
+func (a *Admin) ListenAndServe(addr string) error {
+	a.server = &http.Server{Addr: addr, Handler: a.Handler()}
+	return a.server.ListenAndServe()
+}
+
+// Close stops serving the API.
+func (a *Admin) Close() error {
+	if a.server == nil {
+		return nil
+	}
+	return a.server.Close()
+}
+
+// Handler returns the http.Handler serving the API, which may be used to serve the API on an existing server.
+func (a *Admin) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sessions", a.handleSessions)
+	mux.HandleFunc("POST /sessions/{player}/kick", a.handleKick)
+	mux.HandleFunc("POST /sessions/{player}/transfer", a.handleTransfer)
+	mux.HandleFunc("DELETE /sessions/{player}/transfer", a.handleCancelTransfer)
+	mux.HandleFunc("POST /broadcast", a.handleBroadcast)
+	mux.HandleFunc("GET /maintenance", a.handleMaintenance)
+	mux.HandleFunc("POST /maintenance", a.handleSetMaintenance)
+	mux.HandleFunc("POST /reload", a.handleReload)
+	mux.HandleFunc("GET /circuits", a.handleCircuits)
+	mux.HandleFunc("GET /sessions/{player}/traffic", a.handleSessionTraffic)
+	mux.HandleFunc("GET /sessions/{player}/stats", a.handleSessionStats)
+	mux.HandleFunc("GET /traffic", a.handleTraffic)
+	mux.HandleFunc("GET /parties", a.handleParties)
+	mux.HandleFunc("GET /sessions/{player}/party", a.handleSessionParty)
+	mux.HandleFunc("GET /presence/{player}", a.handlePresence)
+	mux.HandleFunc("GET /presence", a.handlePresences)
+	return a.authenticate(mux)
+}
+
+// authenticate wraps the handler passed so that requests without the token of the Admin are rejected.
+func (a *Admin) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || a.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (a *Admin) handleSessions(w http.ResponseWriter, _ *http.Request) {
+	diagnostics := make([]session.Diagnostics, 0, a.sessions.Count())
+	for _, s := range a.sessions.Sessions() {
+		diagnostics = append(diagnostics, s.Diagnostics())
+	}
+	writeJSON(w, http.StatusOK, diagnostics)
+}
+
+func (a *Admin) handleKick(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	s, ok := a.player(w, r, &req)
+	if !ok {
+		return
+	}
+
+	s.Disconnect(req.Reason)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Admin) handleTransfer(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Addr string `json:"addr"`
+	}
+	s, ok := a.player(w, r, &req)
+	if !ok {
+		return
+	}
+	if req.Addr == "" {
+		writeError(w, http.StatusBadRequest, "missing addr")
+		return
+	}
+
+	if err := s.Transfer(r.Context(), req.Addr); err != nil {
+		a.logger.Error("Failed to transfer session", "err", err)
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Admin) handleCancelTransfer(w http.ResponseWriter, r *http.Request) {
+	s, ok := a.player(w, r, &struct{}{})
+	if !ok {
+		return
+	}
+	if !s.CancelTransfer() {
+		writeError(w, http.StatusConflict, "no transfer in progress")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Admin) handleBroadcast(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Message string `json:"message"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	a.sessions.Broadcast(&packet.Text{TextType: packet.TextTypeRaw, Message: req.Message})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Admin) handleMaintenance(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]bool{"enabled": a.controller.Maintenance()})
+}
+
+func (a *Admin) handleSetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Enabled bool `json:"enabled"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	a.controller.SetMaintenance(req.Enabled)
+	writeJSON(w, http.StatusOK, map[string]bool{"enabled": a.controller.Maintenance()})
+}
+
+func (a *Admin) handleReload(w http.ResponseWriter, _ *http.Request) {
+	if err := a.controller.Reload(); err != nil {
+		a.logger.Error("Failed to reload configuration", "err", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Admin) handleCircuits(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, a.controller.Circuits())
+}
+
+func (a *Admin) handleSessionTraffic(w http.ResponseWriter, r *http.Request) {
+	s, ok := a.player(w, r, &struct{}{})
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.Traffic())
+}
+
+func (a *Admin) handleSessionStats(w http.ResponseWriter, r *http.Request) {
+	s, ok := a.player(w, r, &struct{}{})
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.Stats())
+}
+
+func (a *Admin) handleTraffic(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, session.GlobalTraffic())
+}
+
+func (a *Admin) handleParties(w http.ResponseWriter, _ *http.Request) {
+	parties := a.controller.Parties().Parties()
+	infos := make([]party.Info, 0, len(parties))
+	for _, p := range parties {
+		infos = append(infos, p.Info())
+	}
+	writeJSON(w, http.StatusOK, infos)
+}
+
+func (a *Admin) handleSessionParty(w http.ResponseWriter, r *http.Request) {
+	s, ok := a.player(w, r, &struct{}{})
+	if !ok {
+		return
+	}
+	p := a.controller.Parties().Of(s)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "player is not in a party")
+		return
+	}
+	writeJSON(w, http.StatusOK, p.Info())
+}
+
+func (a *Admin) handlePresence(w http.ResponseWriter, r *http.Request) {
+	presence, ok, err := a.controller.Presence(r.PathValue("player"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, "player is not online")
+		return
+	}
+	writeJSON(w, http.StatusOK, presence)
+}
+
+func (a *Admin) handlePresences(w http.ResponseWriter, r *http.Request) {
+	var players []string
+	for _, player := range strings.Split(r.URL.Query().Get("players"), ",") {
+		if player = strings.TrimSpace(player); player != "" {
+			players = append(players, player)
+		}
+	}
+	presences, err := a.controller.Presences(players...)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, presences)
+}
+
+// player decodes the body of the request into v and looks up the session of the player in the path of the
+// request. It writes an error response and returns false if either fails.
+func (a *Admin) player(w http.ResponseWriter, r *http.Request, v any) (*session.Session, bool) {
+	if !decode(w, r, v) {
+		return nil, false
+	}
+
+	s := a.sessions.Lookup(r.PathValue("player"))
+	if s == nil {
+		writeError(w, http.StatusNotFound, "player not found")
+		return nil, false
+	}
+	return s, true
+}
+
+// decode decodes the JSON body of the request into v. An empty body leaves v untouched. decode writes an error
+// response and returns false if the body is invalid.
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if r.ContentLength == 0 {
+		return true
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// writeJSON writes v as JSON response with the status passed.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response with the status and message passed.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
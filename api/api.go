@@ -2,24 +2,28 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
-	"github.com/sirupsen/logrus"
 	"github.com/spectrum-proxy/spectrum/api/packet"
+	"github.com/spectrum-proxy/spectrum/ban"
 	"github.com/spectrum-proxy/spectrum/internal"
 	"github.com/spectrum-proxy/spectrum/protocol"
 	"github.com/spectrum-proxy/spectrum/session"
+	"log/slog"
 	"net"
+	"time"
 )
 
 type API struct {
-	logger   *logrus.Logger
+	logger   *slog.Logger
 	sessions *session.Registry
+	bans     ban.Store
 
 	listener net.Listener
 	pool     packet.Pool
 }
 
-func NewAPI(logger *logrus.Logger, sessions *session.Registry) *API {
+func NewAPI(logger *slog.Logger, sessions *session.Registry) *API {
 	return &API{
 		logger:   logger,
 		sessions: sessions,
@@ -27,6 +31,12 @@ func NewAPI(logger *logrus.Logger, sessions *session.Registry) *API {
 	}
 }
 
+// SetBanStore sets the store that bans received through the API are added to. If no store is set, ban
+// requests are ignored.
+func (a *API) SetBanStore(store ban.Store) {
+	a.bans = store
+}
+
 func (a *API) Listen(addr string) error {
 	listener, err := net.Listen("tcp", addr)
 	if err != nil {
@@ -58,12 +68,21 @@ func (a *API) handle(conn net.Conn) {
 	defer conn.Close()
 
 	reader := protocol.NewReader(conn)
+	go func() {
+		for reader.Read() == nil {
+		}
+	}()
+
 	for {
-		data := reader.ReadPacket()
+		data, err := reader.ReadPacket()
+		if err != nil {
+			return
+		}
+
 		packetID := binary.LittleEndian.Uint32(data)
 		factory, ok := a.pool[packetID]
 		if !ok {
-			a.logger.Errorf("unknown packet ID: %v", packetID)
+			a.logger.Error("Unknown packet ID", "id", packetID)
 			continue
 		}
 
@@ -89,9 +108,47 @@ func (a *API) handle(conn net.Conn) {
 				continue
 			}
 
-			if err := s.Transfer(pk.Addr); err != nil {
-				a.logger.Errorf("error transferring session: %v", err)
+			if err := s.Transfer(context.Background(), pk.Addr); err != nil {
+				a.logger.Error("Failed to transfer session", "err", err)
+			}
+		case *packet.Ban:
+			a.handleBan(pk)
+		case *packet.Unban:
+			if a.bans == nil {
+				continue
+			}
+
+			if err := a.bans.Remove(ban.Type(pk.Type), pk.Target); err != nil {
+				a.logger.Error("Failed to remove ban", "err", err)
 			}
 		}
 	}
 }
+
+// handleBan adds the ban requested to the ban store and disconnects all sessions it applies to.
+func (a *API) handleBan(pk *packet.Ban) {
+	if a.bans == nil {
+		return
+	}
+
+	entry := ban.Entry{
+		Type:    ban.Type(pk.Type),
+		Target:  pk.Target,
+		Reason:  pk.Reason,
+		Created: time.Now(),
+	}
+	if pk.Duration > 0 {
+		entry.Expiry = entry.Created.Add(time.Duration(pk.Duration) * time.Second)
+	}
+	if err := a.bans.Add(entry); err != nil {
+		a.logger.Error("Failed to add ban", "err", err)
+		return
+	}
+
+	for _, s := range a.sessions.Sessions() {
+		client := s.Client()
+		if entry.Matches(client.IdentityData().XUID, ban.IP(client.RemoteAddr())) {
+			s.Disconnect(entry.Message())
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package packet
+
+import "bytes"
+
+// Ban bans a player by XUID or IP address. Players matching the ban are disconnected.
+type Ban struct {
+	// Type is the type of the ban, either "xuid" or "ip".
+	Type   string
+	Target string
+	Reason string
+	// Duration is the duration of the ban in seconds. If zero, the ban is permanent.
+	Duration int64
+}
+
+// ID ...
+func (b *Ban) ID() uint32 {
+	return IDBan
+}
+
+// Encode ...
+func (b *Ban) Encode(buf *bytes.Buffer) {
+	writeString(buf, b.Type)
+	writeString(buf, b.Target)
+	writeString(buf, b.Reason)
+	writeInt64(buf, b.Duration)
+}
+
+// Decode ...
+func (b *Ban) Decode(buf *bytes.Buffer) {
+	b.Type = readString(buf)
+	b.Target = readString(buf)
+	b.Reason = readString(buf)
+	b.Duration = readInt64(buf)
+}
@@ -18,3 +18,13 @@ func readString(buf *bytes.Buffer) string {
 	_, _ = buf.Read(data)
 	return string(data)
 }
+
+func writeInt64(buf *bytes.Buffer, v int64) {
+	_ = binary.Write(buf, binary.LittleEndian, v)
+}
+
+func readInt64(buf *bytes.Buffer) int64 {
+	var v int64
+	_ = binary.Read(buf, binary.LittleEndian, &v)
+	return v
+}
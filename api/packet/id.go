@@ -3,4 +3,6 @@ package packet
 const (
 	IDKick = iota
 	IDTransfer
+	IDBan
+	IDUnban
 )
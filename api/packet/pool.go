@@ -19,4 +19,6 @@ func NewPool() Pool {
 func init() {
 	Register(IDTransfer, func() Packet { return &Transfer{} })
 	Register(IDKick, func() Packet { return &Kick{} })
+	Register(IDBan, func() Packet { return &Ban{} })
+	Register(IDUnban, func() Packet { return &Unban{} })
 }
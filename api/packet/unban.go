@@ -0,0 +1,27 @@
+package packet
+
+import "bytes"
+
+// Unban removes the ban of a XUID or IP address.
+type Unban struct {
+	// Type is the type of the ban, either "xuid" or "ip".
+	Type   string
+	Target string
+}
+
+// ID ...
+func (u *Unban) ID() uint32 {
+	return IDUnban
+}
+
+// Encode ...
+func (u *Unban) Encode(buf *bytes.Buffer) {
+	writeString(buf, u.Type)
+	writeString(buf, u.Target)
+}
+
+// Decode ...
+func (u *Unban) Decode(buf *bytes.Buffer) {
+	u.Type = readString(buf)
+	u.Target = readString(buf)
+}
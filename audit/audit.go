@@ -0,0 +1,96 @@
+// Package audit records the joins, quits, transfers and kicks of players, for use in moderation and abuse
+// investigations. Records are written to a Sink, such as a rotating file.
+package audit
+
+import (
+	"encoding/json"
+	"log/slog"
+	"time"
+)
+
+// Action is the kind of action a Record describes.
+type Action string
+
+const (
+	// ActionJoin is recorded when a player joined the proxy and was connected to its first server.
+	ActionJoin Action = "join"
+	// ActionQuit is recorded when the session of a player was closed.
+	ActionQuit Action = "quit"
+	// ActionTransfer is recorded when a player was transferred between servers.
+	ActionTransfer Action = "transfer"
+	// ActionKick is recorded when a player was disconnected by the proxy.
+	ActionKick Action = "kick"
+)
+
+// Record is a single entry of the audit log.
+type Record struct {
+	Time   time.Time `json:"time"`
+	Action Action    `json:"action"`
+	Name   string    `json:"name"`
+	XUID   string    `json:"xuid"`
+	IP     string    `json:"ip"`
+	// From and To are the addresses of the servers involved. For joins, only To is set, and for quits only
+	// From is set.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Reason is the reason of a kick or the error a transfer failed with.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Sink writes records of the audit log. Sinks must be safe for concurrent use.
+type Sink interface {
+	// Write writes the record passed.
+	Write(r Record) error
+	// Close flushes and closes the sink.
+	Close() error
+}
+
+// LoggerSink is a Sink writing records to a slog.Logger.
+type LoggerSink struct {
+	Logger *slog.Logger
+}
+
+// Write ...
+func (s LoggerSink) Write(r Record) error {
+	s.Logger.Info("Audit", "action", r.Action, "name", r.Name, "xuid", r.XUID, "ip", r.IP, "from", r.From, "to", r.To, "reason", r.Reason)
+	return nil
+}
+
+// Close ...
+func (LoggerSink) Close() error {
+	return nil
+}
+
+// MultiSink is a Sink writing records to multiple sinks.
+type MultiSink []Sink
+
+// Write ...
+func (m MultiSink) Write(r Record) error {
+	var firstErr error
+	for _, s := range m {
+		if err := s.Write(r); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Close ...
+func (m MultiSink) Close() error {
+	var firstErr error
+	for _, s := range m {
+		if err := s.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// encode encodes the record passed as a single line of JSON.
+func encode(r Record) ([]byte, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileSink is a Sink writing records as JSON lines to a file. Once the file exceeds its maximum size, it is
+// rotated: the file is renamed with a numbered suffix, such as audit.log.1, and a new file is started.
+type FileSink struct {
+	path    string
+	maxSize int64
+	backups int
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+// NewFileSink opens a FileSink appending to the file at the path passed. The file is rotated once it exceeds
+// maxSize bytes, keeping up to the amount of backups passed. If maxSize is zero, the file is never rotated.
+func NewFileSink(path string, maxSize int64, backups int) (*FileSink, error) {
+	s := &FileSink{path: path, maxSize: maxSize, backups: backups}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Write ...
+func (s *FileSink) Write(r Record) error {
+	data, err := encode(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode record: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return os.ErrClosed
+	}
+	if s.maxSize > 0 && s.size+int64(len(data)) > s.maxSize && s.size > 0 {
+		if err := s.rotate(); err != nil {
+			return fmt.Errorf("failed to rotate audit log: %v", err)
+		}
+	}
+	n, err := s.f.Write(data)
+	s.size += int64(n)
+	return err
+}
+
+// Close ...
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
+
+// open opens the file of the sink for appending.
+func (s *FileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %v", err)
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to stat audit log: %v", err)
+	}
+	s.f, s.size = f, stat.Size()
+	return nil
+}
+
+// rotate closes the current file, shifts the backups and opens a new file.
+func (s *FileSink) rotate() error {
+	if err := s.f.Close(); err != nil {
+		return err
+	}
+	if s.backups <= 0 {
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return s.open()
+	}
+
+	_ = os.Remove(fmt.Sprintf("%s.%d", s.path, s.backups))
+	for i := s.backups - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1))
+	}
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		return err
+	}
+	return s.open()
+}
@@ -0,0 +1,96 @@
+package ban
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// Type is the type of target a ban applies to.
+type Type string
+
+const (
+	// TypeXUID bans a player by their XUID.
+	TypeXUID Type = "xuid"
+	// TypeIP bans all players connecting from an IP address.
+	TypeIP Type = "ip"
+)
+
+// Entry is a single ban.
+type Entry struct {
+	// Type is the type of the target of the ban.
+	Type Type `json:"type"`
+	// Target is the XUID or IP address that is banned.
+	Target string `json:"target"`
+	// Reason is the reason of the ban, which is shown to the player when they are disconnected.
+	Reason string `json:"reason,omitempty"`
+	// Created is the time at which the ban was created.
+	Created time.Time `json:"created"`
+	// Expiry is the time at which the ban expires. If zero, the ban is permanent.
+	Expiry time.Time `json:"expiry,omitempty"`
+}
+
+// Expired returns true if the ban is temporary and has expired.
+func (e Entry) Expired() bool {
+	return !e.Expiry.IsZero() && time.Now().After(e.Expiry)
+}
+
+// Message returns the message shown to a banned player on the disconnect screen.
+func (e Entry) Message() string {
+	message := "You are banned from this server."
+	if e.Reason != "" {
+		message += fmt.Sprintf("\nReason: %s", e.Reason)
+	}
+	if !e.Expiry.IsZero() {
+		message += fmt.Sprintf("\nExpires: %s", e.Expiry.Format(time.DateTime))
+	}
+	return message
+}
+
+// Matches returns true if the ban applies to a player with the XUID and IP address passed.
+func (e Entry) Matches(xuid, ip string) bool {
+	switch e.Type {
+	case TypeXUID:
+		return e.Target == xuid
+	case TypeIP:
+		return e.Target == ip
+	}
+	return false
+}
+
+// IP returns the IP address of the network address passed, without its port.
+func IP(addr net.Addr) string {
+	if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+		return host
+	}
+	return addr.String()
+}
+
+// Check looks up the bans of a player with the XUID and IP address passed in the store passed. It returns the
+// first ban that applies to the player, if any. Expired bans are removed from the store.
+func Check(store Store, xuid, ip string) (Entry, bool, error) {
+	for _, target := range []struct {
+		typ    Type
+		target string
+	}{{TypeXUID, xuid}, {TypeIP, ip}} {
+		if target.target == "" {
+			continue
+		}
+
+		entry, ok, err := store.Get(target.typ, target.target)
+		if err != nil {
+			return Entry{}, false, err
+		}
+		if !ok {
+			continue
+		}
+		if entry.Expired() {
+			if err := store.Remove(target.typ, target.target); err != nil {
+				return Entry{}, false, err
+			}
+			continue
+		}
+		return entry, true, nil
+	}
+	return Entry{}, false, nil
+}
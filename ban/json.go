@@ -0,0 +1,102 @@
+package ban
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// JSONStore is a Store that persists bans to a JSON file. The file is rewritten whenever a ban is added or
+// removed.
+type JSONStore struct {
+	path string
+
+	// mu serialises writes to the file.
+	mu     sync.Mutex
+	memory *MemoryStore
+}
+
+// NewJSONStore returns a JSONStore persisting bans to the file at the path passed. Existing bans are loaded
+// from the file if it exists.
+func NewJSONStore(path string) (*JSONStore, error) {
+	s := &JSONStore{path: path, memory: NewMemoryStore()}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bans: %v", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode bans: %v", err)
+	}
+	for _, entry := range entries {
+		_ = s.memory.Add(entry)
+	}
+	return s, nil
+}
+
+// Add ...
+func (s *JSONStore) Add(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_ = s.memory.Add(entry)
+	return s.save()
+}
+
+// Remove ...
+func (s *JSONStore) Remove(typ Type, target string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok, _ := s.memory.Get(typ, target); !ok {
+		return nil
+	}
+	_ = s.memory.Remove(typ, target)
+	return s.save()
+}
+
+// Get ...
+func (s *JSONStore) Get(typ Type, target string) (Entry, bool, error) {
+	return s.memory.Get(typ, target)
+}
+
+// Entries ...
+func (s *JSONStore) Entries() ([]Entry, error) {
+	return s.memory.Entries()
+}
+
+// save writes all bans to the file of the store. The file is replaced atomically, so that it is never left
+// partially written. save must be called with mu held.
+func (s *JSONStore) save() error {
+	entries, _ := s.memory.Entries()
+	data, err := json.MarshalIndent(entries, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to encode bans: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write bans: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write bans: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write bans: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write bans: %v", err)
+	}
+	return nil
+}
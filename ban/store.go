@@ -0,0 +1,75 @@
+package ban
+
+import (
+	"sort"
+	"sync"
+)
+
+// Store stores bans. Implementations must be safe for concurrent use, so that bans may be added while players
+// are logging in.
+type Store interface {
+	// Add adds a ban, replacing any existing ban of the same target.
+	Add(entry Entry) error
+	// Remove removes the ban of the target passed. Removing a ban that does not exist is not an error.
+	Remove(typ Type, target string) error
+	// Get looks up the ban of the target passed.
+	Get(typ Type, target string) (Entry, bool, error)
+	// Entries returns all bans in the store.
+	Entries() ([]Entry, error)
+}
+
+// key is the key of a ban in a MemoryStore.
+type key struct {
+	typ    Type
+	target string
+}
+
+// MemoryStore is a Store holding bans in memory. Bans are lost when the process exits.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	entries map[key]Entry
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[key]Entry)}
+}
+
+// Add ...
+func (s *MemoryStore) Add(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key{typ: entry.Type, target: entry.Target}] = entry
+	return nil
+}
+
+// Remove ...
+func (s *MemoryStore) Remove(typ Type, target string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key{typ: typ, target: target})
+	return nil
+}
+
+// Get ...
+func (s *MemoryStore) Get(typ Type, target string) (Entry, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.entries[key{typ: typ, target: target}]
+	return entry, ok, nil
+}
+
+// Entries returns all bans in the store, sorted by the time they were created.
+func (s *MemoryStore) Entries() ([]Entry, error) {
+	s.mu.RLock()
+	entries := make([]Entry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Created.Before(entries[j].Created)
+	})
+	return entries, nil
+}
@@ -0,0 +1,38 @@
+package spectrum
+
+import (
+	"github.com/spectrum-proxy/spectrum/metrics"
+	"github.com/spectrum-proxy/spectrum/server"
+)
+
+// registerBreakerMetrics registers the gauge reporting the servers whose circuit is open, if the circuit
+// breaker is enabled.
+func (s *Spectrum) registerBreakerMetrics() {
+	if s.breaker == nil {
+		return
+	}
+	metrics.NewGaugeVecFunc("spectrum_circuit_open", "Whether the circuit of a server is open.", "server", func() map[string]float64 {
+		values := make(map[string]float64)
+		for addr, state := range s.breaker.States() {
+			values[addr] = 0
+			if state != server.CircuitClosed {
+				values[addr] = 1
+			}
+		}
+		return values
+	})
+}
+
+// Breaker returns the server.Breaker used when dialing servers, or nil if the circuit breaker is not enabled.
+func (s *Spectrum) Breaker() *server.Breaker {
+	return s.breaker
+}
+
+// Circuits returns the state of the circuit of every server dialed, keyed by the address of the server. If
+// the circuit breaker is not enabled, an empty map is returned.
+func (s *Spectrum) Circuits() map[string]server.CircuitState {
+	if s.breaker == nil {
+		return map[string]server.CircuitState{}
+	}
+	return s.breaker.States()
+}
@@ -0,0 +1,73 @@
+package capture
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Buffer holds the packets of the most recent period of time in memory, so that they may be written to a
+// capture after the fact, for example when a player is reported. Packets older than the window of the Buffer
+// are discarded as new packets are added. A Buffer may be used concurrently.
+type Buffer struct {
+	window   time.Duration
+	maxBytes int
+
+	mu      sync.Mutex
+	records []Record
+	size    int
+}
+
+// NewBuffer returns a Buffer holding the packets of the window passed. If the packets of the window exceed
+// maxBytes in total, the oldest packets are discarded early. If maxBytes is zero, the size is not limited.
+func NewBuffer(window time.Duration, maxBytes int) *Buffer {
+	return &Buffer{window: window, maxBytes: maxBytes}
+}
+
+// Add encodes and adds the packet passed, which was sent in the direction passed at the time passed, and
+// discards the packets that fell out of the window. Shield items in the packet are encoded using the shield
+// ID passed.
+func (b *Buffer) Add(dir Direction, t time.Time, pk packet.Packet, shieldID int32) error {
+	data, err := encode(pk, shieldID)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.records = append(b.records, Record{Direction: dir, Time: t, Data: data})
+	b.size += len(data)
+
+	var n int
+	for ; n < len(b.records)-1; n++ {
+		if t.Sub(b.records[n].Time) <= b.window && (b.maxBytes == 0 || b.size <= b.maxBytes) {
+			break
+		}
+		b.size -= len(b.records[n].Data)
+	}
+	// The discarded records are freed once append moves the records to a new array.
+	clear(b.records[:n])
+	b.records = b.records[n:]
+	return nil
+}
+
+// Dump writes the packets currently held by the Buffer to the io.Writer passed as a capture, which may be read
+// using a Reader. The packets remain in the Buffer.
+func (b *Buffer) Dump(w io.Writer) error {
+	b.mu.Lock()
+	records := slices.Clone(b.records)
+	b.mu.Unlock()
+
+	cw, err := NewWriter(w, 0)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		if err := cw.writeRecord(r); err != nil {
+			return err
+		}
+	}
+	return cw.Close()
+}
@@ -0,0 +1,195 @@
+// Package capture records the packets of a session to a file and reads them back, so that desyncs reported by
+// players can be reproduced by replaying the packets.
+//
+// A capture starts with a magic header, followed by any number of records. Every record holds the direction of
+// the packet, the time it was received in nanoseconds since the Unix epoch, the length of the packet and the
+// encoded packet itself, including its header.
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"io"
+	"sync"
+	"time"
+)
+
+// magic is the header every capture starts with.
+var magic = []byte("SPCAP\x01")
+
+// Direction is the direction a captured packet was sent in.
+type Direction byte
+
+const (
+	// Clientbound is the direction of packets sent by the server to the client.
+	Clientbound Direction = iota
+	// Serverbound is the direction of packets sent by the client to the server.
+	Serverbound
+)
+
+// String ...
+func (d Direction) String() string {
+	if d == Clientbound {
+		return "clientbound"
+	}
+	return "serverbound"
+}
+
+// Record is a single packet read from a capture.
+type Record struct {
+	Direction Direction
+	Time      time.Time
+	// Data is the encoded packet, including its header.
+	Data []byte
+}
+
+// ID returns the ID of the packet of the record.
+func (r Record) ID() uint32 {
+	var header packet.Header
+	_ = header.Read(bytes.NewBuffer(r.Data))
+	return header.PacketID
+}
+
+// Decode decodes the packet of the record using the packets in the pool passed. If the pool has no packet with
+// the ID of the record, a *packet.Unknown is returned.
+func (r Record) Decode(pool packet.Pool, shieldID int32) (pk packet.Packet, err error) {
+	buf := bytes.NewBuffer(r.Data)
+	var header packet.Header
+	if err := header.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to read packet header: %v", err)
+	}
+
+	factory, ok := pool[header.PacketID]
+	if !ok {
+		return &packet.Unknown{PacketID: header.PacketID, Payload: buf.Bytes()}, nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic while decoding packet %v: %v", header.PacketID, r)
+		}
+	}()
+	pk = factory()
+	pk.Marshal(protocol.NewReader(buf, shieldID, false))
+	return pk, nil
+}
+
+// Writer writes packets to a capture. A Writer may be used concurrently.
+type Writer struct {
+	mu       sync.Mutex
+	w        *bufio.Writer
+	shieldID int32
+	err      error
+}
+
+// NewWriter returns a new Writer writing a capture to the io.Writer passed. Shield items in packets are encoded
+// using the shield ID passed.
+func NewWriter(w io.Writer, shieldID int32) (*Writer, error) {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(magic); err != nil {
+		return nil, fmt.Errorf("failed to write capture header: %v", err)
+	}
+	return &Writer{w: bw, shieldID: shieldID}, nil
+}
+
+// Write encodes and writes the packet passed, which was sent in the direction passed at the time passed.
+func (w *Writer) Write(dir Direction, t time.Time, pk packet.Packet) error {
+	data, err := encode(pk, w.shieldID)
+	if err != nil {
+		return err
+	}
+	return w.writeRecord(Record{Direction: dir, Time: t, Data: data})
+}
+
+// writeRecord writes a record holding an encoded packet.
+func (w *Writer) writeRecord(r Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+
+	var prefix [13]byte
+	prefix[0] = byte(r.Direction)
+	binary.LittleEndian.PutUint64(prefix[1:9], uint64(r.Time.UnixNano()))
+	binary.LittleEndian.PutUint32(prefix[9:], uint32(len(r.Data)))
+	if _, err := w.w.Write(prefix[:]); err != nil {
+		w.err = err
+		return err
+	}
+	if _, err := w.w.Write(r.Data); err != nil {
+		w.err = err
+		return err
+	}
+	return nil
+}
+
+// encode encodes the packet passed, including its header, encoding shield items using the shield ID passed.
+func encode(pk packet.Packet, shieldID int32) ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, 64))
+	header := packet.Header{PacketID: pk.ID()}
+	if err := header.Write(buf); err != nil {
+		return nil, err
+	}
+	pk.Marshal(protocol.NewWriter(buf, shieldID))
+	return buf.Bytes(), nil
+}
+
+// Flush writes any buffered records to the underlying io.Writer.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Flush()
+}
+
+// Close flushes the Writer. Packets written after the call are not written to the underlying io.Writer.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil {
+		return nil
+	}
+	w.err = errors.New("capture closed")
+	return w.w.Flush()
+}
+
+// Reader reads records from a capture.
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader returns a new Reader reading a capture from the io.Reader passed. An error is returned if the
+// data read is not a capture.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(br, header); err != nil || !bytes.Equal(header, magic) {
+		return nil, errors.New("not a packet capture")
+	}
+	return &Reader{r: br}, nil
+}
+
+// Next reads the next record of the capture. io.EOF is returned once all records were read.
+func (r *Reader) Next() (Record, error) {
+	var prefix [13]byte
+	if _, err := io.ReadFull(r.r, prefix[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return Record{}, fmt.Errorf("truncated record: %v", err)
+		}
+		return Record{}, err
+	}
+	data := make([]byte, binary.LittleEndian.Uint32(prefix[9:]))
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return Record{}, fmt.Errorf("truncated record: %v", err)
+	}
+	return Record{
+		Direction: Direction(prefix[0]),
+		Time:      time.Unix(0, int64(binary.LittleEndian.Uint64(prefix[1:9]))),
+		Data:      data,
+	}, nil
+}
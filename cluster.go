@@ -0,0 +1,41 @@
+package spectrum
+
+import (
+	"github.com/spectrum-proxy/spectrum/cluster"
+	"net/http"
+	"time"
+)
+
+const (
+	// clusterInterval is the interval at which the players of the proxy are announced to the cluster.
+	clusterInterval = time.Second * 5
+	// clusterTTL is the time after which the announcement of a proxy expires if it is not renewed.
+	clusterTTL = clusterInterval * 3
+)
+
+// joinCluster joins the cluster configured in the Opts of the proxy, if any, sharing the presence of players
+// with its peers.
+func (s *Spectrum) joinCluster() {
+	if s.opts.ClusterID == "" {
+		return
+	}
+
+	store := cluster.NewGossipStore(s.opts.ClusterPeers, []byte(s.opts.ClusterSecret), clusterTTL)
+	s.cluster = cluster.New(s.logger, cluster.Proxy{ID: s.opts.ClusterID, Addr: s.opts.ClusterAddr}, store, s.registry)
+	if s.opts.ClusterListenAddr != "" && s.opts.ClusterSecret == "" {
+		s.logger.Error("Not serving cluster, as no cluster secret is configured")
+	} else if s.opts.ClusterListenAddr != "" {
+		s.gossip = &http.Server{Addr: s.opts.ClusterListenAddr, Handler: store.Handler()}
+		go func() {
+			if err := s.gossip.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				s.logger.Error("Failed to serve cluster", "err", err)
+			}
+		}()
+	}
+	go s.cluster.Run(clusterInterval, s.closed)
+}
+
+// Cluster returns the cluster the proxy is part of, or nil if clustering is not enabled.
+func (s *Spectrum) Cluster() *cluster.Cluster {
+	return s.cluster
+}
@@ -0,0 +1,131 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"github.com/spectrum-proxy/spectrum/session"
+	"log/slog"
+	"net"
+	"strconv"
+	"time"
+)
+
+// Cluster connects a proxy to the other proxies of a network, sharing the presence of players through a
+// Store so that players can be looked up and joined across proxies.
+type Cluster struct {
+	logger   *slog.Logger
+	proxy    Proxy
+	store    Store
+	registry *session.Registry
+}
+
+// New returns a new Cluster announcing the sessions in the registry passed as players of the proxy passed.
+func New(logger *slog.Logger, proxy Proxy, store Store, registry *session.Registry) *Cluster {
+	return &Cluster{logger: logger, proxy: proxy, store: store, registry: registry}
+}
+
+// Proxy returns the proxy the Cluster announces players for.
+func (c *Cluster) Proxy() Proxy {
+	return c.proxy
+}
+
+// Store returns the Store presence is shared through.
+func (c *Cluster) Store() Store {
+	return c.store
+}
+
+// Run announces the players of the proxy at the interval passed until the channel passed is closed. The
+// interval should be well below the ttl of the Store, so that announcements never expire while the proxy is
+// running.
+func (c *Cluster) Run(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Announce(); err != nil {
+			c.logger.Error("Failed to announce players", "err", err)
+		}
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Announce announces all players currently connected to the proxy to the Store.
+func (c *Cluster) Announce() error {
+	sessions := c.registry.Sessions()
+	players := make([]Presence, 0, len(sessions))
+	for _, s := range sessions {
+		players = append(players, c.presence(s))
+	}
+	return c.store.Announce(c.proxy, players)
+}
+
+// Find looks up a player by its XUID or name on any proxy of the cluster. Players connected to this proxy are
+// found immediately, even if they have not been announced yet.
+func (c *Cluster) Find(player string) (Presence, bool, error) {
+	if s := c.registry.Lookup(player); s != nil {
+		return c.presence(s), true, nil
+	}
+	return c.store.Player(player)
+}
+
+// presence returns the Presence of the player of the session passed, which is connected to this proxy.
+func (c *Cluster) presence(s *session.Session) Presence {
+	identity := s.Client().IdentityData()
+	return Presence{
+		XUID:    identity.XUID,
+		Name:    identity.DisplayName,
+		Proxy:   c.proxy.ID,
+		Server:  s.ServerAddr(),
+		Latency: s.Latency(),
+	}
+}
+
+// IsOnline returns true if the player with the XUID or name passed is connected to any proxy of the cluster.
+func (c *Cluster) IsOnline(player string) (bool, error) {
+	_, ok, err := c.Find(player)
+	return ok, err
+}
+
+// Transfer moves the session passed to the proxy with the ID passed using session.Session.TransferExternal.
+func (c *Cluster) Transfer(s *session.Session, proxyID string) error {
+	proxies, err := c.store.Proxies()
+	if err != nil {
+		return fmt.Errorf("failed to list proxies: %v", err)
+	}
+	for _, proxy := range proxies {
+		if proxy.ID != proxyID {
+			continue
+		}
+
+		host, portStr, err := net.SplitHostPort(proxy.Addr)
+		if err != nil {
+			return fmt.Errorf("invalid address of proxy %v: %v", proxyID, err)
+		}
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid port of proxy %v: %v", proxyID, err)
+		}
+		return s.TransferExternal(host, uint16(port))
+	}
+	return fmt.Errorf("proxy %v not found", proxyID)
+}
+
+// Join moves the session passed to the server of the player passed. If the player is connected to another
+// proxy, the session is transferred to that proxy instead, which decides the server it is connected to.
+func (c *Cluster) Join(s *session.Session, player string) error {
+	presence, ok, err := c.Find(player)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("player %v is not online", player)
+	}
+	if presence.Proxy != c.proxy.ID {
+		return c.Transfer(s, presence.Proxy)
+	}
+	return s.Transfer(context.Background(), presence.Server)
+}
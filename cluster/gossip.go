@@ -0,0 +1,163 @@
+package cluster
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// gossipSignatureHeader is the header holding the hex encoded HMAC-SHA256 of an announcement.
+	gossipSignatureHeader = "X-Spectrum-Signature"
+	// gossipTimestampHeader is the header holding the time an announcement was sent at, in Unix milliseconds.
+	gossipTimestampHeader = "X-Spectrum-Timestamp"
+	// gossipMaxSkew is the maximum difference between the time an announcement was sent at and the time it is
+	// received at. Announcements outside of it are rejected as stale.
+	gossipMaxSkew = time.Second * 30
+	// gossipMaxSize is the maximum size of an announcement in bytes.
+	gossipMaxSize = 4 << 20
+)
+
+// GossipStore is a Store that shares presence by pushing the announcements of the proxy directly to its peers
+// over HTTP, without needing an external database. Every proxy in the cluster must serve the Handler of its
+// GossipStore and list the other proxies as peers. Announcements are signed with a secret shared by all
+// proxies in the cluster, and announcements that are not signed with it are rejected.
+type GossipStore struct {
+	peers  []string
+	secret []byte
+	client *http.Client
+	memory *MemoryStore
+}
+
+// NewGossipStore returns a new GossipStore pushing announcements to the peers passed, which are the base URLs
+// the Handlers of the other proxies are served on, such as "http://10.0.0.2:19140". Announcements are signed
+// with the secret passed and expire after the ttl passed. If the secret is empty, the Handler rejects all
+// announcements.
+func NewGossipStore(peers []string, secret []byte, ttl time.Duration) *GossipStore {
+	return &GossipStore{
+		peers:  peers,
+		secret: secret,
+		client: &http.Client{Timeout: time.Second * 5},
+		memory: NewMemoryStore(ttl),
+	}
+}
+
+// gossipAnnouncement is the JSON body of an announcement pushed to a peer.
+type gossipAnnouncement struct {
+	Proxy   Proxy      `json:"proxy"`
+	Players []Presence `json:"players"`
+}
+
+// Announce stores the announcement and pushes it to all peers. Announce returns an error if any peer could not
+// be reached, but still pushes the announcement to the remaining peers.
+func (s *GossipStore) Announce(proxy Proxy, players []Presence) error {
+	_ = s.memory.Announce(proxy, players)
+
+	body, err := json.Marshal(gossipAnnouncement{Proxy: proxy, Players: players})
+	if err != nil {
+		return fmt.Errorf("failed to encode announcement: %v", err)
+	}
+
+	var failed error
+	for _, peer := range s.peers {
+		if err := s.push(peer, body); err != nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+// push pushes an encoded announcement to the peer passed.
+func (s *GossipStore) push(peer string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, peer+"/cluster/announce", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to announce to %v: %v", peer, err)
+	}
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(gossipTimestampHeader, timestamp)
+	req.Header.Set(gossipSignatureHeader, hex.EncodeToString(s.sign(timestamp, body)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to announce to %v: %v", peer, err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to announce to %v: unexpected status %v", peer, resp.Status)
+	}
+	return nil
+}
+
+// Player ...
+func (s *GossipStore) Player(player string) (Presence, bool, error) {
+	return s.memory.Player(player)
+}
+
+// Proxies ...
+func (s *GossipStore) Proxies() ([]Proxy, error) {
+	return s.memory.Proxies()
+}
+
+// Handler returns the http.Handler receiving the announcements of peers. Announcements that are not signed
+// with the secret of the GossipStore, or that were sent too long ago, are rejected.
+func (s *GossipStore) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /cluster/announce", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, gossipMaxSize))
+		if err != nil {
+			http.Error(w, "invalid announcement", http.StatusBadRequest)
+			return
+		}
+		if !s.verify(r.Header, body) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		var a gossipAnnouncement
+		if err := json.Unmarshal(body, &a); err != nil || a.Proxy.ID == "" {
+			http.Error(w, "invalid announcement", http.StatusBadRequest)
+			return
+		}
+		_ = s.memory.Announce(a.Proxy, a.Players)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
+
+// sign returns the HMAC-SHA256 of the announcement passed, sent at the timestamp passed.
+func (s *GossipStore) sign(timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'\n'})
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// verify checks if the announcement passed is signed with the secret of the GossipStore and was sent recently,
+// using the signature and timestamp in the headers passed.
+func (s *GossipStore) verify(header http.Header, body []byte) bool {
+	if len(s.secret) == 0 {
+		return false
+	}
+	timestamp := header.Get(gossipTimestampHeader)
+	millis, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if skew := time.Since(time.UnixMilli(millis)); skew > gossipMaxSkew || skew < -gossipMaxSkew {
+		return false
+	}
+	signature, err := hex.DecodeString(header.Get(gossipSignatureHeader))
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(signature, s.sign(timestamp, body))
+}
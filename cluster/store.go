@@ -0,0 +1,104 @@
+package cluster
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Proxy describes a proxy in the cluster.
+type Proxy struct {
+	// ID uniquely identifies the proxy in the cluster.
+	ID string `json:"id"`
+	// Addr is the public address players connect to the proxy on, in the form host:port.
+	Addr string `json:"addr"`
+}
+
+// Presence describes a player connected to a proxy in the cluster.
+type Presence struct {
+	XUID string `json:"xuid"`
+	Name string `json:"name"`
+	// Proxy is the ID of the proxy the player is connected to.
+	Proxy string `json:"proxy"`
+	// Server is the address of the server the player is connected to.
+	Server string `json:"server"`
+	// Latency is the latency of the player in milliseconds at the time the player was announced.
+	Latency int64 `json:"latency"`
+}
+
+// Store shares the presence of players between the proxies of a cluster. Implementations may be backed by
+// an external database such as Redis, or share presence between the proxies directly like GossipStore.
+type Store interface {
+	// Announce records the players connected to the proxy passed, replacing all players previously announced
+	// for it. Proxies that stop announcing are removed from the store once their announcement expires.
+	Announce(proxy Proxy, players []Presence) error
+	// Player looks up the presence of a player by its XUID or name.
+	Player(player string) (Presence, bool, error)
+	// Proxies returns all proxies currently in the cluster.
+	Proxies() ([]Proxy, error)
+}
+
+// announcement holds the players announced by a proxy.
+type announcement struct {
+	proxy   Proxy
+	players []Presence
+	expiry  time.Time
+}
+
+// MemoryStore is a Store that keeps the presence of players in memory. It is only shared between proxies in
+// the same process, but is used by GossipStore to hold the announcements of its peers.
+type MemoryStore struct {
+	ttl time.Duration
+
+	mu            sync.RWMutex
+	announcements map[string]announcement
+}
+
+// NewMemoryStore returns a new MemoryStore in which announcements expire after the ttl passed.
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{ttl: ttl, announcements: make(map[string]announcement)}
+}
+
+// Announce ...
+func (s *MemoryStore) Announce(proxy Proxy, players []Presence) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.announcements[proxy.ID] = announcement{proxy: proxy, players: players, expiry: time.Now().Add(s.ttl)}
+	return nil
+}
+
+// Player ...
+func (s *MemoryStore) Player(player string) (Presence, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	for _, a := range s.announcements {
+		if now.After(a.expiry) {
+			continue
+		}
+		for _, p := range a.players {
+			if p.XUID == player || strings.EqualFold(p.Name, player) {
+				return p, true, nil
+			}
+		}
+	}
+	return Presence{}, false, nil
+}
+
+// Proxies ...
+func (s *MemoryStore) Proxies() ([]Proxy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	proxies := make([]Proxy, 0, len(s.announcements))
+	for id, a := range s.announcements {
+		if now.After(a.expiry) {
+			delete(s.announcements, id)
+			continue
+		}
+		proxies = append(proxies, a.proxy)
+	}
+	return proxies, nil
+}
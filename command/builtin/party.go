@@ -0,0 +1,82 @@
+package builtin
+
+import (
+	"fmt"
+	"github.com/spectrum-proxy/spectrum/command"
+	"github.com/spectrum-proxy/spectrum/party"
+	"github.com/spectrum-proxy/spectrum/session"
+	"strings"
+)
+
+// Party returns the /party command, which manages the party of the player executing it using the Manager
+// passed. Players to invite or join are looked up in the registry passed.
+func Party(parties *party.Manager, registry *session.Registry) command.Command {
+	params := []command.Parameter{
+		{Name: "action", Type: command.ParameterTypeEnum, Options: []string{"create", "invite", "accept", "leave", "disband", "list", "chat"}},
+		{Name: "argument", Type: command.ParameterTypeText, Optional: true},
+	}
+	return command.New("party", "Manage your party", []string{"p"}, params, func(src command.Source, args command.Arguments) error {
+		s, ok := src.(*session.Session)
+		if !ok {
+			return fmt.Errorf("this command can only be executed by players")
+		}
+
+		switch args.String("action") {
+		case "create":
+			if _, err := parties.Create(s); err != nil {
+				return err
+			}
+			src.SendMessage("You created a party. Invite players using /party invite <player>.")
+		case "invite":
+			target := registry.Lookup(args.String("argument"))
+			if target == nil {
+				return fmt.Errorf("%v is not online", args.String("argument"))
+			}
+			if err := parties.Invite(s, target); err != nil {
+				return err
+			}
+			name := s.Client().IdentityData().DisplayName
+			target.SendMessage(fmt.Sprintf("%v invited you to their party. Join it using /party accept %v.", name, name))
+			src.SendMessage("Invited " + target.Client().IdentityData().DisplayName + " to your party.")
+		case "accept":
+			leader := registry.Lookup(args.String("argument"))
+			if leader == nil {
+				return fmt.Errorf("%v is not online", args.String("argument"))
+			}
+			p, err := parties.Join(s, leader)
+			if err != nil {
+				return err
+			}
+			p.Broadcast(fmt.Sprintf("§d[Party]§r %v joined the party.", s.Client().IdentityData().DisplayName))
+		case "leave":
+			p := parties.Of(s)
+			if err := parties.Leave(s); err != nil {
+				return err
+			}
+			src.SendMessage("You left the party.")
+			p.Broadcast(fmt.Sprintf("§d[Party]§r %v left the party.", s.Client().IdentityData().DisplayName))
+		case "disband":
+			p := parties.Of(s)
+			if p == nil {
+				return fmt.Errorf("you are not in a party")
+			}
+			members := p.Members()
+			if err := parties.Disband(s); err != nil {
+				return err
+			}
+			for _, member := range members {
+				member.SendMessage("§d[Party]§r The party was disbanded.")
+			}
+		case "list":
+			p := parties.Of(s)
+			if p == nil {
+				return fmt.Errorf("you are not in a party")
+			}
+			info := p.Info()
+			src.SendMessage(fmt.Sprintf("Party of %v: %v", info.Leader, strings.Join(info.Members, ", ")))
+		case "chat":
+			return parties.Chat(s, args.String("argument"))
+		}
+		return nil
+	})
+}
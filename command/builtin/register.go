@@ -0,0 +1,20 @@
+package builtin
+
+import (
+	"github.com/spectrum-proxy/spectrum/command"
+	"github.com/spectrum-proxy/spectrum/party"
+	"github.com/spectrum-proxy/spectrum/server"
+	"github.com/spectrum-proxy/spectrum/session"
+)
+
+// Register registers all builtin commands with the command package, so that they may be executed by players.
+// The commands use the server registry, health checker, party manager and session registry passed. health may
+// be nil.
+func Register(servers *server.Registry, health session.HealthChecker, parties *party.Manager, registry *session.Registry) {
+	command.Register(Server(servers, health))
+	command.Register(Party(parties, registry))
+	command.Register(Msg(registry))
+	command.Register(Reply())
+	command.Register(Ignore(registry))
+	command.Register(Stats())
+}
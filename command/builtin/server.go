@@ -0,0 +1,61 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spectrum-proxy/spectrum/command"
+	"github.com/spectrum-proxy/spectrum/server"
+	"github.com/spectrum-proxy/spectrum/session"
+	"strings"
+)
+
+// Server returns the /server command, which transfers the player executing it to one of the servers in the
+// registry passed. Without arguments, it lists the servers available. If health is non-nil, servers it
+// reports as down are marked as such and players are not transferred to them.
+func Server(servers *server.Registry, health session.HealthChecker) command.Command {
+	params := []command.Parameter{{
+		Name:     "name",
+		Type:     command.ParameterTypeString,
+		Optional: true,
+		Suggest:  servers.Names,
+	}}
+	return command.New("server", "Transfer to another server", nil, params, func(src command.Source, args command.Arguments) error {
+		if !args.Has("name") {
+			names := servers.Names()
+			for i, name := range names {
+				if health != nil && !health.Healthy(servers.Resolve(name)) {
+					names[i] = name + " (down)"
+				}
+			}
+			src.SendMessage("Available servers: " + strings.Join(names, ", "))
+			return nil
+		}
+
+		s, ok := src.(*session.Session)
+		if !ok {
+			return fmt.Errorf("this command can only be executed by players")
+		}
+
+		name := args.String("name")
+		addr, ok := servers.Lookup(name)
+		if !ok {
+			return fmt.Errorf("unknown server %v", name)
+		}
+
+		if health != nil && !health.Healthy(addr) {
+			return fmt.Errorf("%v is currently down", name)
+		}
+		if s.ServerAddr() == addr {
+			return fmt.Errorf("you are already connected to %v", name)
+		}
+
+		src.SendMessage("Transferring to " + name + "...")
+		if err := s.Transfer(context.Background(), addr); errors.Is(err, session.ErrBackendFull) {
+			return fmt.Errorf("%v is full", name)
+		} else if err != nil {
+			return fmt.Errorf("failed to transfer to %v: %v", name, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,117 @@
+package builtin
+
+import (
+	"fmt"
+	"github.com/spectrum-proxy/spectrum/command"
+	"github.com/spectrum-proxy/spectrum/session"
+	"slices"
+	"strings"
+)
+
+// Msg returns the /msg command, which sends a private message to a player on any server, looked up in the
+// registry passed.
+func Msg(registry *session.Registry) command.Command {
+	params := []command.Parameter{
+		{Name: "player", Type: command.ParameterTypeString, Suggest: playerNames(registry)},
+		{Name: "message", Type: command.ParameterTypeText},
+	}
+	return command.New("msg", "Send a private message to a player", []string{"tell", "w", "whisper"}, params, func(src command.Source, args command.Arguments) error {
+		s, ok := src.(*session.Session)
+		if !ok {
+			return fmt.Errorf("this command can only be executed by players")
+		}
+
+		target := registry.Lookup(args.String("player"))
+		if target == nil {
+			return fmt.Errorf("%v is not online", args.String("player"))
+		}
+		return s.SendPrivateMessage(target, args.String("message"))
+	})
+}
+
+// Reply returns the /reply command, which sends a private message to the player that the player executing it
+// last exchanged a private message with.
+func Reply() command.Command {
+	params := []command.Parameter{{Name: "message", Type: command.ParameterTypeText}}
+	return command.New("reply", "Reply to the last private message", []string{"r"}, params, func(src command.Source, args command.Arguments) error {
+		s, ok := src.(*session.Session)
+		if !ok {
+			return fmt.Errorf("this command can only be executed by players")
+		}
+		return s.Reply(args.String("message"))
+	})
+}
+
+// Ignore returns the /ignore command, which toggles ignoring a player, looked up in the registry passed.
+// Without arguments, it lists the players ignored. Players that are offline may be unignored by their XUID.
+func Ignore(registry *session.Registry) command.Command {
+	params := []command.Parameter{{
+		Name:     "player",
+		Type:     command.ParameterTypeString,
+		Optional: true,
+		Suggest:  playerNames(registry),
+	}}
+	return command.New("ignore", "Ignore or unignore a player", nil, params, func(src command.Source, args command.Arguments) error {
+		s, ok := src.(*session.Session)
+		if !ok {
+			return fmt.Errorf("this command can only be executed by players")
+		}
+
+		ignored, err := s.Ignored()
+		if err != nil {
+			return fmt.Errorf("failed to look up ignored players: %v", err)
+		}
+		if !args.Has("player") {
+			if len(ignored) == 0 {
+				src.SendMessage("You are not ignoring anyone.")
+				return nil
+			}
+			names := make([]string, 0, len(ignored))
+			for _, xuid := range ignored {
+				if target := registry.GetSession(xuid); target != nil {
+					names = append(names, target.Client().IdentityData().DisplayName)
+				} else {
+					names = append(names, xuid)
+				}
+			}
+			src.SendMessage("Ignored players: " + strings.Join(names, ", "))
+			return nil
+		}
+
+		name, xuid := args.String("player"), args.String("player")
+		if target := registry.Lookup(name); target != nil {
+			name, xuid = target.Client().IdentityData().DisplayName, target.Client().IdentityData().XUID
+		} else if !slices.Contains(ignored, xuid) {
+			return fmt.Errorf("%v is not online", name)
+		}
+		if xuid == s.Client().IdentityData().XUID {
+			return fmt.Errorf("you cannot ignore yourself")
+		}
+
+		if slices.Contains(ignored, xuid) {
+			if err := s.Unignore(xuid); err != nil {
+				return fmt.Errorf("failed to unignore %v: %v", name, err)
+			}
+			src.SendMessage("You are no longer ignoring " + name + ".")
+			return nil
+		}
+		if err := s.Ignore(xuid); err != nil {
+			return fmt.Errorf("failed to ignore %v: %v", name, err)
+		}
+		src.SendMessage("You are now ignoring " + name + ".")
+		return nil
+	})
+}
+
+// playerNames returns a function returning the names of all players in the registry passed, used to suggest
+// players to the client.
+func playerNames(registry *session.Registry) func() []string {
+	return func() []string {
+		sessions := registry.Sessions()
+		names := make([]string, 0, len(sessions))
+		for _, s := range sessions {
+			names = append(names, s.Client().IdentityData().DisplayName)
+		}
+		return names
+	}
+}
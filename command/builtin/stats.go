@@ -0,0 +1,41 @@
+package builtin
+
+import (
+	"fmt"
+	"github.com/spectrum-proxy/spectrum/command"
+	"github.com/spectrum-proxy/spectrum/session"
+	"time"
+)
+
+// Stats returns the /stats command, which shows the player executing it their latency, the server they are on,
+// the time they have been connected and the traffic of their session.
+func Stats() command.Command {
+	return command.New("stats", "Show your connection statistics", []string{"ping"}, nil, func(src command.Source, _ command.Arguments) error {
+		s, ok := src.(*session.Session)
+		if !ok {
+			return fmt.Errorf("this command can only be executed by players")
+		}
+
+		stats := s.Stats()
+		src.SendMessage(fmt.Sprintf("Ping: %vms\nServer: %v\nConnected for: %v\nTransfers: %v\nReceived: %v packets (%v)\nSent: %v packets (%v)",
+			s.Latency(), stats.Server, stats.Uptime().Truncate(time.Second), stats.Transfers,
+			stats.Clientbound.Count, formatBytes(stats.Clientbound.Bytes),
+			stats.Serverbound.Count, formatBytes(stats.Serverbound.Bytes),
+		))
+		return nil
+	})
+}
+
+// formatBytes formats an amount of bytes using the largest unit that keeps the amount above one.
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
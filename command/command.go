@@ -0,0 +1,87 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Source is the source that executes a command. It is typically a session.Session.
+type Source interface {
+	// SendMessage sends a message to the source.
+	SendMessage(message string)
+}
+
+// Runnable is the function executed when a command is run. The arguments passed have already been parsed and
+// validated against the parameters of the command. If an error is returned, its message is sent to the
+// source that ran the command.
+type Runnable func(src Source, args Arguments) error
+
+// Command is a command handled by the proxy itself. Commands registered are shown to clients alongside the
+// commands of the server they are connected to, and are executed by the proxy without being forwarded to
+// the server.
+type Command struct {
+	name        string
+	description string
+	aliases     []string
+	params      []Parameter
+	runnable    Runnable
+}
+
+// New returns a new Command with the name, description, aliases and parameters passed. The runnable is called
+// whenever the command is executed.
+func New(name, description string, aliases []string, params []Parameter, runnable Runnable) Command {
+	return Command{
+		name:        strings.ToLower(name),
+		description: description,
+		aliases:     aliases,
+		params:      params,
+		runnable:    runnable,
+	}
+}
+
+// Name returns the name of the command.
+func (c Command) Name() string {
+	return c.name
+}
+
+// Description returns the description of the command.
+func (c Command) Description() string {
+	return c.description
+}
+
+// Aliases returns the aliases of the command.
+func (c Command) Aliases() []string {
+	return c.aliases
+}
+
+// Params returns the parameters of the command.
+func (c Command) Params() []Parameter {
+	return c.params
+}
+
+// Usage returns the usage of the command, such as "/server <name>".
+func (c Command) Usage() string {
+	usage := "/" + c.name
+	for _, param := range c.params {
+		if param.Optional {
+			usage += " [" + param.Name + "]"
+		} else {
+			usage += " <" + param.Name + ">"
+		}
+	}
+	return usage
+}
+
+// Execute parses the arguments passed and runs the command for the source. Any error that occurs while
+// parsing or running the command is sent to the source.
+func (c Command) Execute(src Source, args string) {
+	arguments, err := parseArguments(c.params, args)
+	if err != nil {
+		src.SendMessage(fmt.Sprintf("§c%v. Usage: %v", err, c.Usage()))
+		return
+	}
+
+	if err := c.runnable(src, arguments); err != nil {
+		src.SendMessage("§c" + err.Error())
+	}
+}
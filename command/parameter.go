@@ -0,0 +1,119 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParameterType is the type of a Parameter.
+type ParameterType int
+
+const (
+	// ParameterTypeString is a single word, or multiple words enclosed in double quotes.
+	ParameterTypeString ParameterType = iota
+	// ParameterTypeInt is a whole number.
+	ParameterTypeInt
+	// ParameterTypeText consumes the rest of the command line. It must be the last parameter of a command.
+	ParameterTypeText
+	// ParameterTypeEnum is a single word that must be one of the options of the parameter.
+	ParameterTypeEnum
+)
+
+// Parameter is a parameter of a Command.
+type Parameter struct {
+	// Name is the name of the parameter, which is shown to the client and used to look up its value in
+	// Arguments.
+	Name string
+	// Type is the type of the parameter.
+	Type ParameterType
+	// Optional specifies if the parameter may be omitted.
+	Optional bool
+	// Options holds the values accepted by a parameter of the type ParameterTypeEnum.
+	Options []string
+	// Suggest, if set, returns values that are suggested to the client when typing a parameter of the type
+	// ParameterTypeString. Unlike Options, values other than those suggested are still accepted.
+	Suggest func() []string
+}
+
+// Arguments holds the arguments a command was executed with, keyed by the name of their parameter.
+type Arguments struct {
+	values map[string]string
+}
+
+// Has returns true if a value was passed for the parameter with the name passed.
+func (a Arguments) Has(name string) bool {
+	_, ok := a.values[name]
+	return ok
+}
+
+// String returns the value of the parameter with the name passed, or an empty string if it was not passed.
+func (a Arguments) String(name string) string {
+	return a.values[name]
+}
+
+// Int returns the value of the parameter with the name passed as an int, or 0 if it was not passed.
+func (a Arguments) Int(name string) int {
+	v, _ := strconv.Atoi(a.values[name])
+	return v
+}
+
+// parseArguments parses the argument line passed according to the parameters passed.
+func parseArguments(params []Parameter, line string) (Arguments, error) {
+	args := Arguments{values: make(map[string]string, len(params))}
+	line = strings.TrimSpace(line)
+	for _, param := range params {
+		if line == "" {
+			if !param.Optional {
+				return args, fmt.Errorf("missing argument %v", param.Name)
+			}
+			continue
+		}
+
+		var value string
+		if param.Type == ParameterTypeText {
+			value, line = line, ""
+		} else {
+			value, line = nextArgument(line)
+		}
+
+		switch param.Type {
+		case ParameterTypeInt:
+			if _, err := strconv.Atoi(value); err != nil {
+				return args, fmt.Errorf("argument %v must be a number", param.Name)
+			}
+		case ParameterTypeEnum:
+			found := false
+			for _, option := range param.Options {
+				if strings.EqualFold(option, value) {
+					value, found = option, true
+					break
+				}
+			}
+			if !found {
+				return args, fmt.Errorf("invalid value %v for argument %v", value, param.Name)
+			}
+		}
+		args.values[param.Name] = value
+	}
+
+	if line != "" {
+		return args, fmt.Errorf("too many arguments")
+	}
+	return args, nil
+}
+
+// nextArgument splits the next argument off the line passed. Arguments enclosed in double quotes may contain
+// spaces.
+func nextArgument(line string) (string, string) {
+	if strings.HasPrefix(line, "\"") {
+		if end := strings.Index(line[1:], "\""); end != -1 {
+			return line[1 : end+1], strings.TrimSpace(line[end+2:])
+		}
+	}
+
+	if i := strings.IndexByte(line, ' '); i != -1 {
+		return line[:i], strings.TrimSpace(line[i+1:])
+	}
+	return line, ""
+}
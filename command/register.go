@@ -0,0 +1,160 @@
+package command
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"math"
+	"strings"
+	"sync"
+)
+
+var (
+	commandsMu sync.RWMutex
+	// commands holds all registered commands, keyed by their name and aliases.
+	commands = map[string]Command{}
+)
+
+// Register registers a command so that it is handled by the proxy. Registering a command with a name or alias
+// that is already taken overwrites the existing command.
+func Register(c Command) {
+	commandsMu.Lock()
+	defer commandsMu.Unlock()
+
+	commands[c.name] = c
+	for _, alias := range c.aliases {
+		commands[strings.ToLower(alias)] = c
+	}
+}
+
+// Unregister removes the command with the name passed, including all of its aliases.
+func Unregister(name string) {
+	commandsMu.Lock()
+	defer commandsMu.Unlock()
+
+	c, ok := commands[strings.ToLower(name)]
+	if !ok {
+		return
+	}
+
+	delete(commands, c.name)
+	for _, alias := range c.aliases {
+		delete(commands, strings.ToLower(alias))
+	}
+}
+
+// ByAlias looks up a command by its name or one of its aliases.
+func ByAlias(alias string) (Command, bool) {
+	commandsMu.RLock()
+	defer commandsMu.RUnlock()
+
+	c, ok := commands[strings.ToLower(alias)]
+	return c, ok
+}
+
+// Commands returns all registered commands, keyed by their name.
+func Commands() map[string]Command {
+	commandsMu.RLock()
+	defer commandsMu.RUnlock()
+
+	m := make(map[string]Command, len(commands))
+	for _, c := range commands {
+		m[c.name] = c
+	}
+	return m
+}
+
+// Inject adds all registered commands to the AvailableCommands packet passed. Commands of the server whose
+// name or aliases clash with the name or aliases of a registered command are replaced.
+func Inject(pk *packet.AvailableCommands) {
+	registered := Commands()
+	if len(registered) == 0 {
+		return
+	}
+
+	filtered := pk.Commands[:0]
+	for _, c := range pk.Commands {
+		if !clashes(pk, c) {
+			filtered = append(filtered, c)
+		}
+	}
+	pk.Commands = filtered
+
+	for _, c := range registered {
+		aliasesOffset := uint32(math.MaxUint32)
+		if len(c.aliases) > 0 {
+			aliasesOffset = addEnum(pk, c.name+"Aliases", append([]string{c.name}, c.aliases...))
+		}
+
+		params := make([]protocol.CommandParameter, 0, len(c.params))
+		for _, param := range c.params {
+			params = append(params, protocol.CommandParameter{
+				Name:     param.Name,
+				Type:     parameterType(pk, c, param),
+				Optional: param.Optional,
+			})
+		}
+
+		pk.Commands = append(pk.Commands, protocol.Command{
+			Name:          c.name,
+			Description:   c.description,
+			AliasesOffset: aliasesOffset,
+			Overloads:     []protocol.CommandOverload{{Parameters: params}},
+		})
+	}
+}
+
+// clashes checks if the name or one of the aliases of the command of the server passed is the name or an alias
+// of a registered command.
+func clashes(pk *packet.AvailableCommands, c protocol.Command) bool {
+	if _, ok := ByAlias(c.Name); ok {
+		return true
+	}
+	if int(c.AliasesOffset) >= len(pk.Enums) {
+		return false
+	}
+	for _, i := range pk.Enums[c.AliasesOffset].ValueIndices {
+		if int(i) >= len(pk.EnumValues) {
+			continue
+		}
+		if _, ok := ByAlias(pk.EnumValues[i]); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parameterType returns the protocol type of the parameter passed, adding an enum to the packet if needed.
+func parameterType(pk *packet.AvailableCommands, c Command, param Parameter) uint32 {
+	switch param.Type {
+	case ParameterTypeInt:
+		return protocol.CommandArgValid | protocol.CommandArgTypeInt
+	case ParameterTypeText:
+		return protocol.CommandArgValid | protocol.CommandArgTypeRawText
+	case ParameterTypeEnum:
+		return protocol.CommandArgValid | protocol.CommandArgEnum | addEnum(pk, c.name+param.Name, param.Options)
+	default:
+		if param.Suggest != nil {
+			pk.DynamicEnums = append(pk.DynamicEnums, protocol.DynamicEnum{
+				Type:   c.name + param.Name,
+				Values: param.Suggest(),
+			})
+			return protocol.CommandArgValid | protocol.CommandArgSoftEnum | uint32(len(pk.DynamicEnums)-1)
+		}
+		return protocol.CommandArgValid | protocol.CommandArgTypeString
+	}
+}
+
+// addEnum adds an enum with the type and values passed to the packet and returns its index.
+func addEnum(pk *packet.AvailableCommands, typ string, values []string) uint32 {
+	indices := make([]uint, 0, len(values))
+	for _, value := range values {
+		indices = append(indices, uint(len(pk.EnumValues)))
+		pk.EnumValues = append(pk.EnumValues, value)
+	}
+
+	pk.Enums = append(pk.Enums, protocol.CommandEnum{
+		Type:         typ,
+		ValueIndices: indices,
+	})
+	return uint32(len(pk.Enums) - 1)
+}
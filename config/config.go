@@ -0,0 +1,150 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+)
+
+// Config is the configuration of a proxy as read from a YAML file. It groups the options of the proxy most
+// commonly configured into sections, and may be converted to spectrum.Opts using Apply.
+type Config struct {
+	// Listener configures the listener players connect to.
+	Listener Listener `yaml:"listener"`
+	// Servers configures the servers behind the proxy.
+	Servers Servers `yaml:"servers"`
+	// Transfer configures how players are transferred between servers.
+	Transfer Transfer `yaml:"transfer"`
+	// RateLimit configures the limits on connections and packets of players.
+	RateLimit RateLimit `yaml:"rate_limit"`
+	// API configures the API servers connect to and the Admin API.
+	API API `yaml:"api"`
+}
+
+// Listener configures the listener players connect to.
+type Listener struct {
+	// Addr is the address to listen on.
+	Addr string `yaml:"addr"`
+	// MOTD holds the lines shown in the server list, rotating every few seconds.
+	MOTD []string `yaml:"motd"`
+	// MOTDFull holds the lines shown in the server list instead of the MOTD while the proxy is full.
+	MOTDFull []string `yaml:"motd_full"`
+	// MaxPlayers is the maximum amount of players on the proxy, including the PrioritySlots. If zero, there is
+	// no limit.
+	MaxPlayers int `yaml:"max_players"`
+	// PrioritySlots is the amount of the MaxPlayers slots reserved for PriorityPlayers.
+	PrioritySlots int `yaml:"priority_slots"`
+	// PriorityPlayers holds the XUIDs or display names of players that may use the reserved PrioritySlots.
+	PriorityPlayers []string `yaml:"priority_players"`
+	// KickPolicy is the policy used when a priority player joins while the proxy is full: "newest", "oldest"
+	// or empty to reject the player.
+	KickPolicy string `yaml:"kick_policy"`
+	// MultiVersion accepts clients of all protocol versions supported by the proxy.
+	MultiVersion bool `yaml:"multi_version"`
+}
+
+// Servers configures the servers behind the proxy.
+type Servers struct {
+	// Addresses maps the names of servers to their addresses.
+	Addresses map[string]string `yaml:"addresses"`
+	// Default is the name or address of the server players join when connecting to the proxy.
+	Default string `yaml:"default"`
+	// Capacities holds the maximum amount of players of servers, keyed by the name or address of the server.
+	Capacities map[string]int `yaml:"capacities"`
+	// QueueFallback is the name or address of the server players wait on while queued for a full server.
+	QueueFallback string `yaml:"queue_fallback"`
+	// HealthCheckInterval is the interval at which servers are checked for reachability in milliseconds. If
+	// zero, servers are not checked.
+	HealthCheckInterval int64 `yaml:"health_check_interval"`
+	// HealthCheckThreshold is the amount of consecutive failed checks after which a server is marked down.
+	HealthCheckThreshold int `yaml:"health_check_threshold"`
+	// Limbo enables the limbo server players are parked in when no server is available.
+	Limbo bool `yaml:"limbo"`
+	// Secret is the secret shared with servers that connections to them are signed with. Changing it and
+	// reloading the config rotates the secret.
+	Secret string `yaml:"secret"`
+	// Secrets holds secrets used instead of the Secret for specific servers, keyed by the name or address of
+	// the server.
+	Secrets map[string]string `yaml:"secrets"`
+}
+
+// Transfer configures how players are transferred between servers.
+type Transfer struct {
+	// Retries is the amount of times a failed transfer is retried.
+	Retries int `yaml:"retries"`
+	// Backoff is the time in milliseconds waited before the first retry, doubling with every retry.
+	Backoff int64 `yaml:"backoff"`
+	// DialTimeout is the maximum time in milliseconds connecting to a server may take.
+	DialTimeout int64 `yaml:"dial_timeout"`
+	// LoginTimeout is the maximum time in milliseconds logging in to a server may take.
+	LoginTimeout int64 `yaml:"login_timeout"`
+	// Animation is the name of the animation played when players are transferred.
+	Animation string `yaml:"animation"`
+	// Seamless enables transferring players between servers in the same dimension without an animation.
+	Seamless bool `yaml:"seamless"`
+}
+
+// RateLimit configures the limits on connections and packets of players.
+type RateLimit struct {
+	// LoginRate is the amount of connections accepted per second from a single IP address. If zero,
+	// connections are not limited per IP address.
+	LoginRate float64 `yaml:"login_rate"`
+	// LoginBurst is the amount of connections accepted from a single IP address in quick succession.
+	LoginBurst int `yaml:"login_burst"`
+	// GlobalLoginRate is the total amount of connections accepted per second. If zero, the total amount of
+	// connections is not limited.
+	GlobalLoginRate float64 `yaml:"global_login_rate"`
+	// GlobalLoginBurst is the total amount of connections accepted in quick succession.
+	GlobalLoginBurst int `yaml:"global_login_burst"`
+}
+
+// API configures the API servers connect to and the Admin API. Both are started by proxy.Proxy, but not by
+// spectrum.Spectrum itself.
+type API struct {
+	// Addr is the address the API servers connect to listens on. If empty, the API is disabled.
+	Addr string `yaml:"addr"`
+	// AdminAddr is the address the Admin API listens on. If empty, the Admin API is disabled.
+	AdminAddr string `yaml:"admin_addr"`
+	// AdminToken is the bearer token required by the Admin API.
+	AdminToken string `yaml:"admin_token"`
+}
+
+// Default returns the Config with the default values of all options.
+func Default() Config {
+	return Config{
+		Listener: Listener{Addr: ":19132"},
+		Transfer: Transfer{
+			Retries:      2,
+			Backoff:      250,
+			DialTimeout:  5000,
+			LoginTimeout: 10000,
+		},
+	}
+}
+
+// Load reads the Config from the YAML file at the path passed. Options missing from the file keep their
+// default values, while unknown options are rejected. The Config returned is validated.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("read config: %w", err)
+	}
+	return Decode(data)
+}
+
+// Decode decodes the Config from the YAML data passed like Load.
+func Decode(data []byte) (Config, error) {
+	conf := Default()
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&conf); err != nil && !errors.Is(err, io.EOF) {
+		return Config{}, fmt.Errorf("decode config: %w", err)
+	}
+	if err := conf.Validate(); err != nil {
+		return Config{}, err
+	}
+	return conf, nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import "github.com/spectrum-proxy/spectrum"
+
+// Apply sets the options of the spectrum.Opts passed covered by the Config to its values. Options not covered
+// by the Config are left unchanged.
+func (c Config) Apply(opts *spectrum.Opts) {
+	opts.Addr = c.Listener.Addr
+	opts.MOTD = c.Listener.MOTD
+	opts.MOTDFull = c.Listener.MOTDFull
+	opts.MaxPlayers = c.Listener.MaxPlayers
+	opts.PrioritySlots = c.Listener.PrioritySlots
+	opts.PriorityPlayers = c.Listener.PriorityPlayers
+	opts.KickPolicy = c.Listener.KickPolicy
+	opts.MultiVersion = c.Listener.MultiVersion
+
+	opts.Servers = c.Servers.Addresses
+	opts.ServerCapacities = c.Servers.Capacities
+	opts.QueueFallback = c.Servers.QueueFallback
+	opts.HealthCheckInterval = c.Servers.HealthCheckInterval
+	opts.HealthCheckThreshold = c.Servers.HealthCheckThreshold
+	opts.Limbo = c.Servers.Limbo
+	opts.ConnectSecret = c.Servers.Secret
+	opts.ServerSecrets = c.Servers.Secrets
+
+	opts.TransferRetries = c.Transfer.Retries
+	opts.TransferBackoff = c.Transfer.Backoff
+	opts.DialTimeout = c.Transfer.DialTimeout
+	opts.LoginTimeout = c.Transfer.LoginTimeout
+	opts.Animation = c.Transfer.Animation
+	opts.SeamlessTransfer = c.Transfer.Seamless
+
+	opts.LoginRate = c.RateLimit.LoginRate
+	opts.LoginBurst = c.RateLimit.LoginBurst
+	opts.GlobalLoginRate = c.RateLimit.GlobalLoginRate
+	opts.GlobalLoginBurst = c.RateLimit.GlobalLoginBurst
+}
+
+// Opts returns spectrum.DefaultOpts with the options covered by the Config applied.
+func (c Config) Opts() *spectrum.Opts {
+	opts := spectrum.DefaultOpts()
+	c.Apply(opts)
+	return opts
+}
+
+// Loader returns a function loading the Config from the YAML file at the path passed and returning the Opts
+// of the proxy, for use with spectrum.Spectrum.SetConfigLoader. The options not covered by the Config are
+// taken from the Opts returned by base, or from spectrum.DefaultOpts if base is nil.
+func Loader(path string, base func() *spectrum.Opts) func() (*spectrum.Opts, error) {
+	return func() (*spectrum.Opts, error) {
+		conf, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		opts := spectrum.DefaultOpts()
+		if base != nil {
+			opts = base()
+		}
+		conf.Apply(opts)
+		return opts, nil
+	}
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spectrum-proxy/spectrum"
+	"github.com/spectrum-proxy/spectrum/session/animation"
+	"net"
+	"strings"
+)
+
+// Validate checks the options of the Config for values that are out of range or inconsistent with each
+// other. All problems found are returned joined into a single error.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, a ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, a...))
+		}
+	}
+
+	l := c.Listener
+	_, _, err := net.SplitHostPort(l.Addr)
+	check(err == nil, "listener.addr: invalid address %q", l.Addr)
+	check(l.MaxPlayers >= 0, "listener.max_players: must not be negative")
+	check(l.PrioritySlots >= 0, "listener.priority_slots: must not be negative")
+	check(l.MaxPlayers == 0 || l.PrioritySlots <= l.MaxPlayers, "listener.priority_slots: must not exceed max_players")
+	switch l.KickPolicy {
+	case spectrum.KickPolicyNone, spectrum.KickPolicyNewest, spectrum.KickPolicyOldest:
+	default:
+		check(false, "listener.kick_policy: unknown policy %q", l.KickPolicy)
+	}
+
+	for name, addr := range c.Servers.Addresses {
+		check(strings.TrimSpace(name) != "", "servers.addresses: server without a name")
+		check(strings.TrimSpace(addr) != "", "servers.addresses.%v: missing address", name)
+	}
+	for name, capacity := range c.Servers.Capacities {
+		check(capacity >= 0, "servers.capacities.%v: must not be negative", name)
+	}
+	check(c.Servers.HealthCheckInterval >= 0, "servers.health_check_interval: must not be negative")
+	check(c.Servers.HealthCheckThreshold >= 0, "servers.health_check_threshold: must not be negative")
+
+	t := c.Transfer
+	check(t.Retries >= 0, "transfer.retries: must not be negative")
+	check(t.Backoff >= 0, "transfer.backoff: must not be negative")
+	check(t.DialTimeout >= 0, "transfer.dial_timeout: must not be negative")
+	check(t.LoginTimeout >= 0, "transfer.login_timeout: must not be negative")
+	if t.Animation != "" {
+		_, ok := animation.ByName(t.Animation)
+		check(ok, "transfer.animation: unknown animation %q, expected one of %v", t.Animation, strings.Join(animation.Names(), ", "))
+	}
+
+	r := c.RateLimit
+	check(r.LoginRate >= 0, "rate_limit.login_rate: must not be negative")
+	check(r.LoginBurst >= 0, "rate_limit.login_burst: must not be negative")
+	check(r.GlobalLoginRate >= 0, "rate_limit.global_login_rate: must not be negative")
+	check(r.GlobalLoginBurst >= 0, "rate_limit.global_login_burst: must not be negative")
+
+	check(c.API.AdminAddr == "" || c.API.AdminToken != "", "api.admin_token: required if admin_addr is set")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+	return nil
+}
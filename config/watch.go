@@ -0,0 +1,41 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// Watch checks the file at the path passed for changes at the interval passed until the channel passed is
+// closed, calling reload every time the file changed, such as the Reload method of spectrum.Spectrum. Errors
+// checking the file or reloading are passed to onError if non-nil.
+func Watch(path string, interval time.Duration, done <-chan struct{}, reload func() error, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var modTime time.Time
+	if stat, err := os.Stat(path); err == nil {
+		modTime = stat.ModTime()
+	}
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			stat, err := os.Stat(path)
+			if err != nil {
+				if !errors.Is(err, os.ErrNotExist) && onError != nil {
+					onError(err)
+				}
+				continue
+			}
+			if stat.ModTime().Equal(modTime) {
+				continue
+			}
+			modTime = stat.ModTime()
+			if err := reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package spectrum
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/spectrum-proxy/spectrum/session"
+	"net/http"
+	"net/http/pprof"
+	runtimepprof "runtime/pprof"
+	"time"
+)
+
+// serveDebug serves the debug endpoints of the proxy over HTTP on the configured address if debugging is
+// enabled. Next to the pprof profiles under /debug/pprof/, a full goroutine dump is served under
+// /debug/goroutines and a snapshot of all sessions under /debug/sessions. A capture of the packets of a player
+// is downloaded from /debug/capture?player=<name>&duration=30s.
+func (s *Spectrum) serveDebug() {
+	if !s.opts.Debug || s.opts.DebugAddr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/goroutines", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_ = runtimepprof.Lookup("goroutine").WriteTo(w, 2)
+	})
+	mux.HandleFunc("/debug/sessions", func(w http.ResponseWriter, r *http.Request) {
+		diagnostics := make([]session.Diagnostics, 0, s.registry.Count())
+		s.registry.Range(func(sess *session.Session) bool {
+			diagnostics = append(diagnostics, sess.Diagnostics())
+			return true
+		})
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(diagnostics)
+	})
+
+	mux.HandleFunc("/debug/capture", s.serveCapture)
+
+	s.debug = &http.Server{Addr: s.opts.DebugAddr, Handler: mux}
+	go func() {
+		if err := s.debug.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			s.logger.Error("Failed to serve debug endpoints", "err", err)
+		}
+	}()
+}
+
+// serveCapture captures the packets of the player in the query of the request for the duration in the query,
+// writing the capture to the response.
+func (s *Spectrum) serveCapture(w http.ResponseWriter, r *http.Request) {
+	sess := s.registry.Lookup(r.URL.Query().Get("player"))
+	if sess == nil {
+		http.Error(w, "player not found", http.StatusNotFound)
+		return
+	}
+	duration := time.Second * 30
+	if d := r.URL.Query().Get("duration"); d != "" {
+		var err error
+		if duration, err = time.ParseDuration(d); err != nil {
+			http.Error(w, "invalid duration: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", sess.Client().IdentityData().DisplayName+".spcap"))
+	if err := sess.StartCapture(w); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	select {
+	case <-time.After(duration):
+	case <-r.Context().Done():
+	case <-sess.Context().Done():
+	}
+	_ = sess.StopCapture()
+}
@@ -0,0 +1,91 @@
+package detection
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"slices"
+	"sync"
+)
+
+// earlyPackets is the amount of packets sent by a client that are inspected by a Detector. Modified clients
+// usually give themselves away shortly after joining, so later packets are not inspected to keep the cost low.
+const earlyPackets = 512
+
+// Signal is a single indication that a client was modified.
+type Signal struct {
+	// Name is the name of the heuristic that raised the signal, such as "title_id".
+	Name string
+	// Score is the amount the signal adds to the risk score of the player.
+	Score int
+}
+
+// Risk is the risk that the client of a player was modified, as assessed from the signals raised for it.
+type Risk struct {
+	// Score is the sum of the scores of the signals. A score of zero means no signals were raised, while
+	// scores of 100 and above are a near certain indication of a modified client.
+	Score int
+	// Signals holds the signals raised, in the order they were raised.
+	Signals []Signal
+}
+
+// add adds the signal passed to the risk if no signal with the same name was raised yet. It returns false if
+// the signal was already raised.
+func (r *Risk) add(signal Signal) bool {
+	if slices.ContainsFunc(r.Signals, func(other Signal) bool { return other.Name == signal.Name }) {
+		return false
+	}
+	r.Signals = append(r.Signals, signal)
+	r.Score += signal.Score
+	return true
+}
+
+// Inspect inspects the login data of a player for signs of a modified client and returns the risk assessed.
+func Inspect(identity login.IdentityData, data login.ClientData) Risk {
+	var risk Risk
+	for _, signal := range inspectClientData(identity, data) {
+		risk.add(signal)
+	}
+	return risk
+}
+
+// Detector assesses the risk that the client of a single player was modified, from its login data and the
+// first packets it sends. A Detector is safe for concurrent use.
+type Detector struct {
+	identity login.IdentityData
+	data     login.ClientData
+
+	mu      sync.Mutex
+	risk    Risk
+	packets int
+}
+
+// New returns a Detector for a player with the login data passed, which is inspected immediately.
+func New(identity login.IdentityData, data login.ClientData) *Detector {
+	return &Detector{identity: identity, data: data, risk: Inspect(identity, data)}
+}
+
+// HandlePacket inspects a packet sent by the client of the player. It returns the signals newly raised by the
+// packet, if any.
+func (d *Detector) HandlePacket(pk packet.Packet) []Signal {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.packets >= earlyPackets {
+		return nil
+	}
+	d.packets++
+
+	var raised []Signal
+	for _, signal := range inspectPacket(d.identity, d.data, pk) {
+		if d.risk.add(signal) {
+			raised = append(raised, signal)
+		}
+	}
+	return raised
+}
+
+// Risk returns the risk assessed for the player so far.
+func (d *Detector) Risk() Risk {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return Risk{Score: d.risk.Score, Signals: slices.Clone(d.risk.Signals)}
+}
@@ -0,0 +1,76 @@
+package detection
+
+import (
+	"encoding/base64"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"slices"
+	"strings"
+)
+
+// titleIDs holds the Xbox Live title IDs of the official clients, keyed by the device OS they run on.
+var titleIDs = map[protocol.DeviceOS]string{
+	protocol.DeviceAndroid: "1739947436",
+	protocol.DeviceIOS:     "1810924247",
+	protocol.DeviceFireOS:  "1944307183",
+	protocol.DeviceWin10:   "896928775",
+	protocol.DeviceOrbis:   "2044456598",
+	protocol.DeviceNX:      "2047319603",
+	protocol.DeviceXBOX:    "1828326430",
+}
+
+// consoles holds the device OSes of consoles, which have no touch screen.
+var consoles = []protocol.DeviceOS{protocol.DeviceOrbis, protocol.DeviceNX, protocol.DeviceXBOX}
+
+// skinSizes holds the sizes in pixels of the skin images that official clients send.
+var skinSizes = [][2]int{{64, 32}, {64, 64}, {128, 64}, {128, 128}, {256, 128}, {256, 256}, {512, 512}}
+
+// inspectClientData returns the signals raised by the login data of a player.
+func inspectClientData(identity login.IdentityData, data login.ClientData) []Signal {
+	var signals []Signal
+	if data.DeviceOS <= 0 || data.DeviceOS > protocol.DeviceLinux {
+		signals = append(signals, Signal{Name: "device_os", Score: 100})
+	}
+	// The title ID is only present for players logged in to Xbox Live, and identifies the client used.
+	if expected, ok := titleIDs[data.DeviceOS]; ok && identity.TitleID != "" && identity.TitleID != expected {
+		signals = append(signals, Signal{Name: "title_id", Score: 60})
+	}
+	switch data.DeviceOS {
+	case protocol.DeviceAndroid, protocol.DeviceIOS:
+		if data.DeviceModel == "" {
+			signals = append(signals, Signal{Name: "device_model", Score: 30})
+		} else if data.DeviceOS == protocol.DeviceIOS && !strings.HasPrefix(data.DeviceModel, "iP") {
+			signals = append(signals, Signal{Name: "device_model", Score: 30})
+		}
+	}
+	if slices.Contains(consoles, data.DeviceOS) && (data.DefaultInputMode == packet.InputModeTouch || data.CurrentInputMode == packet.InputModeTouch) {
+		signals = append(signals, Signal{Name: "input_mode", Score: 40})
+	}
+	skin, err := base64.StdEncoding.DecodeString(data.SkinData)
+	if err != nil || len(skin) != data.SkinImageWidth*data.SkinImageHeight*4 {
+		signals = append(signals, Signal{Name: "skin_data", Score: 60})
+	} else if !slices.Contains(skinSizes, [2]int{data.SkinImageWidth, data.SkinImageHeight}) {
+		signals = append(signals, Signal{Name: "skin_size", Score: 30})
+	}
+	return signals
+}
+
+// inspectPacket returns the signals raised by a packet sent by the client of a player.
+func inspectPacket(identity login.IdentityData, data login.ClientData, pk packet.Packet) []Signal {
+	switch pk := pk.(type) {
+	case *packet.PlayerAuthInput:
+		if slices.Contains(consoles, data.DeviceOS) && pk.InputMode == packet.InputModeTouch {
+			return []Signal{{Name: "input_mode", Score: 40}}
+		}
+		if pk.PlayMode >= packet.PlayModeNumModes {
+			return []Signal{{Name: "play_mode", Score: 50}}
+		}
+	case *packet.Text:
+		// Official clients always send their own XUID with chat messages.
+		if pk.TextType == packet.TextTypeChat && identity.XUID != "" && pk.XUID != identity.XUID {
+			return []Signal{{Name: "chat_xuid", Score: 50}}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package event
+
+import "sync"
+
+// Bus delivers events of type E to any number of subscribers. Events are delivered synchronously in the
+// order subscribers subscribed, so subscribers should not block. A Bus may be used concurrently.
+type Bus[E any] struct {
+	mu          sync.RWMutex
+	subscribers []*subscriber[E]
+}
+
+// subscriber is a single subscriber of a Bus.
+type subscriber[E any] struct {
+	f func(E)
+}
+
+// NewBus returns a new Bus without subscribers.
+func NewBus[E any]() *Bus[E] {
+	return &Bus[E]{}
+}
+
+// Subscribe subscribes f to all events published after the call. The function returned unsubscribes f.
+func (b *Bus[E]) Subscribe(f func(E)) (unsubscribe func()) {
+	sub := &subscriber[E]{f: f}
+
+	b.mu.Lock()
+	b.subscribers = append(b.subscribers, sub)
+	b.mu.Unlock()
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, s := range b.subscribers {
+			if s == sub {
+				b.subscribers = append(b.subscribers[:i:i], b.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Publish delivers the event passed to all subscribers.
+func (b *Bus[E]) Publish(e E) {
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	for _, s := range subscribers {
+		s.f(e)
+	}
+}
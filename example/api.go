@@ -2,45 +2,53 @@ package main
 
 import (
 	"github.com/sandertv/gophertunnel/minecraft"
-	"github.com/sirupsen/logrus"
 	"github.com/spectrum-proxy/spectrum"
 	"github.com/spectrum-proxy/spectrum/api"
 	"github.com/spectrum-proxy/spectrum/server"
+	"log/slog"
 )
 
 func main() {
-	logger := logrus.New()
+	logger := slog.Default()
 	listenConfig := minecraft.ListenConfig{
 		StatusProvider: spectrum.NewStatusProvider("Spectrum Proxy"),
 	}
 
 	s := spectrum.NewSpectrum(server.NewStaticDiscovery(":19133"), logger, nil)
 	if err := s.Listen(listenConfig); err != nil {
-		logger.Errorf("Failed to listen on s: %v", err)
+		logger.Error("Failed to listen on s", "err", err)
 		return
 	}
 
 	a := api.NewAPI(logger, s.Registry())
+	a.SetBanStore(s.Bans())
 	if err := a.Listen(":19134"); err != nil {
-		logger.Errorf("Failed to listen on a: %v", err)
+		logger.Error("Failed to listen on a", "err", err)
 		return
 	}
 
 	go func() {
 		for {
 			if err := a.Accept(); err != nil {
-				logger.Errorf("Failed to accept connection: %v", err)
+				logger.Error("Failed to accept connection", "err", err)
 			}
 		}
 	}()
 
+	admin := api.NewAdmin(logger, s.Registry(), s, "change-me")
+	go func() {
+		if err := admin.ListenAndServe(":8080"); err != nil {
+			logger.Error("Failed to serve admin API", "err", err)
+		}
+	}()
+
 	for {
 		s, err := s.Accept()
 		if err != nil {
 			if s != nil {
 				s.Disconnect(err.Error())
 			}
-			logger.Errorf("Failed to accept session: %v", err)
+			logger.Error("Failed to accept session", "err", err)
 		}
 	}
 }
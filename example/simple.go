@@ -2,17 +2,17 @@ package main
 
 import (
 	"github.com/sandertv/gophertunnel/minecraft"
-	"github.com/sirupsen/logrus"
 	"github.com/spectrum-proxy/spectrum"
 	"github.com/spectrum-proxy/spectrum/server"
+	"log/slog"
 )
 
 func main() {
-	logger := logrus.New()
+	logger := slog.Default()
 	listenConfig := minecraft.ListenConfig{StatusProvider: spectrum.NewStatusProvider("Spectrum Proxy")}
 	proxy := spectrum.NewSpectrum(server.NewStaticDiscovery(":19133"), logger, nil)
 	if err := proxy.Listen(listenConfig); err != nil {
-		logger.Errorf("Failed to listen on proxy: %v", err)
+		logger.Error("Failed to listen on proxy", "err", err)
 		return
 	}
 
@@ -22,7 +22,7 @@ func main() {
 			if s != nil {
 				s.Disconnect(err.Error())
 			}
-			logger.Errorf("Failed to accept session: %v", err)
+			logger.Error("Failed to accept session", "err", err)
 		}
 	}
 }
@@ -0,0 +1,78 @@
+package geoip
+
+import (
+	"fmt"
+	"net"
+	"sort"
+)
+
+// Resolver resolves the region of an IP address, such as "eu" or "na". The regions are chosen freely by the
+// Resolver and are only compared against each other, for example to route players to servers in their
+// region.
+type Resolver interface {
+	// Resolve returns the region of the IP address passed, or an empty string if the region is unknown.
+	Resolve(ip net.IP) (string, error)
+}
+
+// Region resolves the region of the network address passed using the resolver passed. An empty string is
+// returned if the address is not an IP address.
+func Region(resolver Resolver, addr net.Addr) (string, error) {
+	var ip net.IP
+	switch addr := addr.(type) {
+	case *net.UDPAddr:
+		ip = addr.IP
+	case *net.TCPAddr:
+		ip = addr.IP
+	default:
+		host, _, err := net.SplitHostPort(addr.String())
+		if err != nil {
+			host = addr.String()
+		}
+		ip = net.ParseIP(host)
+	}
+	if ip == nil {
+		return "", nil
+	}
+	return resolver.Resolve(ip)
+}
+
+// CIDRResolver is a Resolver that resolves regions from a static table of IP ranges.
+type CIDRResolver struct {
+	ranges []cidrRange
+}
+
+// cidrRange is a single IP range of a CIDRResolver.
+type cidrRange struct {
+	network *net.IPNet
+	region  string
+}
+
+// NewCIDRResolver returns a CIDRResolver for the ranges passed, which map ranges in CIDR notation, such as
+// "10.0.0.0/8", to their region. If an IP address is in multiple ranges, the most specific range is used.
+func NewCIDRResolver(ranges map[string]string) (*CIDRResolver, error) {
+	r := &CIDRResolver{ranges: make([]cidrRange, 0, len(ranges))}
+	for cidr, region := range ranges {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse range %v: %v", cidr, err)
+		}
+		r.ranges = append(r.ranges, cidrRange{network: network, region: region})
+	}
+
+	sort.Slice(r.ranges, func(i, j int) bool {
+		a, _ := r.ranges[i].network.Mask.Size()
+		b, _ := r.ranges[j].network.Mask.Size()
+		return a > b
+	})
+	return r, nil
+}
+
+// Resolve ...
+func (r *CIDRResolver) Resolve(ip net.IP) (string, error) {
+	for _, rng := range r.ranges {
+		if rng.network.Contains(ip) {
+			return rng.region, nil
+		}
+	}
+	return "", nil
+}
@@ -3,17 +3,17 @@ module github.com/spectrum-proxy/spectrum
 go 1.22.1
 
 require (
+	github.com/go-gl/mathgl v1.1.0
+	github.com/google/uuid v1.6.0
 	github.com/sandertv/gophertunnel v1.36.0
 	github.com/scylladb/go-set v1.0.2
-	github.com/sirupsen/logrus v1.9.3
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/go-gl/mathgl v1.1.0 // indirect
 	github.com/go-jose/go-jose/v3 v3.0.3 // indirect
 	github.com/golang/protobuf v1.5.4 // indirect
 	github.com/golang/snappy v0.0.4 // indirect
-	github.com/google/uuid v1.6.0 // indirect
 	github.com/klauspost/compress v1.17.7 // indirect
 	github.com/muhammadmuzzammil1998/jsonc v1.0.0 // indirect
 	github.com/sandertv/go-raknet v1.13.0 // indirect
@@ -0,0 +1,48 @@
+package spectrum
+
+import (
+	"github.com/spectrum-proxy/spectrum/healthcheck"
+	"github.com/spectrum-proxy/spectrum/metrics"
+	"github.com/spectrum-proxy/spectrum/session"
+	"time"
+)
+
+// checkHealth starts checking the reachability of servers at the configured interval, if any.
+func (s *Spectrum) checkHealth() {
+	if s.health == nil {
+		return
+	}
+
+	s.health.Subscribe(func(addr string, healthy bool) {
+		if healthy {
+			s.logger.Info("Server is up", "server", addr)
+		} else {
+			s.logger.Warn("Server is down", "server", addr)
+		}
+	})
+	metrics.NewGaugeVecFunc("spectrum_server_up", "Whether a server is reachable.", "server", func() map[string]float64 {
+		values := make(map[string]float64)
+		for addr, status := range s.health.Statuses() {
+			values[addr] = 0
+			if status.Healthy {
+				values[addr] = 1
+			}
+		}
+		return values
+	})
+	go s.health.Run(time.Duration(s.opts.HealthCheckInterval)*time.Millisecond, s.closed)
+}
+
+// Health returns the healthcheck.Checker reporting the reachability of servers, or nil if health checks are
+// not enabled.
+func (s *Spectrum) Health() *healthcheck.Checker {
+	return s.health
+}
+
+// healthChecker returns the session.HealthChecker passed to sessions, or nil if health checks are not enabled.
+func (s *Spectrum) healthChecker() session.HealthChecker {
+	if s.health == nil {
+		return nil
+	}
+	return s.health
+}
@@ -0,0 +1,31 @@
+package healthcheck
+
+import (
+	"errors"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"sync/atomic"
+)
+
+// Balancer is a server.Discovery that distributes players over a set of servers in turn, skipping servers
+// that the Checker considers down.
+type Balancer struct {
+	checker *Checker
+	servers []string
+	next    atomic.Uint64
+}
+
+// NewBalancer returns a new Balancer distributing players over the servers at the addresses passed.
+func NewBalancer(checker *Checker, servers ...string) *Balancer {
+	return &Balancer{checker: checker, servers: servers}
+}
+
+// Discover ...
+func (b *Balancer) Discover(*minecraft.Conn) (string, error) {
+	for range b.servers {
+		addr := b.servers[b.next.Add(1)%uint64(len(b.servers))]
+		if b.checker.Healthy(addr) {
+			return addr, nil
+		}
+	}
+	return "", errors.New("no healthy server available")
+}
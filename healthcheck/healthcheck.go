@@ -0,0 +1,178 @@
+// Package healthcheck periodically checks if servers are reachable, so that players are not routed to servers
+// that are down.
+package healthcheck
+
+import (
+	"context"
+	"github.com/spectrum-proxy/spectrum/server"
+	"sync"
+	"time"
+)
+
+// Probe checks if a single server is reachable.
+type Probe interface {
+	// Probe returns an error if the server at the address passed is not reachable.
+	Probe(addr string) error
+}
+
+// TransportProbe is a Probe that connects to servers using a server.Transport and closes the connection right
+// away. A server is considered reachable if the connection succeeds.
+type TransportProbe struct {
+	// Transport is the server.Transport used to connect. If nil, server.TCP is used.
+	Transport server.Transport
+	// Timeout is the maximum duration connecting may take. If zero, five seconds are used.
+	Timeout time.Duration
+}
+
+// Probe ...
+func (p TransportProbe) Probe(addr string) error {
+	transport := p.Transport
+	if transport == nil {
+		transport = server.TCP{}
+	}
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = time.Second * 5
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn, err := transport.Dial(ctx, addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// Status holds the health of a server.
+type Status struct {
+	// Healthy specifies if the server is considered reachable.
+	Healthy bool
+	// Failures is the amount of consecutive failed checks.
+	Failures int
+	// LastError is the error of the last failed check, or nil if the last check succeeded.
+	LastError error
+	// Checked is the time the server was last checked.
+	Checked time.Time
+}
+
+// Checker periodically checks the health of all servers in a server.Registry. Servers are marked down after
+// a configurable amount of consecutive failed checks and up again after a single successful check. Servers
+// that were not checked yet are considered healthy.
+type Checker struct {
+	servers   *server.Registry
+	probe     Probe
+	threshold int
+
+	mu          sync.RWMutex
+	statuses    map[string]Status
+	subscribers []func(addr string, healthy bool)
+}
+
+// New returns a new Checker checking the servers in the registry passed using the Probe passed. Servers are
+// marked down after threshold consecutive failed checks. If probe is nil, a TransportProbe is used.
+func New(servers *server.Registry, probe Probe, threshold int) *Checker {
+	if probe == nil {
+		probe = TransportProbe{}
+	}
+	return &Checker{
+		servers:   servers,
+		probe:     probe,
+		threshold: max(threshold, 1),
+		statuses:  make(map[string]Status),
+	}
+}
+
+// Run checks all servers at the interval passed until the channel passed is closed.
+func (c *Checker) Run(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.CheckAll()
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckAll checks all servers in the registry concurrently and returns once all checks completed.
+func (c *Checker) CheckAll() {
+	addrs := make(map[string]struct{})
+	for _, name := range c.servers.Names() {
+		addrs[c.servers.Resolve(name)] = struct{}{}
+	}
+
+	var wg sync.WaitGroup
+	for addr := range addrs {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+			c.Check(addr)
+		}(addr)
+	}
+	wg.Wait()
+}
+
+// Check checks the server at the address passed and returns its updated Status.
+func (c *Checker) Check(addr string) Status {
+	err := c.probe.Probe(addr)
+
+	c.mu.Lock()
+	status, ok := c.statuses[addr]
+	if !ok {
+		status.Healthy = true
+	}
+	wasHealthy := status.Healthy
+
+	status.Checked, status.LastError = time.Now(), err
+	if err != nil {
+		status.Failures++
+		if status.Failures >= c.threshold {
+			status.Healthy = false
+		}
+	} else {
+		status.Failures, status.Healthy = 0, true
+	}
+	c.statuses[addr] = status
+	subscribers := c.subscribers
+	c.mu.Unlock()
+
+	if status.Healthy != wasHealthy {
+		for _, f := range subscribers {
+			f(addr, status.Healthy)
+		}
+	}
+	return status
+}
+
+// Healthy returns true if the server at the address passed is considered reachable.
+func (c *Checker) Healthy(addr string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	status, ok := c.statuses[addr]
+	return !ok || status.Healthy
+}
+
+// Statuses returns the Status of every server checked, keyed by the address of the server.
+func (c *Checker) Statuses() map[string]Status {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	statuses := make(map[string]Status, len(c.statuses))
+	for addr, status := range c.statuses {
+		statuses[addr] = status
+	}
+	return statuses
+}
+
+// Subscribe subscribes f to changes of the health of servers. f is called with the address of the server
+// and whether it is now healthy.
+func (c *Checker) Subscribe(f func(addr string, healthy bool)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subscribers = append(c.subscribers, f)
+}
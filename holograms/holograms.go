@@ -0,0 +1,160 @@
+package holograms
+
+import (
+	"github.com/spectrum-proxy/spectrum/event"
+	"github.com/spectrum-proxy/spectrum/session"
+	"slices"
+	"sync"
+)
+
+// hologram is a hologram added to a Manager along with the function deciding which players see it.
+type hologram struct {
+	h       session.Hologram
+	visible func(s *session.Session) bool
+}
+
+// Manager shows holograms to the players on the proxy. Every hologram has its own visibility, which is checked
+// again whenever a player joins the proxy or is transferred, so that holograms may for example only be shown
+// on specific servers or to players with a specific rank.
+type Manager struct {
+	registry *session.Registry
+
+	mu        sync.Mutex
+	holograms map[int]hologram
+	shown     map[*session.Session]map[int]int64
+	nextID    int
+
+	unsubscribe func()
+}
+
+// New returns a new Manager showing holograms to the sessions in the registry passed. The visibility of
+// holograms is checked when sessions start or transfer, as published on the bus passed.
+func New(registry *session.Registry, events *event.Bus[session.Event]) *Manager {
+	m := &Manager{
+		registry:  registry,
+		holograms: make(map[int]hologram),
+		shown:     make(map[*session.Session]map[int]int64),
+	}
+	m.unsubscribe = events.Subscribe(m.handleEvent)
+	return m
+}
+
+// Close stops the Manager from following the events of sessions. Holograms already shown are kept.
+func (m *Manager) Close() {
+	m.unsubscribe()
+}
+
+// Add adds a hologram shown to the players for which visible returns true, or to all players if visible is
+// nil, and returns its ID.
+func (m *Manager) Add(h session.Hologram, visible func(s *session.Session) bool) int {
+	m.mu.Lock()
+	m.nextID++
+	id := m.nextID
+	m.holograms[id] = hologram{h: h, visible: visible}
+	m.mu.Unlock()
+
+	m.Refresh(id)
+	return id
+}
+
+// SetLines replaces the lines of the hologram with the ID passed for all players it is shown to.
+func (m *Manager) SetLines(id int, lines []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	holo, ok := m.holograms[id]
+	if !ok {
+		return
+	}
+	holo.h.Lines = slices.Clone(lines)
+	m.holograms[id] = holo
+	for s, shown := range m.shown {
+		if holoID, ok := shown[id]; ok {
+			s.UpdateHologram(holoID, lines)
+		}
+	}
+}
+
+// SetVisibility replaces the function deciding which players see the hologram with the ID passed and shows or
+// hides the hologram accordingly.
+func (m *Manager) SetVisibility(id int, visible func(s *session.Session) bool) {
+	m.mu.Lock()
+	holo, ok := m.holograms[id]
+	holo.visible = visible
+	if ok {
+		m.holograms[id] = holo
+	}
+	m.mu.Unlock()
+
+	if ok {
+		m.Refresh(id)
+	}
+}
+
+// Refresh checks the visibility of the hologram with the ID passed for all players again, showing or hiding
+// it where it changed. It should be called when the values the visibility depends on change.
+func (m *Manager) Refresh(id int) {
+	m.registry.Range(func(s *session.Session) bool {
+		m.update(s, id)
+		return true
+	})
+}
+
+// Remove removes the hologram with the ID passed for all players.
+func (m *Manager) Remove(id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.holograms, id)
+	for s, shown := range m.shown {
+		if holoID, ok := shown[id]; ok {
+			s.HideHologram(holoID)
+			delete(shown, id)
+		}
+	}
+}
+
+// handleEvent checks the visibility of all holograms for sessions that start or transfer and forgets the
+// sessions that are closed.
+func (m *Manager) handleEvent(e session.Event) {
+	s := e.Session()
+	switch e.(type) {
+	case session.SessionStart, session.TransferEnd:
+		m.mu.Lock()
+		ids := make([]int, 0, len(m.holograms))
+		for id := range m.holograms {
+			ids = append(ids, id)
+		}
+		m.mu.Unlock()
+
+		for _, id := range ids {
+			m.update(s, id)
+		}
+	case session.SessionClose:
+		m.mu.Lock()
+		delete(m.shown, s)
+		m.mu.Unlock()
+	}
+}
+
+// update shows or hides the hologram with the ID passed for the session passed, depending on its visibility.
+func (m *Manager) update(s *session.Session, id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	holo, ok := m.holograms[id]
+	if !ok {
+		return
+	}
+	holoID, shown := m.shown[s][id]
+	switch visible := holo.visible == nil || holo.visible(s); {
+	case visible && !shown:
+		if m.shown[s] == nil {
+			m.shown[s] = make(map[int]int64)
+		}
+		m.shown[s][id] = s.ShowHologram(holo.h)
+	case !visible && shown:
+		s.HideHologram(holoID)
+		delete(m.shown[s], id)
+	}
+}
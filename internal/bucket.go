@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenBucket is a token bucket rate limiter. Tokens are added at a fixed rate up to a maximum burst, and
+// every allowed action takes a token.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket returns a full TokenBucket refilled with rate tokens per second, holding at most burst
+// tokens.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	return &TokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// Allow takes a token from the bucket and returns true, or returns false if the bucket is empty.
+func (b *TokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Full returns true if the bucket has been refilled completely, meaning it is no longer limiting.
+func (b *TokenBucket) Full() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens+time.Since(b.last).Seconds()*b.rate >= b.burst
+}
+
+// Reserve takes n tokens from the bucket, even if it holds fewer, and returns the duration after which the
+// bucket holds a non-negative amount of tokens again. Callers should wait for the duration before acting.
+func (b *TokenBucket) Reserve(n int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
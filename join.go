@@ -0,0 +1,60 @@
+package spectrum
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/spectrum-proxy/spectrum/ban"
+	"github.com/spectrum-proxy/spectrum/session"
+)
+
+// newJoinPipeline returns the JoinPipeline of the proxy, checking the bans and the whitelist of the proxy before
+// the steps of the session.DefaultJoinPipeline.
+func (s *Spectrum) newJoinPipeline() *session.JoinPipeline {
+	pipeline := session.DefaultJoinPipeline()
+	pipeline.Insert("server", session.NewJoinStep("ban", func(j *session.Join) error {
+		return s.checkBan(j.Session.Client())
+	}))
+	pipeline.Insert("server", session.NewJoinStep("whitelist", func(j *session.Join) error {
+		return s.checkWhitelist(j.Session.Client())
+	}))
+	return pipeline
+}
+
+// admit returns a session.Rejection if the player of the connection passed is banned or not whitelisted. It is
+// called before a suspended session is resumed or a session is queued, as neither passes through the
+// JoinPipeline first.
+func (s *Spectrum) admit(conn *minecraft.Conn) error {
+	if err := s.checkBan(conn); err != nil {
+		return err
+	}
+	return s.checkWhitelist(conn)
+}
+
+// checkBan returns a session.Rejection if the player of the connection passed is banned. Players are also
+// rejected if the bans could not be checked, so that banned players are never let in.
+func (s *Spectrum) checkBan(conn *minecraft.Conn) error {
+	entry, ok, err := ban.Check(s.bans, conn.IdentityData().XUID, ban.IP(conn.RemoteAddr()))
+	if err != nil {
+		s.logger.Error("Failed to check bans", "name", conn.IdentityData().DisplayName, "err", err)
+		rejectionsTotal.With("banned").Inc()
+		return session.Rejection{Message: messageBanCheckFailed}
+	}
+	if ok {
+		rejectionsTotal.With("banned").Inc()
+		return session.Rejection{Message: entry.Message()}
+	}
+	return nil
+}
+
+// checkWhitelist returns a session.Rejection if the player of the connection passed is not whitelisted.
+func (s *Spectrum) checkWhitelist(conn *minecraft.Conn) error {
+	identity := conn.IdentityData()
+	ok, err := s.whitelist.Allowed(identity.XUID, identity.DisplayName)
+	if err != nil {
+		s.logger.Error("Failed to check whitelist", "name", identity.DisplayName, "err", err)
+	}
+	if err != nil || !ok {
+		rejectionsTotal.With("whitelist").Inc()
+		return session.Rejection{Message: messageNotWhitelisted}
+	}
+	return nil
+}
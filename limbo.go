@@ -0,0 +1,75 @@
+package spectrum
+
+import (
+	"github.com/spectrum-proxy/spectrum/limbo"
+	"github.com/spectrum-proxy/spectrum/server"
+	"maps"
+	"time"
+)
+
+// limboInterval is the interval at which sessions parked in limbo are moved to their server once it is
+// available.
+const limboInterval = time.Second * 5
+
+// registerLimbo registers the transport of the limbo server if it is enabled in the Opts of the proxy.
+func (s *Spectrum) registerLimbo() {
+	if s.opts.Limbo {
+		server.RegisterTransport(limbo.TransportName, limbo.New(s.logger, s.opts.LimboMessage))
+	}
+}
+
+// limboAddr returns the address of the limbo server, or an empty string if limbo is disabled.
+func (s *Spectrum) limboAddr() string {
+	if !s.opts.Limbo {
+		return ""
+	}
+	return limbo.Addr
+}
+
+// transports returns the transports used to connect to servers, keyed by the name or address of the server,
+// including the transport of the limbo server if it is enabled. transports must be called with optsMu held.
+func (s *Spectrum) transports() map[string]string {
+	if !s.opts.Limbo {
+		return s.opts.ServerTransports
+	}
+	transports := maps.Clone(s.opts.ServerTransports)
+	if transports == nil {
+		transports = make(map[string]string, 1)
+	}
+	transports[limbo.Addr] = limbo.TransportName
+	return transports
+}
+
+// rescueLimbo moves the sessions parked in limbo to the server the discovery of the proxy resolves for them
+// once that server is available, at the interval passed until the proxy is closed. Servers at their capacity
+// are waited for in their queue.
+func (s *Spectrum) rescueLimbo(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.closed:
+			return
+		case <-ticker.C:
+		}
+
+		for _, sess := range s.registry.SessionsOn(limbo.Addr) {
+			if _, _, queued := s.queue.Position(sess); queued {
+				continue
+			}
+			target, err := s.discovery.Discover(sess.Client())
+			if err != nil {
+				continue
+			}
+			if target = s.servers.Resolve(target); target == limbo.Addr || (s.health != nil && !s.health.Healthy(target)) {
+				continue
+			}
+			go func() {
+				if _, err := s.queue.Transfer(sess.Context(), sess, target, s.queueTier(sess.Client().IdentityData())); err != nil {
+					s.logger.Debug("Failed to move session out of limbo", "name", sess.Client().IdentityData().DisplayName, "err", err)
+				}
+			}()
+		}
+	}
+}
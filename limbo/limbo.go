@@ -0,0 +1,170 @@
+package limbo
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/server"
+	packet2 "github.com/spectrum-proxy/spectrum/server/packet"
+	"log/slog"
+	"net"
+)
+
+const (
+	// Addr is the address of the limbo server. Sessions are parked in limbo by transferring them to it.
+	Addr = "limbo"
+	// TransportName is the name the Limbo transport is registered with.
+	TransportName = "limbo"
+)
+
+// spawnHeight is the height at which players are spawned in limbo.
+const spawnHeight = 100
+
+// Limbo is a minimal world served by the proxy itself, in which sessions can be parked when no server is
+// available, for example while servers are restarting. It is a server.Transport: dialing it does not open a
+// network connection, but connects to a void world held in memory in which players are held in place.
+//
+// The compression of the limbo server cannot be configured, as the Limbo does not negotiate compression.
+type Limbo struct {
+	// Message is sent to players when they enter limbo. If empty, no message is sent.
+	Message string
+
+	logger *slog.Logger
+}
+
+// New returns a new Limbo that sends the message passed to players entering it. Errors serving players are
+// logged to the logger passed.
+func New(logger *slog.Logger, message string) *Limbo {
+	return &Limbo{logger: logger, Message: message}
+}
+
+// Dial ...
+func (l *Limbo) Dial(ctx context.Context, _ string) (net.Conn, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client, srv := net.Pipe()
+	go func() {
+		conn := server.NewConn(srv, packet.NewClientPool())
+		defer conn.Close()
+		if err := l.serve(conn); err != nil {
+			l.logger.Debug("Limbo connection closed", "err", err)
+		}
+	}()
+	return client, nil
+}
+
+// serve serves the limbo world over the connection passed until it is closed.
+func (l *Limbo) serve(conn *server.Conn) error {
+	pk, err := conn.ReadPacket()
+	if err != nil {
+		return fmt.Errorf("failed to read connect packet: %v", err)
+	}
+	connect, ok := pk.(*packet2.Connect)
+	if !ok {
+		return fmt.Errorf("expected connect packet, got %T", pk)
+	}
+	if err := l.spawn(conn, connect.EntityID); err != nil {
+		return err
+	}
+
+	for {
+		pk, err := conn.ReadPacket()
+		if err != nil {
+			return err
+		}
+		switch pk := pk.(type) {
+		case *packet2.Latency:
+			err = conn.WritePacket(&packet2.Latency{Timestamp: pk.Timestamp})
+		case *packet.NetworkStackLatency:
+			if pk.NeedsResponse {
+				err = conn.WritePacket(&packet.NetworkStackLatency{Timestamp: pk.Timestamp})
+			}
+		case *packet.RequestChunkRadius:
+			err = conn.WritePacket(&packet.ChunkRadiusUpdated{ChunkRadius: pk.ChunkRadius})
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// spawn spawns the player with the entity ID passed in the limbo world, following the sequence expected by
+// server.Dialer.
+func (l *Limbo) spawn(conn *server.Conn, entityID int64) error {
+	pos := mgl32.Vec3{0.5, spawnHeight, 0.5}
+	err := conn.WritePacket(&packet.StartGame{
+		EntityUniqueID:  entityID,
+		EntityRuntimeID: uint64(entityID),
+		PlayerGameMode:  packet.GameTypeAdventure,
+		PlayerPosition:  pos,
+		Dimension:       packet.DimensionOverworld,
+		Generator:       2,
+		WorldGameMode:   packet.GameTypeAdventure,
+		WorldSpawn:      protocol.BlockPos{0, spawnHeight, 0},
+		BaseGameVersion: "*",
+		LevelID:         "limbo",
+		WorldName:       "Limbo",
+		Time:            6000,
+		GameVersion:     protocol.CurrentVersion,
+		PropertyData:    map[string]any{},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write start game packet: %v", err)
+	}
+
+	pk, err := conn.ReadPacket()
+	if err != nil {
+		return fmt.Errorf("failed to read request chunk radius packet: %v", err)
+	}
+	radius, ok := pk.(*packet.RequestChunkRadius)
+	if !ok {
+		return fmt.Errorf("expected request chunk radius packet, got %T", pk)
+	}
+	pks := []packet.Packet{
+		&packet.ChunkRadiusUpdated{ChunkRadius: radius.ChunkRadius},
+		&packet.NetworkChunkPublisherUpdate{Position: protocol.BlockPos{0, spawnHeight, 0}, Radius: uint32(radius.ChunkRadius) << 4},
+	}
+	for x := -radius.ChunkRadius; x <= radius.ChunkRadius; x++ {
+		for z := -radius.ChunkRadius; z <= radius.ChunkRadius; z++ {
+			pks = append(pks, &packet.LevelChunk{Position: protocol.ChunkPos{x, z}, RawPayload: emptyChunk})
+		}
+	}
+	pks = append(pks, &packet.PlayStatus{Status: packet.PlayStatusPlayerSpawn})
+	for _, pk := range pks {
+		if err := conn.WritePacket(pk); err != nil {
+			return fmt.Errorf("failed to write %T: %v", pk, err)
+		}
+	}
+
+	// Players are held in place, so that they do not fall through the void.
+	metadata := protocol.NewEntityMetadata()
+	metadata.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagNoAI)
+	metadata.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagBreathing)
+	pks = []packet.Packet{
+		&packet.SetActorData{EntityRuntimeID: uint64(entityID), EntityMetadata: metadata},
+		&packet.MovePlayer{EntityRuntimeID: uint64(entityID), Position: pos.Add(mgl32.Vec3{0, 1.62}), Mode: packet.MoveModeTeleport},
+	}
+	if l.Message != "" {
+		pks = append(pks, &packet.Text{TextType: packet.TextTypeRaw, Message: l.Message})
+	}
+	for _, pk := range pks {
+		if err := conn.WritePacket(pk); err != nil {
+			return fmt.Errorf("failed to write %T: %v", pk, err)
+		}
+	}
+	return nil
+}
+
+// emptyChunk is the payload of a chunk without any blocks. It holds the biomes of the 24 sub chunks of the
+// overworld, each a single plains biome, followed by the count of border blocks.
+var emptyChunk = func() []byte {
+	var payload []byte
+	for range 24 {
+		// A paletted storage without indices, followed by the single entry of its palette as varint32.
+		payload = append(payload, 0x01, 0x02)
+	}
+	return append(payload, 0x00)
+}()
@@ -0,0 +1,75 @@
+package spectrum
+
+import (
+	"github.com/spectrum-proxy/spectrum/internal"
+	"net"
+	"sync"
+	"time"
+)
+
+// loginLimiter limits the rate at which connections are accepted, both globally and per IP address.
+type loginLimiter struct {
+	rate  float64
+	burst int
+
+	global *internal.TokenBucket
+
+	mu      sync.Mutex
+	buckets map[string]*internal.TokenBucket
+	pruned  time.Time
+}
+
+// newLoginLimiter returns a loginLimiter for the Opts passed, or nil if login rate limiting is disabled.
+func newLoginLimiter(opts *Opts) *loginLimiter {
+	if opts.LoginRate <= 0 && opts.GlobalLoginRate <= 0 {
+		return nil
+	}
+
+	l := &loginLimiter{
+		rate:    opts.LoginRate,
+		burst:   max(opts.LoginBurst, 1),
+		buckets: make(map[string]*internal.TokenBucket),
+		pruned:  time.Now(),
+	}
+	if opts.GlobalLoginRate > 0 {
+		l.global = internal.NewTokenBucket(opts.GlobalLoginRate, max(opts.GlobalLoginBurst, 1))
+	}
+	return l
+}
+
+// allow returns true if a connection from the address passed may be accepted.
+func (l *loginLimiter) allow(addr net.Addr) bool {
+	if l.rate > 0 && !l.bucket(addr).Allow() {
+		return false
+	}
+	return l.global == nil || l.global.Allow()
+}
+
+// bucket returns the token bucket of the IP address of addr, creating it if it does not yet exist.
+func (l *loginLimiter) bucket(addr net.Addr) *internal.TokenBucket {
+	ip := addr.String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if time.Since(l.pruned) > time.Minute {
+		// Buckets that have been refilled completely behave exactly like new ones, so they are removed to
+		// prevent the map from growing indefinitely.
+		for ip, bucket := range l.buckets {
+			if bucket.Full() {
+				delete(l.buckets, ip)
+			}
+		}
+		l.pruned = time.Now()
+	}
+
+	bucket, ok := l.buckets[ip]
+	if !ok {
+		bucket = internal.NewTokenBucket(l.rate, l.burst)
+		l.buckets[ip] = bucket
+	}
+	return bucket
+}
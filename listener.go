@@ -0,0 +1,204 @@
+package spectrum
+
+import (
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/spectrum-proxy/spectrum/motd"
+	"github.com/spectrum-proxy/spectrum/version"
+	"net"
+	"sort"
+	"sync"
+)
+
+// defaultListener is the name of the listener started on the Addr of the Opts.
+const defaultListener = "default"
+
+// ListenerOpts configures an additional address the proxy listens on.
+type ListenerOpts struct {
+	// Addr is the address to listen on.
+	Addr string `yaml:"addr"`
+	// MOTD holds the lines shown in the server list to players pinging this address. If empty, the MOTD of
+	// the proxy is shown.
+	MOTD []string `yaml:"motd"`
+	// Disabled starts the listener disabled. It may be enabled through Spectrum.SetListenerEnabled.
+	Disabled bool `yaml:"disabled"`
+}
+
+// listener is an address the proxy listens on. Players connecting to all listeners are accepted through
+// Spectrum.Accept and share the same session registry.
+type listener struct {
+	name   string
+	addr   string
+	config minecraft.ListenConfig
+	motd   *motd.Provider
+
+	mu sync.Mutex
+	l  *minecraft.Listener
+}
+
+// AddListener starts listening on the address passed under the name passed, using the ListenConfig passed,
+// including its StatusProvider. Players connecting to it are returned by Accept. If a listener with the name
+// passed exists, it is replaced.
+func (s *Spectrum) AddListener(name, addr string, config minecraft.ListenConfig) error {
+	l := &listener{name: name, addr: addr, config: s.prepareListenConfig(config)}
+	if err := s.startListener(l); err != nil {
+		return err
+	}
+	s.listenMu.Lock()
+	previous := s.listeners[name]
+	s.listeners[name] = l
+	s.listenMu.Unlock()
+	if previous != nil {
+		previous.close()
+	}
+	return nil
+}
+
+// RemoveListener stops listening on the listener with the name passed and removes it. Sessions accepted
+// through it are not affected.
+func (s *Spectrum) RemoveListener(name string) error {
+	s.listenMu.Lock()
+	l, ok := s.listeners[name]
+	delete(s.listeners, name)
+	s.listenMu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown listener %v", name)
+	}
+	l.close()
+	return nil
+}
+
+// SetListenerEnabled enables or disables the listener with the name passed. A disabled listener stops
+// listening on its address and no longer shows up in the server list, until it is enabled again. Sessions
+// accepted through it are not affected.
+func (s *Spectrum) SetListenerEnabled(name string, enabled bool) error {
+	s.listenMu.Lock()
+	l, ok := s.listeners[name]
+	s.listenMu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown listener %v", name)
+	}
+	if !enabled {
+		l.close()
+		s.logger.Info("Disabled listener", "name", name)
+		return nil
+	}
+	return s.startListener(l)
+}
+
+// Listeners returns the addresses of all listeners of the proxy that are enabled, keyed by their name.
+func (s *Spectrum) Listeners() map[string]net.Addr {
+	s.listenMu.Lock()
+	defer s.listenMu.Unlock()
+
+	addrs := make(map[string]net.Addr, len(s.listeners))
+	for name, l := range s.listeners {
+		if addr := l.netAddr(); addr != nil {
+			addrs[name] = addr
+		}
+	}
+	return addrs
+}
+
+// listenAll starts the default listener on the Addr of the Opts and the additional listeners in the Opts,
+// using the ListenConfig passed.
+func (s *Spectrum) listenAll(config minecraft.ListenConfig) error {
+	if err := s.AddListener(defaultListener, s.opts.Addr, config); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(s.opts.Listeners))
+	for name := range s.opts.Listeners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		opts := s.opts.Listeners[name]
+		conf := config
+		var provider *motd.Provider
+		if len(opts.MOTD) > 0 {
+			provider = motd.New(opts.MOTD...)
+			provider.SetPlayerCounter(s.registry.Count)
+			provider.SetMaxPlayers(s.opts.MaxPlayers - s.opts.PrioritySlots)
+			provider.SetFullLines(s.opts.MOTDFull...)
+			conf.StatusProvider = provider
+		}
+
+		l := &listener{name: name, addr: opts.Addr, config: s.prepareListenConfig(conf), motd: provider}
+		if !opts.Disabled {
+			if err := s.startListener(l); err != nil {
+				return err
+			}
+		}
+		s.listenMu.Lock()
+		s.listeners[name] = l
+		s.listenMu.Unlock()
+	}
+	return nil
+}
+
+// prepareListenConfig fills in the protocols and resource packs of the ListenConfig passed from the Opts of
+// the proxy, unless they were set explicitly.
+func (s *Spectrum) prepareListenConfig(config minecraft.ListenConfig) minecraft.ListenConfig {
+	if s.opts.MultiVersion && len(config.AcceptedProtocols) == 0 {
+		config.AcceptedProtocols = version.Protocols()
+	}
+	if len(config.ResourcePacks) == 0 {
+		config.ResourcePacks = s.packs.Packs()
+		config.TexturePacksRequired = config.TexturePacksRequired || s.opts.ForceResourcePacks
+	}
+	return config
+}
+
+// startListener starts listening on the listener passed, if it is not already listening, and forwards the
+// connections it accepts to Accept.
+func (s *Spectrum) startListener(l *listener) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.l != nil {
+		return nil
+	}
+
+	ml, err := l.config.Listen(s.network, l.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %v: %v", l.addr, err)
+	}
+	l.l = ml
+	s.logger.Info("Started listener", "name", l.name, "addr", ml.Addr())
+
+	go func() {
+		for {
+			conn, err := ml.Accept()
+			if err != nil {
+				return
+			}
+			select {
+			case s.incoming <- conn:
+			case <-s.closed:
+				_ = conn.Close()
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// close stops listening on the listener, if it is listening.
+func (l *listener) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.l != nil {
+		_ = l.l.Close()
+		l.l = nil
+	}
+}
+
+// netAddr returns the address the listener is listening on, or nil if it is disabled.
+func (l *listener) netAddr() net.Addr {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.l == nil {
+		return nil
+	}
+	return l.l.Addr()
+}
@@ -0,0 +1,62 @@
+package spectrum
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/locale"
+	"log/slog"
+)
+
+// Keys of the messages players are disconnected with when their connection is rejected. Their translations
+// may be overwritten in the translations directory.
+const (
+	messageMaintenance    = "spectrum.maintenance"
+	messageNotWhitelisted = "spectrum.not_whitelisted"
+	messageServerFull     = "spectrum.server_full"
+	messageProxyFull      = "spectrum.proxy_full"
+	messagePriorityKick   = "spectrum.priority_kick"
+	messageInvalidClient  = "spectrum.invalid_client_data"
+	messageDialFailed     = "spectrum.dial_failed"
+	messageBanCheckFailed = "spectrum.ban_check_failed"
+)
+
+// defaultTranslations holds the translations of the messages of the proxy in locale.DefaultLocale.
+var defaultTranslations = map[string]string{
+	messageMaintenance:    "The server is currently under maintenance, please try again later.",
+	messageNotWhitelisted: "You are not whitelisted on this server.",
+	messageServerFull:     "The server is full, please try again later.",
+	messageProxyFull:      "The network is full, please try again later.",
+	messagePriorityKick:   "You were disconnected to make room for a player with a reserved slot.",
+	messageInvalidClient:  "Your skin or device data is not supported by this server.",
+	messageDialFailed:     "Could not connect to the server (%v), please try again later.",
+	messageBanCheckFailed: "Your login could not be verified, please try again later.",
+}
+
+// newTranslator returns the translator of the messages of the proxy, loading translations from the directory
+// configured in the Opts passed, if any.
+func newTranslator(logger *slog.Logger, opts *Opts) *locale.Translator {
+	translator := locale.New(opts.DefaultLocale)
+	translator.Add(locale.DefaultLocale, defaultTranslations)
+	if opts.TranslationsDir != "" {
+		if err := translator.LoadDirectory(opts.TranslationsDir); err != nil {
+			logger.Error("Failed to load translations", "err", err)
+		}
+	}
+	return translator
+}
+
+// Translator returns the locale.Translator used to translate messages sent by the proxy to the locale of
+// players.
+func (s *Spectrum) Translator() *locale.Translator {
+	return s.locales
+}
+
+// disconnect disconnects the connection passed before a session is created for it, translating the message
+// passed to the locale of the player if it is the key of a translated message.
+func (s *Spectrum) disconnect(conn *minecraft.Conn, message string) {
+	if translated, ok := s.locales.Translate(conn.ClientData().LanguageCode, message); ok {
+		message = translated
+	}
+	_ = conn.WritePacket(&packet.Disconnect{Message: message})
+	_ = conn.Close()
+}
@@ -0,0 +1,141 @@
+// Package locale translates messages sent by the proxy to the language of players. Translations are
+// templates keyed by a message key, grouped per locale, and are usually loaded from one file per locale.
+package locale
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the locale used for players whose locale has no translation of a message.
+const DefaultLocale = "en_US"
+
+// Translator holds the translations of messages in any number of locales. A Translator is safe for concurrent
+// use.
+type Translator struct {
+	fallback string
+
+	mu      sync.RWMutex
+	locales map[string]map[string]string
+}
+
+// New returns a new Translator without translations. Messages not translated in the locale of a player are
+// translated in the fallback locale passed. If fallback is empty, DefaultLocale is used.
+func New(fallback string) *Translator {
+	if fallback == "" {
+		fallback = DefaultLocale
+	}
+	return &Translator{fallback: Normalize(fallback), locales: make(map[string]map[string]string)}
+}
+
+// Add adds the translations passed, keyed by their message key, to the locale passed. Existing translations
+// of the same keys are replaced.
+func (t *Translator) Add(locale string, translations map[string]string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	locale = Normalize(locale)
+	messages, ok := t.locales[locale]
+	if !ok {
+		messages = make(map[string]string, len(translations))
+		t.locales[locale] = messages
+	}
+	for key, template := range translations {
+		messages[key] = template
+	}
+}
+
+// LoadDirectory loads the translations from all YAML files in the directory passed. Every file holds the
+// translations of a single locale, which is the name of the file without extension, such as en_US.yml. Files
+// map message keys to templates, which may contain fmt verbs for the arguments of the message.
+func (t *Translator) LoadDirectory(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read translations directory: %v", err)
+	}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yml" && ext != ".yaml") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read translations %v: %v", entry.Name(), err)
+		}
+		var translations map[string]string
+		if err := yaml.Unmarshal(data, &translations); err != nil {
+			return fmt.Errorf("failed to decode translations %v: %v", entry.Name(), err)
+		}
+		t.Add(strings.TrimSuffix(entry.Name(), ext), translations)
+	}
+	return nil
+}
+
+// Translate translates the message with the key passed to the locale passed, formatting its template with
+// the arguments passed. If the locale has no translation, the translation of the language of the locale in
+// any region is used, and otherwise that of the fallback locale. The second return value is false if the
+// message has no translation at all.
+func (t *Translator) Translate(locale, key string, args ...any) (string, bool) {
+	template, ok := t.lookup(Normalize(locale), key)
+	if !ok {
+		return "", false
+	}
+	if len(args) == 0 {
+		return template, true
+	}
+	return fmt.Sprintf(template, args...), true
+}
+
+// Has returns true if the message with the key passed has a translation in the fallback locale.
+func (t *Translator) Has(key string) bool {
+	_, ok := t.lookup(t.fallback, key)
+	return ok
+}
+
+// Locales returns all locales that have at least one translation.
+func (t *Translator) Locales() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	locales := make([]string, 0, len(t.locales))
+	for locale := range t.locales {
+		locales = append(locales, locale)
+	}
+	return locales
+}
+
+// lookup looks up the template of the message with the key passed for the locale passed, falling back to
+// other regions of the same language and then to the fallback locale.
+func (t *Translator) lookup(locale, key string) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if template, ok := t.locales[locale][key]; ok {
+		return template, true
+	}
+	language, _, _ := strings.Cut(locale, "_")
+	for other, messages := range t.locales {
+		if strings.HasPrefix(other, language+"_") || other == language {
+			if template, ok := messages[key]; ok {
+				return template, true
+			}
+		}
+	}
+	template, ok := t.locales[t.fallback][key]
+	return template, ok
+}
+
+// Normalize normalizes the locale passed to the format used by Minecraft, such as en_US. Both hyphens and
+// underscores are accepted as separator.
+func Normalize(locale string) string {
+	language, region, ok := strings.Cut(strings.ReplaceAll(locale, "-", "_"), "_")
+	if !ok {
+		return strings.ToLower(language)
+	}
+	return strings.ToLower(language) + "_" + strings.ToUpper(region)
+}
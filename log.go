@@ -0,0 +1,21 @@
+package spectrum
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// NewLogger returns a logger writing to stderr with the level and format configured in the Opts passed.
+func NewLogger(opts *Opts) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(opts.LogLevel)); err != nil {
+		level = slog.LevelInfo
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	if strings.EqualFold(opts.LogFormat, "json") {
+		return slog.New(slog.NewJSONHandler(os.Stderr, handlerOpts))
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, handlerOpts))
+}
@@ -0,0 +1,89 @@
+package spectrum
+
+import "fmt"
+
+// SetMaintenance enables or disables maintenance mode. While in maintenance mode, new connections are
+// rejected. Sessions that were already accepted are not affected.
+func (s *Spectrum) SetMaintenance(enabled bool) {
+	s.maintenance.Store(enabled)
+	s.logger.Info("Changed maintenance mode", "enabled", enabled)
+}
+
+// Maintenance returns true if the proxy is in maintenance mode.
+func (s *Spectrum) Maintenance() bool {
+	return s.maintenance.Load()
+}
+
+// SetConfigLoader sets the function used to load new Opts when the proxy is reloaded through Reload.
+func (s *Spectrum) SetConfigLoader(loader func() (*Opts, error)) {
+	s.loader = loader
+}
+
+// Reload reloads the configuration of the proxy. If a config loader is set, the Opts it returns replace the
+// current Opts: the servers, MOTD, player limits, login rate limits and secrets shared with servers are applied
+// immediately, and session options apply to sessions accepted after the call. Options such as the addresses
+// listened on require a restart. The whitelist file and translations are reloaded if configured.
+func (s *Spectrum) Reload() error {
+	if s.loader != nil {
+		opts, err := s.loader()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+		s.servers.Set(opts.Servers)
+
+		s.optsMu.Lock()
+		s.opts = opts
+		s.limiter = newLoginLimiter(opts)
+		s.optsMu.Unlock()
+		s.updateMOTD(opts)
+		s.updateSecrets(opts)
+
+		if opts.TranslationsDir != "" {
+			if err := s.locales.LoadDirectory(opts.TranslationsDir); err != nil {
+				return fmt.Errorf("failed to reload translations: %v", err)
+			}
+		}
+	}
+
+	if err := s.whitelist.Reload(); err != nil {
+		return fmt.Errorf("failed to reload whitelist: %v", err)
+	}
+	s.logger.Info("Reloaded configuration")
+	return nil
+}
+
+// updateMOTD updates the lines and maximum player count shown in the server list by the MOTD provider of the
+// proxy, if it uses one, to those of the Opts passed.
+func (s *Spectrum) updateMOTD(opts *Opts) {
+	if s.motd == nil {
+		return
+	}
+	s.motd.SetLines(opts.MOTD...)
+	s.motd.SetFullLines(opts.MOTDFull...)
+	s.motd.SetMaxPlayers(opts.MaxPlayers - opts.PrioritySlots)
+
+	s.listenMu.Lock()
+	defer s.listenMu.Unlock()
+	for name, l := range s.listeners {
+		if lOpts, ok := opts.Listeners[name]; ok && l.motd != nil && len(lOpts.MOTD) > 0 {
+			l.motd.SetLines(lOpts.MOTD...)
+			l.motd.SetFullLines(opts.MOTDFull...)
+			l.motd.SetMaxPlayers(opts.MaxPlayers - opts.PrioritySlots)
+		}
+	}
+}
+
+// options returns the current Opts of the proxy.
+func (s *Spectrum) options() *Opts {
+	s.optsMu.RLock()
+	defer s.optsMu.RUnlock()
+	return s.opts
+}
+
+// maintenanceMessage returns the message players are disconnected with during maintenance.
+func (s *Spectrum) maintenanceMessage() string {
+	if message := s.options().MaintenanceMessage; message != "" {
+		return message
+	}
+	return messageMaintenance
+}
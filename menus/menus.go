@@ -0,0 +1,73 @@
+package menus
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/spectrum-proxy/spectrum/session"
+)
+
+// Button is an item in a Menu that runs a function when clicked.
+type Button struct {
+	// Item is the item shown in the slot of the button.
+	Item protocol.ItemInstance
+	// OnClick is called when the player clicks the button. It may be nil.
+	OnClick func(s *session.Session)
+	// Close closes the menu when the button is clicked, before OnClick is called.
+	Close bool
+}
+
+// Menu builds chest menus out of buttons, such as server selectors, that may be opened for any number of
+// players. A Menu must not be changed while it is being opened.
+type Menu struct {
+	title   string
+	buttons map[int]Button
+	onClose func(s *session.Session)
+}
+
+// New returns a new Menu without buttons with the title passed.
+func New(title string) *Menu {
+	return &Menu{title: title, buttons: make(map[int]Button)}
+}
+
+// With sets the button in the slot passed and returns the Menu. Slots outside the size of a menu, which is
+// session.MenuSize, are ignored.
+func (m *Menu) With(slot int, b Button) *Menu {
+	if slot >= 0 && slot < session.MenuSize {
+		m.buttons[slot] = b
+	}
+	return m
+}
+
+// OnClose sets the function called when the menu is closed and returns the Menu.
+func (m *Menu) OnClose(f func(s *session.Session)) *Menu {
+	m.onClose = f
+	return m
+}
+
+// Open opens the menu for the session passed, closing the menu it has open, if any.
+func (m *Menu) Open(s *session.Session) {
+	items := make([]protocol.ItemInstance, session.MenuSize)
+	for slot, b := range m.buttons {
+		items[slot] = b.Item
+	}
+	s.OpenMenu(session.Menu{
+		Title:   m.title,
+		Items:   items,
+		OnClick: m.click,
+		OnClose: m.onClose,
+	})
+}
+
+// click runs the button in the slot clicked, playing a click sound to the player.
+func (m *Menu) click(s *session.Session, slot int) {
+	b, ok := m.buttons[slot]
+	if !ok {
+		return
+	}
+	s.PlaySound("random.click")
+	if b.Close {
+		s.CloseMenu()
+	}
+	if b.OnClick != nil {
+		b.OnClick(s)
+	}
+}
@@ -0,0 +1,43 @@
+package spectrum
+
+import (
+	"github.com/spectrum-proxy/spectrum/metrics"
+	"github.com/spectrum-proxy/spectrum/session"
+	"net/http"
+)
+
+var (
+	connectionsTotal = metrics.NewCounter("spectrum_connections_total", "Amount of connections accepted by the listener.")
+	rejectionsTotal  = metrics.NewCounterVec("spectrum_rejected_connections_total", "Amount of connections rejected before creating a session.", "reason")
+)
+
+// registerMetrics registers the gauges reporting the sessions in the registry passed.
+func registerMetrics(registry *session.Registry) {
+	metrics.NewGaugeFunc("spectrum_sessions", "Amount of sessions.", func() float64 {
+		return float64(registry.Count())
+	})
+	metrics.NewGaugeVecFunc("spectrum_server_sessions", "Amount of sessions per server.", "server", func() map[string]float64 {
+		counts := registry.ServerCounts()
+		values := make(map[string]float64, len(counts))
+		for addr, count := range counts {
+			values[addr] = float64(count)
+		}
+		return values
+	})
+}
+
+// serveMetrics serves the metrics of the proxy over HTTP on the configured address, if any.
+func (s *Spectrum) serveMetrics() {
+	if s.opts.MetricsAddr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	s.metrics = &http.Server{Addr: s.opts.MetricsAddr, Handler: mux}
+	go func() {
+		if err := s.metrics.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			s.logger.Error("Failed to serve metrics", "err", err)
+		}
+	}()
+}
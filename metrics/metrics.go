@@ -0,0 +1,248 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// collector is a metric that can be written in the Prometheus text exposition format.
+type collector interface {
+	write(w io.Writer)
+}
+
+var (
+	collectorsMu sync.RWMutex
+	// collectors holds all registered metrics, keyed by their name.
+	collectors = map[string]collector{}
+)
+
+// register registers a metric under the name passed, replacing any metric previously registered under it.
+func register(name string, c collector) {
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+	collectors[name] = c
+}
+
+// Write writes all registered metrics to the writer passed in the Prometheus text exposition format.
+func Write(w io.Writer) {
+	collectorsMu.RLock()
+	names := make([]string, 0, len(collectors))
+	for name := range collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	cs := make([]collector, 0, len(names))
+	for _, name := range names {
+		cs = append(cs, collectors[name])
+	}
+	collectorsMu.RUnlock()
+
+	buf := bufio.NewWriter(w)
+	for _, c := range cs {
+		c.write(buf)
+	}
+	_ = buf.Flush()
+}
+
+// Handler returns an http.Handler serving all registered metrics, which may be scraped by Prometheus.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		Write(w)
+	})
+}
+
+// Counter is a metric that only increases.
+type Counter struct {
+	name, help string
+	v          atomic.Uint64
+}
+
+// NewCounter registers and returns a new Counter with the name and help text passed.
+func NewCounter(name, help string) *Counter {
+	c := &Counter{name: name, help: help}
+	register(name, c)
+	return c
+}
+
+// Inc increments the counter by one.
+func (c *Counter) Inc() {
+	c.v.Add(1)
+}
+
+// Add increments the counter by n.
+func (c *Counter) Add(n uint64) {
+	c.v.Add(n)
+}
+
+// Value returns the current value of the counter.
+func (c *Counter) Value() uint64 {
+	return c.v.Load()
+}
+
+func (c *Counter) write(w io.Writer) {
+	writeHeader(w, c.name, c.help, "counter")
+	_, _ = fmt.Fprintf(w, "%s %d\n", c.name, c.v.Load())
+}
+
+// CounterVec is a set of counters that are distinguished by the value of a label.
+type CounterVec struct {
+	name, help, label string
+
+	mu       sync.RWMutex
+	counters map[string]*Counter
+}
+
+// NewCounterVec registers and returns a new CounterVec with the name, help text and label name passed.
+func NewCounterVec(name, help, label string) *CounterVec {
+	c := &CounterVec{name: name, help: help, label: label, counters: make(map[string]*Counter)}
+	register(name, c)
+	return c
+}
+
+// With returns the counter with the label value passed, creating it if it does not yet exist.
+func (c *CounterVec) With(value string) *Counter {
+	c.mu.RLock()
+	counter, ok := c.counters[value]
+	c.mu.RUnlock()
+	if ok {
+		return counter
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if counter, ok := c.counters[value]; ok {
+		return counter
+	}
+	counter = &Counter{name: c.name}
+	c.counters[value] = counter
+	return counter
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.RLock()
+	values := make(map[string]float64, len(c.counters))
+	for value, counter := range c.counters {
+		values[value] = float64(counter.v.Load())
+	}
+	c.mu.RUnlock()
+
+	writeHeader(w, c.name, c.help, "counter")
+	writeLabelled(w, c.name, c.label, values)
+}
+
+// GaugeFunc is a metric whose value is computed by a function every time it is collected.
+type GaugeFunc struct {
+	name, help string
+	f          func() float64
+}
+
+// NewGaugeFunc registers a new GaugeFunc with the name and help text passed, collecting its value from f.
+func NewGaugeFunc(name, help string, f func() float64) *GaugeFunc {
+	g := &GaugeFunc{name: name, help: help, f: f}
+	register(name, g)
+	return g
+}
+
+func (g *GaugeFunc) write(w io.Writer) {
+	writeHeader(w, g.name, g.help, "gauge")
+	_, _ = fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.f()))
+}
+
+// GaugeVecFunc is a set of gauges distinguished by the value of a label, whose values are computed by a
+// function every time they are collected.
+type GaugeVecFunc struct {
+	name, help, label string
+	f                 func() map[string]float64
+}
+
+// NewGaugeVecFunc registers a new GaugeVecFunc with the name, help text and label name passed, collecting
+// its values, keyed by label value, from f.
+func NewGaugeVecFunc(name, help, label string, f func() map[string]float64) *GaugeVecFunc {
+	g := &GaugeVecFunc{name: name, help: help, label: label, f: f}
+	register(name, g)
+	return g
+}
+
+func (g *GaugeVecFunc) write(w io.Writer) {
+	writeHeader(w, g.name, g.help, "gauge")
+	writeLabelled(w, g.name, g.label, g.f())
+}
+
+// Histogram is a metric that counts observations in configurable buckets.
+type Histogram struct {
+	name, help string
+	buckets    []float64
+
+	counts []atomic.Uint64
+	count  atomic.Uint64
+	// sum holds the sum of all observations as the bits of a float64.
+	sum atomic.Uint64
+}
+
+// NewHistogram registers and returns a new Histogram with the name, help text and upper bounds of buckets
+// passed. The buckets must be sorted in ascending order.
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	h := &Histogram{name: name, help: help, buckets: buckets, counts: make([]atomic.Uint64, len(buckets))}
+	register(name, h)
+	return h
+}
+
+// Observe adds an observation to the histogram.
+func (h *Histogram) Observe(v float64) {
+	for i, bound := range h.buckets {
+		if v <= bound {
+			h.counts[i].Add(1)
+			break
+		}
+	}
+	h.count.Add(1)
+	for {
+		old := h.sum.Load()
+		if h.sum.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+func (h *Histogram) write(w io.Writer) {
+	writeHeader(w, h.name, h.help, "histogram")
+	var cumulative uint64
+	for i, bound := range h.buckets {
+		cumulative += h.counts[i].Load()
+		_, _ = fmt.Fprintf(w, "%s_bucket{le=%q} %d\n", h.name, formatFloat(bound), cumulative)
+	}
+	count := h.count.Load()
+	_, _ = fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", h.name, count)
+	_, _ = fmt.Fprintf(w, "%s_sum %s\n", h.name, formatFloat(math.Float64frombits(h.sum.Load())))
+	_, _ = fmt.Fprintf(w, "%s_count %d\n", h.name, count)
+}
+
+// writeHeader writes the HELP and TYPE lines of a metric.
+func writeHeader(w io.Writer, name, help, typ string) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// writeLabelled writes a sample for every label value passed, sorted by label value.
+func writeLabelled(w io.Writer, name, label string, values map[string]float64) {
+	keys := make([]string, 0, len(values))
+	for value := range values {
+		keys = append(keys, value)
+	}
+	sort.Strings(keys)
+	for _, value := range keys {
+		_, _ = fmt.Fprintf(w, "%s{%s=%q} %s\n", name, label, value, formatFloat(values[value]))
+	}
+}
+
+// formatFloat formats a float the way Prometheus expects it.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
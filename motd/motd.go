@@ -0,0 +1,140 @@
+package motd
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft"
+	"sync"
+	"time"
+)
+
+// Provider is a minecraft.ServerStatusProvider that controls the status shown in the server list. It rotates
+// through a list of MOTD lines, may report player counts from a custom source and allows a hook to adjust
+// the status on every update.
+// Note that the status is shared by all clients pinging the listener, so region specific lines are chosen
+// based on the region of the proxy set through SetRegion rather than that of the client.
+type Provider struct {
+	mu sync.RWMutex
+
+	lines       []string
+	fullLines   []string
+	regionLines map[string][]string
+	region      string
+	interval    time.Duration
+
+	playerCount func() int
+	maxPlayers  int
+	hook        func(status *minecraft.ServerStatus)
+
+	start time.Time
+}
+
+// New returns a Provider that rotates through the lines passed every 5 seconds.
+func New(lines ...string) *Provider {
+	return &Provider{
+		lines:       lines,
+		regionLines: make(map[string][]string),
+		interval:    time.Second * 5,
+		start:       time.Now(),
+	}
+}
+
+// SetLines sets the MOTD lines rotated through.
+func (p *Provider) SetLines(lines ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lines = lines
+}
+
+// SetFullLines sets the MOTD lines rotated through while the player count shown is at least the maximum
+// player count shown, replacing all other lines. If empty, the other lines are shown while full.
+func (p *Provider) SetFullLines(lines ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fullLines = lines
+}
+
+// SetRegionLines sets the MOTD lines rotated through when the region of the proxy is the region passed,
+// replacing the lines set through SetLines.
+func (p *Provider) SetRegionLines(region string, lines ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.regionLines[region] = lines
+}
+
+// SetRegion sets the region of the proxy, which selects the lines set through SetRegionLines.
+func (p *Provider) SetRegion(region string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.region = region
+}
+
+// SetInterval sets the interval at which the next MOTD line is shown.
+func (p *Provider) SetInterval(interval time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.interval = interval
+}
+
+// SetPlayerCounter sets the function returning the player count shown, such as the Count method of a
+// session.Registry. If nil, the player count of the listener is shown.
+func (p *Provider) SetPlayerCounter(counter func() int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.playerCount = counter
+}
+
+// SetMaxPlayers sets the maximum player count shown. If zero, the maximum of the listener is shown.
+func (p *Provider) SetMaxPlayers(maxPlayers int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxPlayers = maxPlayers
+}
+
+// SetHook sets a function called every time the status is computed. The hook may modify the status before
+// it is shown.
+func (p *Provider) SetHook(hook func(status *minecraft.ServerStatus)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.hook = hook
+}
+
+// ServerStatus ...
+func (p *Provider) ServerStatus(playerCount, maxPlayers int) minecraft.ServerStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.playerCount != nil {
+		playerCount = p.playerCount()
+	}
+	if p.maxPlayers != 0 {
+		maxPlayers = p.maxPlayers
+	}
+
+	lines := p.lines
+	if regionLines, ok := p.regionLines[p.region]; ok && len(regionLines) > 0 {
+		lines = regionLines
+	}
+	if maxPlayers > 0 && playerCount >= maxPlayers && len(p.fullLines) > 0 {
+		lines = p.fullLines
+	}
+
+	status := minecraft.ServerStatus{
+		ServerName:  p.line(lines),
+		PlayerCount: playerCount,
+		MaxPlayers:  maxPlayers,
+	}
+	if p.hook != nil {
+		p.hook(&status)
+	}
+	return status
+}
+
+// line returns the line of the lines passed currently shown. line must be called with mu held.
+func (p *Provider) line(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	if p.interval <= 0 {
+		return lines[0]
+	}
+	return lines[int(time.Since(p.start)/p.interval)%len(lines)]
+}
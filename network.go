@@ -0,0 +1,77 @@
+package spectrum
+
+import (
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/spectrum-proxy/spectrum/version"
+	"net"
+	"time"
+)
+
+// registerNetwork registers the minecraft.Network the listeners of the proxy listen on and returns its name.
+// The network is RakNet, but rejects connections exceeding the login rate limit as soon as they are accepted,
+// before the login and encryption handshake is performed for them.
+func (s *Spectrum) registerNetwork() string {
+	name := fmt.Sprintf("spectrum-%p", s)
+	minecraft.RegisterNetwork(name, network{s: s})
+	return name
+}
+
+// network is a RakNet minecraft.Network whose listeners apply the login rate limit of the proxy.
+type network struct {
+	minecraft.RakNet
+	s *Spectrum
+}
+
+// Listen ...
+func (n network) Listen(address string) (minecraft.NetworkListener, error) {
+	l, err := n.RakNet.Listen(address)
+	if err != nil {
+		return nil, err
+	}
+	return networkListener{NetworkListener: l, s: n.s}, nil
+}
+
+// networkListener is a minecraft.NetworkListener closing connections that exceed the login rate limit of the
+// proxy. As the connections are closed before the login, the client is not shown a message.
+type networkListener struct {
+	minecraft.NetworkListener
+	s *Spectrum
+}
+
+// Accept ...
+func (l networkListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.NetworkListener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		l.s.optsMu.RLock()
+		limiter := l.s.limiter
+		l.s.optsMu.RUnlock()
+		if limiter == nil || limiter.allow(conn.RemoteAddr()) {
+			return networkConn{Conn: conn}, nil
+		}
+		rejectionsTotal.With("rate_limit").Inc()
+		_ = conn.Close()
+	}
+}
+
+// networkConn is a connection accepted by a networkListener. It makes the version package forget the protocol
+// of the connection once it is closed, as connections closed while logging in are never accepted by the proxy.
+type networkConn struct {
+	net.Conn
+}
+
+// Close ...
+func (c networkConn) Close() error {
+	version.Forget(c.RemoteAddr())
+	return c.Conn.Close()
+}
+
+// Latency returns the latency of the RakNet connection, which minecraft.Conn requires of the connections it
+// wraps.
+func (c networkConn) Latency() time.Duration {
+	return c.Conn.(interface{ Latency() time.Duration }).Latency()
+}
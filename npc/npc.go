@@ -0,0 +1,141 @@
+package npc
+
+import (
+	"github.com/spectrum-proxy/spectrum/event"
+	"github.com/spectrum-proxy/spectrum/session"
+	"slices"
+	"sync"
+)
+
+// definition is an NPC added to a Manager along with the servers it is shown on.
+type definition struct {
+	npc     session.NPC
+	servers []string
+}
+
+// Manager shows NPCs to all players on the proxy, such as server selectors in lobbies. NPCs may be limited to
+// specific servers, in which case they are spawned whenever a player joins one of those servers and removed
+// when they leave it. NPCs shown on all servers are kept across transfers.
+type Manager struct {
+	registry *session.Registry
+
+	mu      sync.Mutex
+	defs    map[int]definition
+	spawned map[*session.Session]map[int]int64
+	nextID  int
+
+	unsubscribe func()
+}
+
+// New returns a new Manager showing NPCs to the sessions in the registry passed. NPCs are spawned for sessions
+// when they start or transfer, as published on the bus passed.
+func New(registry *session.Registry, events *event.Bus[session.Event]) *Manager {
+	m := &Manager{
+		registry: registry,
+		defs:     make(map[int]definition),
+		spawned:  make(map[*session.Session]map[int]int64),
+	}
+	m.unsubscribe = events.Subscribe(m.handleEvent)
+	return m
+}
+
+// Close stops the Manager from following the events of sessions. NPCs already spawned are kept.
+func (m *Manager) Close() {
+	m.unsubscribe()
+}
+
+// Add adds an NPC shown to players on the servers with the addresses passed, or on all servers if none are
+// passed, and returns its ID. The Persistent field of the NPC is ignored. The NPC is spawned immediately for
+// players already on those servers.
+func (m *Manager) Add(npc session.NPC, servers ...string) int {
+	npc.Persistent = len(servers) == 0
+
+	m.mu.Lock()
+	m.nextID++
+	id := m.nextID
+	def := definition{npc: npc, servers: servers}
+	m.defs[id] = def
+	m.mu.Unlock()
+
+	m.registry.Range(func(s *session.Session) bool {
+		if def.shownOn(s.ServerAddr()) {
+			m.spawn(s, id, def)
+		}
+		return true
+	})
+	return id
+}
+
+// Remove removes the NPC with the ID passed for all players.
+func (m *Manager) Remove(id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.defs, id)
+	for s, spawned := range m.spawned {
+		if npcID, ok := spawned[id]; ok {
+			s.RemoveNPC(npcID)
+			delete(spawned, id)
+		}
+	}
+}
+
+// handleEvent spawns the NPCs of the server a session joins and forgets the sessions that are closed.
+func (m *Manager) handleEvent(e session.Event) {
+	s := e.Session()
+	switch e := e.(type) {
+	case session.SessionStart:
+		m.spawnAll(s, s.ServerAddr())
+	case session.TransferEnd:
+		if e.Err != nil {
+			return
+		}
+		m.mu.Lock()
+		for id := range m.spawned[s] {
+			// NPCs that are not persistent were removed by the session during the transfer.
+			if def, ok := m.defs[id]; !ok || !def.npc.Persistent {
+				delete(m.spawned[s], id)
+			}
+		}
+		m.mu.Unlock()
+		m.spawnAll(s, e.To)
+	case session.SessionClose:
+		m.mu.Lock()
+		delete(m.spawned, s)
+		m.mu.Unlock()
+	}
+}
+
+// spawnAll spawns the NPCs shown on the server with the address passed for the session passed, if they are not
+// already spawned.
+func (m *Manager) spawnAll(s *session.Session, addr string) {
+	m.mu.Lock()
+	defs := make(map[int]definition)
+	for id, def := range m.defs {
+		if _, ok := m.spawned[s][id]; !ok && def.shownOn(addr) {
+			defs[id] = def
+		}
+	}
+	m.mu.Unlock()
+
+	for id, def := range defs {
+		m.spawn(s, id, def)
+	}
+}
+
+// spawn spawns the NPC with the ID passed for the session passed.
+func (m *Manager) spawn(s *session.Session, id int, def definition) {
+	npcID := s.SpawnNPC(def.npc)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.spawned[s] == nil {
+		m.spawned[s] = make(map[int]int64)
+	}
+	m.spawned[s][id] = npcID
+}
+
+// shownOn checks if the NPC is shown on the server with the address passed.
+func (d definition) shownOn(addr string) bool {
+	return len(d.servers) == 0 || slices.Contains(d.servers, addr)
+}
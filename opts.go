@@ -1,16 +1,288 @@
 package spectrum
 
+import (
+	"github.com/spectrum-proxy/spectrum/server"
+	"github.com/spectrum-proxy/spectrum/session"
+)
+
 type Opts struct {
 	// Addr is the address to listen on.
 	Addr string `yaml:"addr"`
+	// Listeners holds additional addresses to listen on, keyed by a name used to enable and disable them at
+	// runtime through Spectrum.SetListenerEnabled. Players connecting to any address join the same proxy.
+	Listeners map[string]ListenerOpts `yaml:"listeners"`
 	// LatencyInterval is the interval at which the latency of the connection is updated in milliseconds.
 	// The lower the interval, the more accurate the latency will be, but the more bandwidth it will use.
 	LatencyInterval int64 `yaml:"latency_interval"`
+	// LatencyStrategy is the strategy used to measure the latency of clients, either "raknet" or
+	// "network_stack". The latter measures the round-trip time of NetworkStackLatency packets, which includes
+	// the time clients take to process packets. If empty, "raknet" is used.
+	LatencyStrategy string `yaml:"latency_strategy"`
+	// LatencySmoothing is the weight of new samples in the moving average of the latency, between 0 and 1.
+	// Lower values result in a more stable latency. If zero, a default of 0.2 is used.
+	LatencySmoothing float64 `yaml:"latency_smoothing"`
+	// PingDisplay enables showing the latency measured by the proxy next to the names of players in the player
+	// list, as servers behind the proxy cannot measure the latency of players themselves.
+	PingDisplay bool `yaml:"ping_display"`
+	// NetworkPlayerList makes the player list of players show all players connected to the proxy rather than
+	// only those on the same server.
+	NetworkPlayerList bool `yaml:"network_player_list"`
+	// PlayerListHeader is the text shown at the top of the player list of players. If empty, no header is shown.
+	PlayerListHeader string `yaml:"player_list_header"`
+	// PlayerListFooter is the text shown at the bottom of the player list of players. If empty, no footer is
+	// shown.
+	PlayerListFooter string `yaml:"player_list_footer"`
+	// PlayerListRanks holds rank prefixes, as set through session.Session.SetRank, in the order players are
+	// listed in the player list. If empty, players are listed in the order they joined.
+	PlayerListRanks []string `yaml:"player_list_ranks"`
+	// ChatChannels holds the channels over which chat messages of players are bridged between servers, keyed
+	// by their name, enabling chat across the network without plugins on the servers.
+	ChatChannels map[string]session.ChatChannel `yaml:"chat_channels"`
+	// TransferRetries is the amount of times dialing a server is retried during a transfer before the next
+	// fallback server is tried.
+	TransferRetries int `yaml:"transfer_retries"`
+	// TransferBackoff is the time in milliseconds waited before retrying a failed dial during a transfer. The
+	// backoff is doubled after every failed attempt.
+	TransferBackoff int64 `yaml:"transfer_backoff"`
+	// Servers maps the logical names of servers to their addresses. Servers may be referred to by their name
+	// when transferring players.
+	Servers map[string]string `yaml:"servers"`
+	// ConnectSecret is the secret shared with servers that the identity of players is signed with when
+	// connecting to a server. Servers should verify it using packet.Connect.Verify to reject players
+	// connecting to them directly while pretending to be someone else. If empty, the identity is not signed.
+	// Secrets may be rotated by changing them and calling Spectrum.Reload.
+	ConnectSecret string `yaml:"connect_secret"`
+	// ServerSecrets holds secrets used instead of the ConnectSecret for specific servers, keyed by the name or
+	// address of the server.
+	ServerSecrets map[string]string `yaml:"server_secrets"`
+	// ControlAllPlayers allows servers to transfer and message players other than the player a control
+	// request was sent through. It should only be enabled if all servers are trusted.
+	ControlAllPlayers bool `yaml:"control_all_players"`
+	// ClientDataPolicy configures how the ClientData of players, such as their skin, is filtered before it is
+	// forwarded to servers, and whether players whose ClientData is invalid are rejected.
+	ClientDataPolicy session.ClientDataPolicy `yaml:"client_data_policy"`
+	// RiskBanScore is the risk score, as assessed by the detection package from the login data of players, at
+	// which players are banned for using a modified client. Signals raised after joining are available to
+	// handlers through session.Session.Risk. If zero, players are never banned for their risk score.
+	RiskBanScore int `yaml:"risk_ban_score"`
+	// RiskBanDuration is the duration in milliseconds of bans issued for the RiskBanScore. If zero, such bans
+	// are permanent.
+	RiskBanDuration int64 `yaml:"risk_ban_duration"`
+	// ServerCompression configures the compression used for the connections to servers, keyed by the name or
+	// address of the server. Servers on a local network may skip compression entirely, while servers across
+	// the internet may use a low threshold. Servers without an entry compress every packet using flate.
+	ServerCompression map[string]server.Compression `yaml:"server_compression"`
+	// ServerTransports holds the transports used to connect to servers, keyed by the name or address of the
+	// server. The transports "tcp" and "tls" are built in, "aead" is available if a LinkKey is set, and others
+	// may be added using server.RegisterTransport, such as a QUIC transport, which is not built in. Servers
+	// without an entry are connected to over TCP.
+	ServerTransports map[string]string `yaml:"server_transports"`
+	// LinkKey is the key shared with servers used by the "aead" transport, which encrypts the connections to
+	// servers without requiring certificates. Servers accept such connections using server.AEADServer.
+	LinkKey string `yaml:"link_key"`
+	// LinkTLS configures the certificates used by the "tls" transport. If empty, the certificates of servers
+	// are verified against the root certificates of the system.
+	LinkTLS LinkTLS `yaml:"link_tls"`
+	// ServerPools configures pools of spare connections kept open to servers, keyed by the name or address of
+	// the server. Players joining a server with a pool skip connecting to it, reducing the time transfers
+	// take. Names are resolved when the proxy is created.
+	ServerPools map[string]server.PoolConfig `yaml:"server_pools"`
+	// ServerViewDistances holds the maximum view distance in chunks of servers, keyed by the name or address of
+	// the server. Players requesting a larger view distance are limited to it, protecting both servers and
+	// clients from extreme render distances.
+	ServerViewDistances map[string]int32 `yaml:"server_view_distances"`
+	// Limbo enables the limbo server, a void world served by the proxy itself under the address "limbo".
+	// Players are parked in limbo when the server they join cannot be reached, or when the connection to
+	// their server is lost and no fallback server is available, and are moved out once a server is available.
+	Limbo bool `yaml:"limbo"`
+	// LimboMessage is the message sent to players when they are parked in limbo.
+	LimboMessage string `yaml:"limbo_message"`
+	// DialFailureMessage is the message players are disconnected with if the server they join cannot be
+	// reached. It is formatted with the category of the failure: "timeout", "refused", "full" or "error". If
+	// empty, the translation of the "spectrum.dial_failed" message is used.
+	DialFailureMessage string `yaml:"dial_failure_message"`
+	// MaxPlayers is the maximum amount of players on the proxy, including the PrioritySlots. The server list
+	// shows the amount of slots not reserved as the maximum. If zero, there is no limit.
+	MaxPlayers int `yaml:"max_players"`
+	// PrioritySlots is the amount of the MaxPlayers slots reserved for PriorityPlayers.
+	PrioritySlots int `yaml:"priority_slots"`
+	// PriorityPlayers holds the XUIDs or display names of players that may use the reserved PrioritySlots,
+	// such as staff.
+	PriorityPlayers []string `yaml:"priority_players"`
+	// KickPolicy is the policy used when a priority player joins while the proxy is full: KickPolicyNewest or
+	// KickPolicyOldest kick the player without priority that joined the latest or earliest to make room, while
+	// KickPolicyNone, the default, rejects the priority player.
+	KickPolicy string `yaml:"kick_policy"`
+	// ServerCapacities holds the maximum amount of players of servers, keyed by the name or address of the
+	// server. Players joining a server at its capacity wait in its queue on the QueueFallback server, or in
+	// limbo if no QueueFallback is set, and are disconnected if neither is available. Transfers of players to
+	// a server at its capacity fail. Servers without an entry have no limit.
+	ServerCapacities map[string]int `yaml:"server_capacities"`
+	// QueueFallback is the name or address of the server players wait on while they are in the queue of the
+	// server they joined. If empty, players wait in limbo if it is enabled.
+	QueueFallback string `yaml:"queue_fallback"`
+	// QueuePriorities holds the priority tiers of players in queues, keyed by their XUID or display name.
+	// Players with a higher tier are placed before players with a lower tier. Players without an entry have
+	// tier zero.
+	QueuePriorities map[string]int `yaml:"queue_priorities"`
+	// HealthCheckInterval is the interval at which servers are checked for reachability in milliseconds.
+	// Players are not transferred to servers that are down. If zero, servers are not checked.
+	HealthCheckInterval int64 `yaml:"health_check_interval"`
+	// HealthCheckThreshold is the amount of consecutive failed checks after which a server is marked down.
+	HealthCheckThreshold int `yaml:"health_check_threshold"`
+	// DialTimeout is the maximum time in milliseconds connecting to a server may take. If zero, there is no
+	// limit.
+	DialTimeout int64 `yaml:"dial_timeout"`
+	// LoginTimeout is the maximum time in milliseconds logging in to a server and receiving its game data may
+	// take. If zero, there is no limit.
+	LoginTimeout int64 `yaml:"login_timeout"`
+	// ReconnectGrace is the time in milliseconds the connection to the server of a player is kept open after
+	// the player lost their connection. If the player reconnects within that time, they continue where they
+	// left off on the same server. If zero, sessions are closed as soon as the player disconnects.
+	ReconnectGrace int64 `yaml:"reconnect_grace"`
+	// ReplayWindow is the time in milliseconds of recent gameplay kept in memory for every player, so that
+	// moderators may review what a player saw and did when an incident is reported, by saving it through
+	// session.Session.SaveReplay and replaying it through session.Session.Replay. If zero, no gameplay is kept.
+	ReplayWindow int64 `yaml:"replay_window"`
+	// CircuitBreakerThreshold is the amount of consecutive failed dials after which a server is no longer
+	// dialed for the cooldown period, so that players fall back to other servers immediately. If zero, the
+	// circuit breaker is disabled.
+	CircuitBreakerThreshold int `yaml:"circuit_breaker_threshold"`
+	// CircuitBreakerCooldown is the time in milliseconds a server is not dialed after its circuit opened.
+	CircuitBreakerCooldown int64 `yaml:"circuit_breaker_cooldown"`
+	// PipelineWorkers enables writing packets on a separate goroutine per session and direction, queueing up to
+	// four times PipelineWorkers packets. Zero writes packets inline, which is sufficient unless writes stall.
+	PipelineWorkers int `yaml:"pipeline_workers"`
+	// Passthrough enables forwarding packets sent by servers to clients without decoding them, which greatly
+	// reduces CPU usage. Only packets needed by the proxy and those in PassthroughDecode are decoded.
+	Passthrough bool `yaml:"passthrough"`
+	// PassthroughDecode holds the IDs of additional packets that are decoded in passthrough mode.
+	PassthroughDecode []uint32 `yaml:"passthrough_decode"`
+	// MultiVersion enables accepting clients on older protocol versions supported by the version package.
+	// Their packets are converted to the latest protocol, so passthrough mode is disabled for them.
+	MultiVersion bool `yaml:"multi_version"`
+	// TranslateEntityIDs enables translating the entity IDs of servers to IDs that are unique per session,
+	// preventing entities from being corrupted on the client when servers use overlapping IDs.
+	TranslateEntityIDs bool `yaml:"translate_entity_ids"`
+	// SeamlessTransfer enables transferring players between servers in the same dimension without the
+	// dimension change animation, only resending the parts of the world state that differ between them.
+	SeamlessTransfer bool `yaml:"seamless_transfer"`
+	// TransferForwarding keeps forwarding packets between players and their current server while the server
+	// they are transferred to is dialed, instead of freezing them until the transfer completes.
+	TransferForwarding bool `yaml:"transfer_forwarding"`
+	// Animation is the name of the animation played when players are transferred, such as "dimension",
+	// "fade", "title" or "credits". If empty, "dimension" is used.
+	Animation string `yaml:"animation"`
+
+	// LoginRate is the amount of connections accepted per second from a single IP address. Connections
+	// exceeding the rate are closed as soon as RakNet accepts them, before the login handshake is performed,
+	// so their clients are not shown a message. If zero, connections are not limited per IP address.
+	LoginRate float64 `yaml:"login_rate"`
+	// LoginBurst is the amount of connections accepted from a single IP address in quick succession before
+	// LoginRate applies.
+	LoginBurst int `yaml:"login_burst"`
+	// GlobalLoginRate is the total amount of connections accepted per second. If zero, the total amount of
+	// connections is not limited.
+	GlobalLoginRate float64 `yaml:"global_login_rate"`
+	// GlobalLoginBurst is the total amount of connections accepted in quick succession before
+	// GlobalLoginRate applies.
+	GlobalLoginBurst int `yaml:"global_login_burst"`
+	// FloodLimits limits the rate at which clients may send packets, keyed by packet ID. Packets without a
+	// limit may be sent at any rate.
+	FloodLimits map[uint32]session.FloodLimit `yaml:"flood_limits"`
+	// Throttle limits the bandwidth used to send packets to every player, preventing servers sending large
+	// amounts of chunks from saturating the connection of players on slow networks. Movement packets are not
+	// limited.
+	Throttle session.Throttle `yaml:"throttle"`
+	// ChunkPacing limits the rate at which chunks are sent to players after joining and transferring, so that
+	// low-end devices do not freeze when servers send all chunks around the player at once.
+	ChunkPacing session.ChunkPacing `yaml:"chunk_pacing"`
+	// BansFile is the path of the JSON file bans are persisted to. If empty, bans are only kept in memory.
+	BansFile string `yaml:"bans_file"`
+	// IgnoresFile is the path of the JSON file the players that players ignore are persisted to. If empty, they
+	// are only kept in memory.
+	IgnoresFile string `yaml:"ignores_file"`
+	// AuditFile is the path of the file joins, quits, transfers and kicks of players are recorded in as JSON
+	// lines. If empty, no audit log is written.
+	AuditFile string `yaml:"audit_file"`
+	// AuditMaxSize is the size in bytes after which the audit log is rotated. If zero, it is never rotated.
+	AuditMaxSize int64 `yaml:"audit_max_size"`
+	// AuditBackups is the amount of rotated audit logs kept.
+	AuditBackups int `yaml:"audit_backups"`
+	// WhitelistFile is the path of a file holding the XUIDs and gamertags of whitelisted players, one per line.
+	// If set, the whitelist is enabled and reloaded whenever the file changes.
+	WhitelistFile string `yaml:"whitelist_file"`
+	// GeoIPRanges maps IP ranges in CIDR notation to the region of players connecting from them. If set, the
+	// region of every player is resolved when they connect and is available through session.Session.Region.
+	GeoIPRanges map[string]string `yaml:"geoip_ranges"`
+	// MOTD holds the lines shown in the server list, rotating every few seconds. It is only used if no
+	// StatusProvider is set in the ListenConfig passed to Listen.
+	MOTD []string `yaml:"motd"`
+	// MOTDFull holds the lines shown in the server list instead of the MOTD while the proxy has reached its
+	// MaxPlayers.
+	MOTDFull []string `yaml:"motd_full"`
+	// ResourcePacksDir is the directory holding the resource packs sent to players when they join. Packs are
+	// only used if no packs are set in the ListenConfig passed to Listen.
+	ResourcePacksDir string `yaml:"resource_packs_dir"`
+	// ResourcePackKeys holds the content keys of encrypted resource packs, keyed by the UUID of the pack.
+	ResourcePackKeys map[string]string `yaml:"resource_pack_keys"`
+	// ForceResourcePacks requires players to accept the resource packs of the proxy in order to join.
+	ForceResourcePacks bool `yaml:"force_resource_packs"`
+	// ServerResourcePacks holds the UUIDs of the resource packs required by servers, keyed by the name or
+	// address of the server. Players that did not receive all packs of a server are not transferred to it.
+	ServerResourcePacks map[string][]string `yaml:"server_resource_packs"`
+	// Maintenance starts the proxy in maintenance mode, rejecting all new connections until it is disabled
+	// through Spectrum.SetMaintenance.
+	Maintenance bool `yaml:"maintenance"`
+	// MaintenanceMessage is the message players are disconnected with during maintenance. If empty, a default
+	// message is used.
+	MaintenanceMessage string `yaml:"maintenance_message"`
+	// TranslationsDir is a directory holding a YAML file per locale, such as en_US.yml, with translations of
+	// the messages sent by the proxy. Messages sent to players are translated to their locale if a
+	// translation exists. If empty, only the built-in English messages are used.
+	TranslationsDir string `yaml:"translations_dir"`
+	// DefaultLocale is the locale used for players whose locale has no translation of a message. If empty,
+	// en_US is used.
+	DefaultLocale string `yaml:"default_locale"`
+
+	// ClusterID is the ID of the proxy in a cluster of proxies sharing the presence of players. If empty, the
+	// proxy does not join a cluster.
+	ClusterID string `yaml:"cluster_id"`
+	// ClusterAddr is the public address players connect to the proxy on, used by other proxies to transfer
+	// players to it.
+	ClusterAddr string `yaml:"cluster_addr"`
+	// ClusterListenAddr is the address presence announcements of other proxies are received on.
+	ClusterListenAddr string `yaml:"cluster_listen_addr"`
+	// ClusterPeers holds the base URLs of the other proxies in the cluster, such as "http://10.0.0.2:19140".
+	ClusterPeers []string `yaml:"cluster_peers"`
+	// ClusterSecret is the secret shared by all proxies in the cluster that announcements are signed with.
+	// Announcements of other proxies are only received if it is set.
+	ClusterSecret string `yaml:"cluster_secret"`
+
+	// LogLevel is the minimum level of messages logged by the logger created by NewLogger, such as "debug"
+	// or "error". If empty, "info" is used.
+	LogLevel string `yaml:"log_level"`
+	// LogFormat is the format of the logger created by NewLogger, either "text" or "json".
+	LogFormat string `yaml:"log_format"`
+	// MetricsAddr is the address the Prometheus metrics of the proxy are served on under /metrics. If empty,
+	// metrics are not served.
+	MetricsAddr string `yaml:"metrics_addr"`
+	// Debug enables the debug HTTP server, which serves pprof profiles, goroutine dumps and a snapshot of all
+	// sessions. It should never be exposed publicly.
+	Debug bool `yaml:"debug"`
+	// DebugAddr is the address the debug HTTP server listens on if Debug is enabled.
+	DebugAddr string `yaml:"debug_addr"`
 }
 
 func DefaultOpts() *Opts {
 	return &Opts{
 		Addr:            ":19132",
 		LatencyInterval: 3000,
+		TransferRetries: 2,
+		TransferBackoff: 250,
+		DialTimeout:     5000,
+		LoginTimeout:    10000,
+
+		CircuitBreakerCooldown: 30000,
 	}
 }
@@ -0,0 +1,222 @@
+package party
+
+import (
+	"fmt"
+	"github.com/spectrum-proxy/spectrum/event"
+	"github.com/spectrum-proxy/spectrum/session"
+	"sync"
+	"time"
+)
+
+// followWorkers is the maximum amount of members of a party transferred concurrently when following their
+// leader.
+const followWorkers = 4
+
+// Manager manages the parties on the proxy. It follows the events of sessions to transfer the members of a
+// party along with its leader and to remove players from their party when they leave the proxy.
+type Manager struct {
+	mu      sync.RWMutex
+	parties map[*session.Session]*Party
+	nextID  uint64
+
+	unsubscribe func()
+}
+
+// NewManager returns a new Manager following the events published on the bus passed.
+func NewManager(events *event.Bus[session.Event]) *Manager {
+	m := &Manager{parties: make(map[*session.Session]*Party)}
+	m.unsubscribe = events.Subscribe(m.handleEvent)
+	return m
+}
+
+// Close stops the Manager from following the events of sessions. Existing parties are kept.
+func (m *Manager) Close() {
+	m.unsubscribe()
+}
+
+// Create creates a new party led by the session passed. An error is returned if the session is already in a
+// party.
+func (m *Manager) Create(leader *session.Session) (*Party, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.parties[leader]; ok {
+		return nil, fmt.Errorf("you are already in a party")
+	}
+	m.nextID++
+	p := &Party{
+		id:      m.nextID,
+		leader:  leader,
+		members: []*session.Session{leader},
+		invites: make(map[string]time.Time),
+	}
+	m.parties[leader] = p
+	return p, nil
+}
+
+// Invite invites the target session passed to the party of the session passed, which must be its leader. The
+// invite expires after a minute.
+func (m *Manager) Invite(s, target *session.Session) error {
+	p := m.Of(s)
+	if p == nil {
+		return fmt.Errorf("you are not in a party")
+	}
+	if p.Leader() != s {
+		return fmt.Errorf("only the leader of the party can invite players")
+	}
+	if m.Of(target) != nil {
+		return fmt.Errorf("%v is already in a party", target.Client().IdentityData().DisplayName)
+	}
+
+	p.mu.Lock()
+	p.invites[target.Client().IdentityData().XUID] = time.Now().Add(inviteDuration)
+	p.mu.Unlock()
+	return nil
+}
+
+// Join makes the session passed join the party led by the leader passed. The session must have been invited
+// to the party.
+func (m *Manager) Join(s, leader *session.Session) (*Party, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.parties[s]; ok {
+		return nil, fmt.Errorf("you are already in a party")
+	}
+	p, ok := m.parties[leader]
+	if !ok || !p.Invited(s.Client().IdentityData().XUID) {
+		return nil, fmt.Errorf("you were not invited to a party by %v", leader.Client().IdentityData().DisplayName)
+	}
+
+	p.mu.Lock()
+	delete(p.invites, s.Client().IdentityData().XUID)
+	p.members = append(p.members, s)
+	p.mu.Unlock()
+	m.parties[s] = p
+	return p, nil
+}
+
+// Leave removes the session passed from its party. If it was the leader, the member that joined the earliest
+// becomes the new leader. The party is disbanded once it has no members left.
+func (m *Manager) Leave(s *session.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.parties[s]
+	if !ok {
+		return fmt.Errorf("you are not in a party")
+	}
+	delete(m.parties, s)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, member := range p.members {
+		if member == s {
+			p.members = append(p.members[:i:i], p.members[i+1:]...)
+			break
+		}
+	}
+	if p.leader == s && len(p.members) > 0 {
+		p.leader = p.members[0]
+	}
+	return nil
+}
+
+// Disband disbands the party of the session passed, which must be its leader.
+func (m *Manager) Disband(s *session.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.parties[s]
+	if !ok {
+		return fmt.Errorf("you are not in a party")
+	}
+	if p.Leader() != s {
+		return fmt.Errorf("only the leader of the party can disband it")
+	}
+	for _, member := range p.Members() {
+		delete(m.parties, member)
+	}
+	p.mu.Lock()
+	p.members = nil
+	p.mu.Unlock()
+	return nil
+}
+
+// Of returns the party of the session passed, or nil if it is not in a party.
+func (m *Manager) Of(s *session.Session) *Party {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.parties[s]
+}
+
+// Parties returns all parties.
+func (m *Manager) Parties() []*Party {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	seen := make(map[*Party]struct{}, len(m.parties))
+	parties := make([]*Party, 0, len(m.parties))
+	for _, p := range m.parties {
+		if _, ok := seen[p]; !ok {
+			seen[p] = struct{}{}
+			parties = append(parties, p)
+		}
+	}
+	return parties
+}
+
+// Chat sends a chat message from the session passed to all members of its party.
+func (m *Manager) Chat(s *session.Session, message string) error {
+	p := m.Of(s)
+	if p == nil {
+		return fmt.Errorf("you are not in a party")
+	}
+	p.Broadcast(fmt.Sprintf("§d[Party] %v:§r %v", s.Client().IdentityData().DisplayName, message))
+	return nil
+}
+
+// handleEvent handles an event of a session, transferring the members of a party after its leader was
+// transferred and removing sessions that were closed from their party.
+func (m *Manager) handleEvent(e session.Event) {
+	switch e := e.(type) {
+	case session.TransferEnd:
+		p := m.Of(e.Session())
+		if e.Err != nil || p == nil || p.Leader() != e.Session() {
+			return
+		}
+		go m.follow(p, e.Session(), e.To)
+	case session.SessionClose:
+		if p := m.Of(e.Session()); p != nil {
+			_ = m.Leave(e.Session())
+			p.Broadcast(fmt.Sprintf("§d[Party]§r %v left the party.", e.Session().Client().IdentityData().DisplayName))
+		}
+	}
+}
+
+// follow transfers the members of the party passed other than its leader to the server at the address
+// passed. Members are transferred using a bounded amount of goroutines.
+func (m *Manager) follow(p *Party, leader *session.Session, addr string) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, followWorkers)
+	for _, member := range p.Members() {
+		if member == leader || member.ServerAddr() == addr {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(member *session.Session) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			member.SendMessage("§d[Party]§r Following your party leader...")
+			if err := member.Transfer(member.Context(), addr); err != nil {
+				member.SendMessage(fmt.Sprintf("§d[Party]§r Failed to follow your party leader: %v", err))
+			}
+		}(member)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,93 @@
+package party
+
+import (
+	"github.com/spectrum-proxy/spectrum/session"
+	"slices"
+	"sync"
+	"time"
+)
+
+// inviteDuration is the time after which an invite to a party expires.
+const inviteDuration = time.Minute
+
+// Party is a group of players led by one of them. When the leader of a party is transferred to another
+// server, the other members follow them to the same server.
+type Party struct {
+	id uint64
+
+	mu      sync.RWMutex
+	leader  *session.Session
+	members []*session.Session
+	// invites maps the XUIDs of players invited to the party to the time their invite expires.
+	invites map[string]time.Time
+}
+
+// ID returns the ID of the party, which is unique for the lifetime of its Manager.
+func (p *Party) ID() uint64 {
+	return p.id
+}
+
+// Leader returns the leader of the party.
+func (p *Party) Leader() *session.Session {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.leader
+}
+
+// Members returns all members of the party, including its leader, in the order they joined.
+func (p *Party) Members() []*session.Session {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Clone(p.members)
+}
+
+// Has checks if the session passed is a member of the party.
+func (p *Party) Has(s *session.Session) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Contains(p.members, s)
+}
+
+// Invited checks if the player with the XUID passed has an invite to the party that has not expired.
+func (p *Party) Invited(xuid string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	expiry, ok := p.invites[xuid]
+	return ok && time.Now().Before(expiry)
+}
+
+// Broadcast sends a message to all members of the party.
+func (p *Party) Broadcast(message string) {
+	for _, member := range p.Members() {
+		member.SendMessage(message)
+	}
+}
+
+// Info holds information about a party, as exposed by the Admin API.
+type Info struct {
+	// ID is the ID of the party.
+	ID uint64 `json:"id"`
+	// Leader is the display name of the leader of the party.
+	Leader string `json:"leader"`
+	// Members holds the display names of all members of the party, including its leader.
+	Members []string `json:"members"`
+	// Server is the address of the server the leader of the party is connected to.
+	Server string `json:"server"`
+}
+
+// Info returns information about the party.
+func (p *Party) Info() Info {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	info := Info{
+		ID:      p.id,
+		Leader:  p.leader.Client().IdentityData().DisplayName,
+		Members: make([]string, 0, len(p.members)),
+		Server:  p.leader.ServerAddr(),
+	}
+	for _, member := range p.members {
+		info.Members = append(info.Members, member.Client().IdentityData().DisplayName)
+	}
+	return info
+}
@@ -0,0 +1,41 @@
+package spectrum
+
+import "github.com/spectrum-proxy/spectrum/cluster"
+
+// Presence looks up whether the player with the XUID or display name passed is online, and if so, which server
+// they are connected to and their latency. If the proxy is part of a cluster, players connected to other
+// proxies of the cluster are found as well, in which case the Proxy of the Presence holds the ID of their
+// proxy.
+func (s *Spectrum) Presence(player string) (cluster.Presence, bool, error) {
+	if s.cluster != nil {
+		return s.cluster.Find(player)
+	}
+
+	sess := s.registry.Lookup(player)
+	if sess == nil {
+		return cluster.Presence{}, false, nil
+	}
+	identity := sess.Client().IdentityData()
+	return cluster.Presence{
+		XUID:    identity.XUID,
+		Name:    identity.DisplayName,
+		Server:  sess.ServerAddr(),
+		Latency: sess.Latency(),
+	}, true, nil
+}
+
+// Presences looks up the presence of each of the players passed like Presence. The presences of the players
+// that are online are returned, keyed by the XUID or display name they were looked up by.
+func (s *Spectrum) Presences(players ...string) (map[string]cluster.Presence, error) {
+	presences := make(map[string]cluster.Presence, len(players))
+	for _, player := range players {
+		presence, ok, err := s.Presence(player)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			presences[player] = presence
+		}
+	}
+	return presences, nil
+}
@@ -2,6 +2,8 @@ package protocol
 
 import (
 	"encoding/binary"
+	"net"
+	"sync"
 )
 
 const (
@@ -18,6 +20,7 @@ type Reader struct {
 	buf       []byte
 	remaining uint32
 	packets   chan []byte
+	once      sync.Once
 }
 
 func NewReader(r readable) *Reader {
@@ -27,7 +30,18 @@ func NewReader(r readable) *Reader {
 	}
 }
 
+// Read reads the next frame from the underlying reader. Once a full packet has been read, it is made
+// available through ReadPacket. If an error is returned, the Reader is closed and ReadPacket returns an error
+// once all buffered packets have been consumed.
 func (r *Reader) Read() (err error) {
+	defer func() {
+		if err != nil {
+			r.once.Do(func() {
+				close(r.packets)
+			})
+		}
+	}()
+
 	if r.remaining <= 0 {
 		length, err := r.internalRead(packetLengthSize)
 		if err != nil {
@@ -53,11 +67,14 @@ func (r *Reader) Read() (err error) {
 	return
 }
 
-func (r *Reader) ReadPacket() []byte {
-	select {
-	case packet := <-r.packets:
-		return packet
+// ReadPacket returns the next packet read by the Reader. It blocks until a packet is available, or returns
+// net.ErrClosed if the Reader was closed.
+func (r *Reader) ReadPacket() ([]byte, error) {
+	packet, ok := <-r.packets
+	if !ok {
+		return nil, net.ErrClosed
 	}
+	return packet, nil
 }
 
 func (r *Reader) internalRead(n uint32) ([]byte, error) {
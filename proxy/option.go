@@ -0,0 +1,62 @@
+package proxy
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/spectrum-proxy/spectrum"
+	"github.com/spectrum-proxy/spectrum/server"
+	"github.com/spectrum-proxy/spectrum/session"
+	"log/slog"
+	"time"
+)
+
+// Option configures a Proxy when it is created using New.
+type Option func(p *Proxy)
+
+// WithLogger sets the logger of the Proxy. If not set, slog.Default is used.
+func WithLogger(logger *slog.Logger) Option {
+	return func(p *Proxy) {
+		p.logger = logger
+	}
+}
+
+// WithDiscovery sets the server.Discovery choosing the server players join. If not set, players join the
+// default server of the config.Config of the Proxy.
+func WithDiscovery(discovery server.Discovery) Option {
+	return func(p *Proxy) {
+		p.discovery = discovery
+	}
+}
+
+// WithListenConfig sets the minecraft.ListenConfig used to listen for players.
+func WithListenConfig(config minecraft.ListenConfig) Option {
+	return func(p *Proxy) {
+		p.listenConfig = config
+	}
+}
+
+// WithOpts sets a function called to adjust the spectrum.Opts built from the config.Config of the Proxy, for
+// options not covered by the config.Config. It is called again whenever the config is reloaded.
+func WithOpts(f func(opts *spectrum.Opts)) Option {
+	return func(p *Proxy) {
+		p.configure = append(p.configure, f)
+	}
+}
+
+// WithSessionHandler sets a function called with every session accepted, for example to attach a
+// session.Handler. It is called on the goroutine accepting players and should not block.
+func WithSessionHandler(f func(s *session.Session)) Option {
+	return func(p *Proxy) {
+		p.handler = f
+	}
+}
+
+// WithConfigFile enables reloading the config of the Proxy from the YAML file at the path passed, checking
+// for changes at the interval passed. If the interval is not positive, the file is checked every 5 seconds.
+func WithConfigFile(path string, interval time.Duration) Option {
+	return func(p *Proxy) {
+		if interval <= 0 {
+			interval = time.Second * 5
+		}
+		p.configPath, p.watchEvery = path, interval
+	}
+}
@@ -0,0 +1,201 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/spectrum-proxy/spectrum"
+	"github.com/spectrum-proxy/spectrum/api"
+	"github.com/spectrum-proxy/spectrum/config"
+	"github.com/spectrum-proxy/spectrum/server"
+	"github.com/spectrum-proxy/spectrum/session"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// shutdownMessage is the message players are disconnected with when the Proxy is shut down.
+const shutdownMessage = "The proxy is shutting down."
+
+// Proxy ties together a spectrum.Spectrum, the loop accepting its sessions and the APIs enabled in its
+// config.Config, so that a proxy may be embedded in a larger program by calling New, Start and Shutdown.
+type Proxy struct {
+	conf         config.Config
+	logger       *slog.Logger
+	discovery    server.Discovery
+	listenConfig minecraft.ListenConfig
+	configure    []func(opts *spectrum.Opts)
+	handler      func(s *session.Session)
+	configPath   string
+	watchEvery   time.Duration
+
+	spectrum *spectrum.Spectrum
+	api      *api.API
+	admin    *api.Admin
+
+	wg     sync.WaitGroup
+	done   chan struct{}
+	closed sync.Once
+}
+
+// New returns a new Proxy configured by the config.Config and the options passed. The Proxy does not listen
+// for players until Start is called.
+func New(conf config.Config, opts ...Option) *Proxy {
+	p := &Proxy{conf: conf, done: make(chan struct{})}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.logger == nil {
+		p.logger = slog.Default()
+	}
+	if p.discovery == nil && conf.Servers.Default != "" {
+		p.discovery = server.NewStaticDiscovery(conf.Servers.Default)
+	}
+	return p
+}
+
+// Start starts listening for players and serving the APIs enabled in the config.Config of the Proxy. Players
+// are accepted in the background until Shutdown is called.
+func (p *Proxy) Start() error {
+	if p.spectrum != nil {
+		return errors.New("proxy already started")
+	}
+	if p.discovery == nil {
+		return errors.New("no default server configured and no discovery set")
+	}
+
+	p.spectrum = spectrum.NewSpectrum(p.discovery, p.logger, p.opts())
+	if p.configPath != "" {
+		p.spectrum.SetConfigLoader(config.Loader(p.configPath, p.opts))
+	}
+	if err := p.spectrum.Listen(p.listenConfig); err != nil {
+		// The proxy is reset so that Start may be called again.
+		_ = p.spectrum.Close()
+		p.spectrum = nil
+		return err
+	}
+
+	if addr := p.conf.API.Addr; addr != "" {
+		p.api = api.NewAPI(p.logger, p.spectrum.Registry())
+		p.api.SetBanStore(p.spectrum.Bans())
+		if err := p.api.Listen(addr); err != nil {
+			_ = p.spectrum.Close()
+			p.spectrum, p.api = nil, nil
+			return fmt.Errorf("failed to listen on API address: %v", err)
+		}
+		p.wg.Add(1)
+		go p.acceptAPI()
+	}
+	if p.configPath != "" {
+		go config.Watch(p.configPath, p.watchEvery, p.done, p.spectrum.Reload, func(err error) {
+			p.logger.Error("Failed to reload config", "err", err)
+		})
+	}
+	if addr := p.conf.API.AdminAddr; addr != "" {
+		p.admin = api.NewAdmin(p.logger, p.spectrum.Registry(), p.spectrum, p.conf.API.AdminToken)
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			if err := p.admin.ListenAndServe(addr); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				p.logger.Error("Failed to serve admin API", "err", err)
+			}
+		}()
+	}
+
+	p.wg.Add(1)
+	go p.accept()
+	return nil
+}
+
+// Shutdown stops accepting players, stops the APIs and disconnects all players. It waits for the Proxy to
+// stop until the context passed is done, in which case the error of the context is returned.
+func (p *Proxy) Shutdown(ctx context.Context) error {
+	if p.spectrum == nil {
+		return errors.New("proxy not started")
+	}
+	p.closed.Do(func() {
+		close(p.done)
+		_ = p.spectrum.Close()
+		if p.api != nil {
+			_ = p.api.Close()
+		}
+		if p.admin != nil {
+			_ = p.admin.Close()
+		}
+		for _, s := range p.spectrum.Registry().Sessions() {
+			s.Disconnect(shutdownMessage)
+		}
+	})
+
+	stopped := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Spectrum returns the spectrum.Spectrum of the Proxy, or nil if the Proxy was not started.
+func (p *Proxy) Spectrum() *spectrum.Spectrum {
+	return p.spectrum
+}
+
+// Registry returns the session.Registry holding the sessions of the Proxy, or nil if the Proxy was not
+// started.
+func (p *Proxy) Registry() *session.Registry {
+	if p.spectrum == nil {
+		return nil
+	}
+	return p.spectrum.Registry()
+}
+
+// opts returns the spectrum.Opts of the Proxy, built from its config.Config and options.
+func (p *Proxy) opts() *spectrum.Opts {
+	opts := p.conf.Opts()
+	for _, f := range p.configure {
+		f(opts)
+	}
+	return opts
+}
+
+// accept accepts players until the Proxy is shut down, passing their sessions to the handler of the Proxy.
+func (p *Proxy) accept() {
+	defer p.wg.Done()
+	for {
+		s, err := p.spectrum.Accept()
+		select {
+		case <-p.done:
+			return
+		default:
+		}
+		if err != nil {
+			p.logger.Debug("Failed to accept session", "err", err)
+			continue
+		}
+		if p.handler != nil {
+			p.handler(s)
+		}
+	}
+}
+
+// acceptAPI accepts connections to the API until the Proxy is shut down.
+func (p *Proxy) acceptAPI() {
+	defer p.wg.Done()
+	for {
+		if err := p.api.Accept(); err != nil {
+			select {
+			case <-p.done:
+				return
+			default:
+				p.logger.Error("Failed to accept API connection", "err", err)
+			}
+		}
+	}
+}
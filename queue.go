@@ -0,0 +1,69 @@
+package spectrum
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/spectrum-proxy/spectrum/metrics"
+	"github.com/spectrum-proxy/spectrum/queue"
+	"time"
+)
+
+// queueInterval is the interval at which players waiting in queues are transferred and shown their position.
+const queueInterval = time.Second
+
+// newQueue returns the queue players wait in for servers at their capacity and registers its metrics.
+func (s *Spectrum) newQueue() *queue.Queue {
+	q := queue.New(s.registry, s.servers, s.events, s.serverCapacity)
+	metrics.NewGaugeVecFunc("spectrum_queue_length", "Number of players waiting in the queue of a server.", "server", func() map[string]float64 {
+		values := make(map[string]float64)
+		for addr, n := range q.Lengths() {
+			values[addr] = float64(n)
+		}
+		return values
+	})
+	return q
+}
+
+// Queue returns the queue players wait in for servers that are at their capacity.
+func (s *Spectrum) Queue() *queue.Queue {
+	return s.queue
+}
+
+// serverCapacity returns the capacity configured for the server at the address passed, or zero if it has no
+// capacity.
+func (s *Spectrum) serverCapacity(addr string) int {
+	s.optsMu.RLock()
+	defer s.optsMu.RUnlock()
+
+	if capacity, ok := s.opts.ServerCapacities[addr]; ok {
+		return capacity
+	}
+	for name, capacity := range s.opts.ServerCapacities {
+		if s.servers.Resolve(name) == addr {
+			return capacity
+		}
+	}
+	return 0
+}
+
+// queueTier returns the priority tier of the player with the identity passed in queues.
+func (s *Spectrum) queueTier(identity login.IdentityData) int {
+	s.optsMu.RLock()
+	defer s.optsMu.RUnlock()
+
+	if tier, ok := s.opts.QueuePriorities[identity.XUID]; ok {
+		return tier
+	}
+	return s.opts.QueuePriorities[identity.DisplayName]
+}
+
+// queueFallback returns the address of the server players wait on while queued when joining, or an empty
+// string if no such server is configured.
+func (s *Spectrum) queueFallback() string {
+	s.optsMu.RLock()
+	defer s.optsMu.RUnlock()
+
+	if s.opts.QueueFallback == "" {
+		return ""
+	}
+	return s.servers.Resolve(s.opts.QueueFallback)
+}
@@ -0,0 +1,234 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"github.com/spectrum-proxy/spectrum/event"
+	"github.com/spectrum-proxy/spectrum/server"
+	"github.com/spectrum-proxy/spectrum/session"
+	"math"
+	"sync"
+	"time"
+)
+
+// entry is a player waiting in the queue of a server.
+type entry struct {
+	s    *session.Session
+	tier int
+}
+
+// Queue holds players waiting for a slot on servers that are at their capacity. Each server has its own queue,
+// in which players with a higher priority tier are placed before players with a lower tier, and players of
+// the same tier are served first come, first served. Players wait on whatever server they are connected to,
+// typically a fallback or limbo server, and are transferred once a slot frees up.
+type Queue struct {
+	registry *session.Registry
+	servers  *server.Registry
+	capacity func(addr string) int
+
+	mu      sync.Mutex
+	queues  map[string][]entry
+	pending map[string]int
+
+	unsubscribe func()
+}
+
+// New returns a new Queue for the sessions in the registry passed. capacity returns the maximum amount of
+// players of the server at the address passed, or zero if the server has no capacity. Servers are shown to
+// players by their name in the server registry passed. Players are removed from the queue when their session
+// is closed, as published on the bus passed.
+func New(registry *session.Registry, servers *server.Registry, events *event.Bus[session.Event], capacity func(addr string) int) *Queue {
+	q := &Queue{
+		registry: registry,
+		servers:  servers,
+		capacity: capacity,
+		queues:   make(map[string][]entry),
+		pending:  make(map[string]int),
+	}
+	q.unsubscribe = events.Subscribe(func(e session.Event) {
+		if _, ok := e.(session.SessionClose); ok {
+			q.Remove(e.Session())
+		}
+	})
+	return q
+}
+
+// Close stops the Queue from following the events of sessions.
+func (q *Queue) Close() {
+	q.unsubscribe()
+}
+
+// Available checks if a player may connect to the server at the address passed without waiting in its queue.
+// This is the case if the server has a free slot and no other players are waiting for it.
+func (q *Queue) Available(addr string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.queues[addr]) == 0 && q.free(addr) > 0
+}
+
+// Enqueue places the session passed in the queue of the server at the address passed with the priority tier
+// passed, removing it from any queue it was in before. It returns the position of the session in the queue,
+// starting at 1.
+func (q *Queue) Enqueue(s *session.Session, addr string, tier int) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.remove(s)
+	queue := q.queues[addr]
+	i := len(queue)
+	for i > 0 && queue[i-1].tier < tier {
+		i--
+	}
+	q.queues[addr] = append(queue[:i:i], append([]entry{{s: s, tier: tier}}, queue[i:]...)...)
+	return i + 1
+}
+
+// Transfer transfers the session passed to the server at the address passed if it has a free slot, or
+// otherwise places it in the queue of the server with the tier passed. It returns the position in the queue,
+// or zero if the session was transferred.
+func (q *Queue) Transfer(ctx context.Context, s *session.Session, addr string, tier int) (int, error) {
+	if q.Available(addr) {
+		return 0, s.Transfer(ctx, addr)
+	}
+	return q.Enqueue(s, addr, tier), nil
+}
+
+// Remove removes the session passed from the queue it is in, if any.
+func (q *Queue) Remove(s *session.Session) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.remove(s)
+}
+
+// Position returns the address of the server the session passed is waiting for and its position in the queue
+// of that server, starting at 1. False is returned if the session is not in a queue.
+func (q *Queue) Position(s *session.Session) (string, int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for addr, queue := range q.queues {
+		for i, e := range queue {
+			if e.s == s {
+				return addr, i + 1, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// Len returns the amount of players waiting in the queue of the server at the address passed.
+func (q *Queue) Len(addr string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.queues[addr])
+}
+
+// Lengths returns the amount of players waiting in the queue of every server with players waiting, keyed by
+// the address of the server.
+func (q *Queue) Lengths() map[string]int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	lengths := make(map[string]int, len(q.queues))
+	for addr, queue := range q.queues {
+		lengths[addr] = len(queue)
+	}
+	return lengths
+}
+
+// Run transfers players waiting in queues to their server as slots free up and shows the players still
+// waiting their position, at the interval passed until the channel passed is closed.
+func (q *Queue) Run(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			q.process()
+		}
+	}
+}
+
+// process transfers as many players waiting in queues as their servers have free slots and shows the players
+// still waiting their position.
+func (q *Queue) process() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for addr, queue := range q.queues {
+		for free := q.free(addr); free > 0 && len(queue) > 0; free-- {
+			e := queue[0]
+			queue = queue[1:]
+			if q.registry.GetSession(e.s.Client().IdentityData().XUID) != e.s {
+				// The session has not started yet, so it cannot be transferred. It keeps its position.
+				queue = append([]entry{e}, queue...)
+				break
+			}
+			q.pending[addr]++
+			go q.transfer(e, addr)
+		}
+		if len(queue) == 0 {
+			delete(q.queues, addr)
+			continue
+		}
+		q.queues[addr] = queue
+		name := q.servers.Name(addr)
+		for i, e := range queue {
+			e.s.SendActionBar(fmt.Sprintf("Position %v of %v in the queue for %v", i+1, len(queue), name))
+		}
+	}
+}
+
+// transfer transfers the session of the entry passed to the server at the address passed. If the transfer
+// fails, the session is placed back at the front of its tier in the queue.
+func (q *Queue) transfer(e entry, addr string) {
+	e.s.SendActionBar("Transferring...")
+	err := e.s.Transfer(e.s.Context(), addr)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending[addr]--
+	if q.pending[addr] == 0 {
+		delete(q.pending, addr)
+	}
+	if err == nil || e.s.Context().Err() != nil {
+		return
+	}
+
+	queue := q.queues[addr]
+	i := 0
+	for i < len(queue) && queue[i].tier > e.tier {
+		i++
+	}
+	q.queues[addr] = append(queue[:i:i], append([]entry{e}, queue[i:]...)...)
+}
+
+// free returns the amount of free slots of the server at the address passed, excluding slots of players being
+// transferred to it. free must be called with mu held.
+func (q *Queue) free(addr string) int {
+	capacity := q.capacity(addr)
+	if capacity <= 0 {
+		return math.MaxInt
+	}
+	return capacity - q.registry.CountOn(addr) - q.pending[addr]
+}
+
+// remove removes the session passed from the queue it is in. remove must be called with mu held.
+func (q *Queue) remove(s *session.Session) {
+	for addr, queue := range q.queues {
+		for i, e := range queue {
+			if e.s != s {
+				continue
+			}
+			if queue = append(queue[:i:i], queue[i+1:]...); len(queue) == 0 {
+				delete(q.queues, addr)
+			} else {
+				q.queues[addr] = queue
+			}
+			return
+		}
+	}
+}
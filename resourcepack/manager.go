@@ -0,0 +1,178 @@
+// Package resourcepack manages the resource packs the proxy sends to clients when they join. As clients can
+// only download resource packs while logging in, the proxy sends the packs of all servers at once, and checks
+// that a client received the packs a server requires before transferring it there.
+package resourcepack
+
+import (
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft/resource"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cachedPack is a pack read from a file, kept so that unchanged files need not be read again.
+type cachedPack struct {
+	pack    *resource.Pack
+	modTime time.Time
+}
+
+// Manager holds the resource packs sent to clients and the packs required by every server. Packs may be
+// added directly or loaded from a directory. A Manager is safe for concurrent use.
+type Manager struct {
+	mu    sync.RWMutex
+	packs map[string]*resource.Pack
+	// files caches the packs read from files, keyed by their path.
+	files map[string]cachedPack
+	// servers holds the UUIDs of the packs required by a server, keyed by the name or address of the server.
+	servers map[string][]string
+}
+
+// NewManager returns a new Manager without any packs.
+func NewManager() *Manager {
+	return &Manager{
+		packs:   make(map[string]*resource.Pack),
+		files:   make(map[string]cachedPack),
+		servers: make(map[string][]string),
+	}
+}
+
+// Add adds a pack to the Manager. If a pack with the same UUID was already added, the pack with the highest
+// version is kept, allowing packs to be overridden by newer versions.
+func (m *Manager) Add(pack *resource.Pack) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.add(pack)
+}
+
+// Remove removes the pack with the UUID passed.
+func (m *Manager) Remove(uuid string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.packs, strings.ToLower(uuid))
+}
+
+// LoadDirectory adds all packs in the directory passed, which may be .mcpack or .zip archives or unpacked
+// directories. The content keys passed are applied to encrypted packs, keyed by the UUID of the pack. Files
+// that were loaded before and did not change since are not read again.
+func (m *Manager) LoadDirectory(dir string, keys map[string]string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read resource pack directory: %v", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat resource pack %v: %v", path, err)
+		}
+
+		cached, ok := m.files[path]
+		if !ok || !cached.modTime.Equal(info.ModTime()) {
+			pack, err := resource.ReadPath(path)
+			if err != nil {
+				return fmt.Errorf("failed to read resource pack %v: %v", path, err)
+			}
+			cached = cachedPack{pack: pack, modTime: info.ModTime()}
+			m.files[path] = cached
+		}
+
+		pack := cached.pack
+		if key, ok := keys[pack.UUID()]; ok {
+			pack = pack.WithContentKey(key)
+		}
+		m.add(pack)
+	}
+	return nil
+}
+
+// Packs returns all packs of the Manager, sorted by their name.
+func (m *Manager) Packs() []*resource.Pack {
+	m.mu.RLock()
+	packs := make([]*resource.Pack, 0, len(m.packs))
+	for _, pack := range m.packs {
+		packs = append(packs, pack)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(packs, func(i, j int) bool {
+		return packs[i].Name() < packs[j].Name()
+	})
+	return packs
+}
+
+// SetServerPacks sets the UUIDs of the packs required by the server with the name or address passed. Clients
+// that did not receive all of them are not transferred to the server. Passing no UUIDs removes the
+// requirement.
+func (m *Manager) SetServerPacks(server string, uuids ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(uuids) == 0 {
+		delete(m.servers, strings.ToLower(server))
+		return
+	}
+	normalised := make([]string, 0, len(uuids))
+	for _, uuid := range uuids {
+		normalised = append(normalised, strings.ToLower(uuid))
+	}
+	m.servers[strings.ToLower(server)] = normalised
+}
+
+// Missing returns the UUIDs of the packs required by the server passed that are not among the packs passed,
+// which are the packs a client received.
+func (m *Manager) Missing(server string, received []*resource.Pack) []string {
+	m.mu.RLock()
+	required := m.servers[strings.ToLower(server)]
+	m.mu.RUnlock()
+
+	var missing []string
+	for _, uuid := range required {
+		found := false
+		for _, pack := range received {
+			if strings.EqualFold(pack.UUID(), uuid) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, uuid)
+		}
+	}
+	return missing
+}
+
+// add adds a pack, keeping the pack with the highest version if one with the same UUID exists. add must be
+// called with mu held.
+func (m *Manager) add(pack *resource.Pack) {
+	uuid := strings.ToLower(pack.UUID())
+	if existing, ok := m.packs[uuid]; ok && compareVersions(existing.Version(), pack.Version()) > 0 {
+		return
+	}
+	m.packs[uuid] = pack
+}
+
+// compareVersions compares two pack versions of the form "1.2.3", returning a positive number if a is newer
+// than b, a negative number if b is newer than a, or zero if both are equal.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			_, _ = fmt.Sscan(as[i], &x)
+		}
+		if i < len(bs) {
+			_, _ = fmt.Sscan(bs[i], &y)
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
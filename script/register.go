@@ -0,0 +1,51 @@
+package script
+
+import (
+	"sync"
+)
+
+// Handler handles a message received over a channel. If it returns true, the message is consumed by the proxy
+// and is not forwarded.
+type Handler func(src Source, msg Message) bool
+
+var (
+	handlersMu sync.RWMutex
+	// handlers holds the registered handlers, keyed by the channel they handle.
+	handlers = map[string]Handler{}
+)
+
+// Handle registers a handler for messages over the channel passed, in both directions. Registering a handler
+// for a channel that already has one replaces the existing handler.
+func Handle(channel string, h Handler) {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+	handlers[channel] = h
+}
+
+// Unhandle removes the handler of the channel passed.
+func Unhandle(channel string) {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+	delete(handlers, channel)
+}
+
+// Dispatch passes the data of a ScriptMessage packet travelling in the direction passed to the handler of its
+// channel, or to the Request waiting for it if it is a response. It returns true if the message was consumed
+// and must not be forwarded. Messages of which the data is not JSON are never consumed.
+func Dispatch(src Source, dir Direction, channel string, data []byte) bool {
+	handlersMu.RLock()
+	h, ok := handlers[channel]
+	handlersMu.RUnlock()
+	if !ok && !awaiting() {
+		return false
+	}
+
+	msg, valid := decode(dir, channel, data)
+	if !valid {
+		return false
+	}
+	if msg.ReplyTo != "" && respond(msg) {
+		return true
+	}
+	return ok && h(src, msg)
+}
@@ -0,0 +1,67 @@
+package script
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	pendingMu sync.Mutex
+	// pending holds the channels of requests waiting for a response, keyed by the ID of the request.
+	pending = map[string]chan Message{}
+	// pendingCount is the amount of requests waiting for a response.
+	pendingCount atomic.Int64
+)
+
+// Request sends the value passed, encoded as JSON, over the channel passed in the direction passed and waits
+// for the response, which is a message with its reply_to set to the ID of the request. The response is
+// consumed and not forwarded. Request must not be called from a Handler, as responses are dispatched by the
+// same goroutine that calls handlers.
+func Request(ctx context.Context, src Source, dir Direction, channel string, v any) (Message, error) {
+	id := uuid.NewString()
+	c := make(chan Message, 1)
+
+	pendingMu.Lock()
+	pending[id] = c
+	pendingMu.Unlock()
+	pendingCount.Add(1)
+	defer func() {
+		pendingMu.Lock()
+		delete(pending, id)
+		pendingMu.Unlock()
+		pendingCount.Add(-1)
+	}()
+
+	if err := write(src, dir, channel, envelope{ID: id}, v); err != nil {
+		return Message{}, err
+	}
+	select {
+	case msg := <-c:
+		return msg, nil
+	case <-ctx.Done():
+		return Message{}, ctx.Err()
+	}
+}
+
+// awaiting checks if any request is waiting for a response.
+func awaiting() bool {
+	return pendingCount.Load() > 0
+}
+
+// respond delivers the response passed to the request waiting for it. It returns false if no request with the
+// ID the message replies to is waiting.
+func respond(msg Message) bool {
+	pendingMu.Lock()
+	c, ok := pending[msg.ReplyTo]
+	pendingMu.Unlock()
+	if ok {
+		select {
+		case c <- msg:
+		default:
+			// The request already received a response.
+		}
+	}
+	return ok
+}
@@ -0,0 +1,107 @@
+package script
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Direction is the direction a ScriptMessage packet travels in.
+type Direction uint8
+
+const (
+	// Serverbound is the direction of messages sent by the client to the server.
+	Serverbound Direction = iota
+	// Clientbound is the direction of messages sent by the server to the client.
+	Clientbound
+)
+
+// Opposite returns the opposite of the direction, which is the direction replies to a message travel in.
+func (d Direction) Opposite() Direction {
+	if d == Serverbound {
+		return Clientbound
+	}
+	return Serverbound
+}
+
+// String ...
+func (d Direction) String() string {
+	if d == Serverbound {
+		return "serverbound"
+	}
+	return "clientbound"
+}
+
+// Source is the source of a message, through which messages may be sent back. It is typically a
+// session.Session.
+type Source interface {
+	// WriteScriptMessage writes a ScriptMessage packet with the channel and data passed in the direction
+	// passed: to the server if it is Serverbound and to the client if it is Clientbound.
+	WriteScriptMessage(dir Direction, channel string, data []byte) error
+}
+
+// envelope is the JSON object messages are wrapped in to correlate requests with their responses.
+type envelope struct {
+	ID      string          `json:"id,omitempty"`
+	ReplyTo string          `json:"reply_to,omitempty"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Message is a message exchanged with a behaviour pack over a ScriptMessage packet. The data of messages is
+// JSON, optionally wrapped in an object of the form {"id": "...", "reply_to": "...", "data": ...} to correlate
+// requests with their responses.
+type Message struct {
+	// Channel is the identifier of the ScriptMessage packet, such as "spectrum:party".
+	Channel string
+	// Direction is the direction the message was travelling in.
+	Direction Direction
+	// ID is the ID of the message if it is a request expecting a response, or an empty string otherwise.
+	ID string
+	// ReplyTo is the ID of the request the message is a response to, or an empty string if it is none.
+	ReplyTo string
+	// Data is the JSON data of the message.
+	Data json.RawMessage
+}
+
+// Decode decodes the data of the message into the value passed.
+func (m Message) Decode(v any) error {
+	return json.Unmarshal(m.Data, v)
+}
+
+// Reply sends the value passed, encoded as JSON, back to where the message came from over the same channel,
+// as response to the message.
+func (m Message) Reply(src Source, v any) error {
+	return write(src, m.Direction.Opposite(), m.Channel, envelope{ReplyTo: m.ID}, v)
+}
+
+// Send sends the value passed, encoded as JSON, over the channel passed in the direction passed.
+func Send(src Source, dir Direction, channel string, v any) error {
+	return write(src, dir, channel, envelope{}, v)
+}
+
+// write encodes the value passed into the envelope passed and writes it to the source.
+func write(src Source, dir Direction, channel string, e envelope, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %v", err)
+	}
+	if e.ID != "" || e.ReplyTo != "" {
+		e.Data = data
+		if data, err = json.Marshal(e); err != nil {
+			return fmt.Errorf("failed to encode message: %v", err)
+		}
+	}
+	return src.WriteScriptMessage(dir, channel, data)
+}
+
+// decode decodes the data of a ScriptMessage packet into a Message. False is returned if the data is not JSON.
+func decode(dir Direction, channel string, data []byte) (Message, bool) {
+	if !json.Valid(data) {
+		return Message{}, false
+	}
+	msg := Message{Channel: channel, Direction: dir, Data: data}
+	var e envelope
+	if err := json.Unmarshal(data, &e); err == nil && e.Data != nil && (e.ID != "" || e.ReplyTo != "") {
+		msg.ID, msg.ReplyTo, msg.Data = e.ID, e.ReplyTo, e.Data
+	}
+	return msg, true
+}
@@ -0,0 +1,15 @@
+package spectrum
+
+// updateSecrets replaces the secrets shared with servers with those of the Opts passed, resolving the names
+// of servers to their addresses.
+func (s *Spectrum) updateSecrets(opts *Opts) {
+	servers := make(map[string][]byte, len(opts.ServerSecrets))
+	for name, secret := range opts.ServerSecrets {
+		servers[s.servers.Resolve(name)] = []byte(secret)
+	}
+	var global []byte
+	if opts.ConnectSecret != "" {
+		global = []byte(opts.ConnectSecret)
+	}
+	s.secrets.Set(global, servers)
+}
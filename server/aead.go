@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// aeadSaltSize is the size of the random salt each side of an AEAD connection sends when it is opened.
+	aeadSaltSize = 32
+	// aeadMaxFrame is the maximum size of the plaintext of a single frame of an AEAD connection.
+	aeadMaxFrame = 1 << 16
+)
+
+// AEAD is a Transport connecting to servers over TCP, encrypting all traffic with AES-256-GCM using keys
+// derived from a key shared with the server. It is a lightweight alternative to TLS that does not require
+// certificates, for use when servers are reached over untrusted networks. Servers accept such connections
+// using AEADServer.
+type AEAD struct {
+	// Key is the key shared with the server. It may be of any length, but should hold at least 32 random
+	// bytes.
+	Key []byte
+}
+
+// Dial ...
+func (a AEAD) Dial(ctx context.Context, addr string) (net.Conn, error) {
+	if len(a.Key) == 0 {
+		return nil, errors.New("aead transport has no key")
+	}
+	conn, err := TCP{}.Dial(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+	c, err := newAEADConn(conn, a.Key, true)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to perform AEAD handshake: %v", err)
+	}
+	return c, nil
+}
+
+// AEADServer performs the server side of the handshake of a connection made by the AEAD transport with the
+// key passed and returns the connection, which decrypts everything read from it and encrypts everything
+// written to it. Servers behind the proxy call it on every connection accepted.
+func AEADServer(conn net.Conn, key []byte) (net.Conn, error) {
+	if len(key) == 0 {
+		return nil, errors.New("aead transport has no key")
+	}
+	c, err := newAEADConn(conn, key, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform AEAD handshake: %v", err)
+	}
+	return c, nil
+}
+
+// aeadConn is a net.Conn encrypting the data written to it and decrypting the data read from it. Data is sent
+// in frames holding the size of the sealed data followed by the data itself. Every direction uses its own key
+// and a counter as nonce, so that frames cannot be replayed or reordered.
+type aeadConn struct {
+	net.Conn
+
+	readMu    sync.Mutex
+	open      cipher.AEAD
+	readNonce uint64
+	pending   []byte
+
+	writeMu    sync.Mutex
+	seal       cipher.AEAD
+	writeNonce uint64
+}
+
+// newAEADConn exchanges salts over the connection passed and derives the keys of both directions from them
+// and the key passed. The side dialing the connection is the client.
+func newAEADConn(conn net.Conn, key []byte, client bool) (*aeadConn, error) {
+	local := make([]byte, aeadSaltSize)
+	if _, err := rand.Read(local); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(local); err != nil {
+		return nil, err
+	}
+	remote := make([]byte, aeadSaltSize)
+	if _, err := io.ReadFull(conn, remote); err != nil {
+		return nil, err
+	}
+
+	clientSalt, serverSalt := local, remote
+	if !client {
+		clientSalt, serverSalt = remote, local
+	}
+	toServer, err := deriveAEAD(key, "client", clientSalt, serverSalt)
+	if err != nil {
+		return nil, err
+	}
+	toClient, err := deriveAEAD(key, "server", clientSalt, serverSalt)
+	if err != nil {
+		return nil, err
+	}
+	if client {
+		return &aeadConn{Conn: conn, seal: toServer, open: toClient}, nil
+	}
+	return &aeadConn{Conn: conn, seal: toClient, open: toServer}, nil
+}
+
+// deriveAEAD derives the AES-256-GCM cipher of one direction of a connection from the shared key, the label of
+// the direction and the salts of both sides.
+func deriveAEAD(key []byte, label string, clientSalt, serverSalt []byte) (cipher.AEAD, error) {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(label))
+	_, _ = h.Write(clientSalt)
+	_, _ = h.Write(serverSalt)
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// aeadNonce returns the nonce for the frame with the counter passed.
+func aeadNonce(a cipher.AEAD, counter uint64) []byte {
+	n := make([]byte, a.NonceSize())
+	binary.BigEndian.PutUint64(n[len(n)-8:], counter)
+	return n
+}
+
+// Read ...
+func (c *aeadConn) Read(b []byte) (int, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	for len(c.pending) == 0 {
+		var size [4]byte
+		if _, err := io.ReadFull(c.Conn, size[:]); err != nil {
+			return 0, err
+		}
+		n := binary.BigEndian.Uint32(size[:])
+		if n > aeadMaxFrame+uint32(c.open.Overhead()) {
+			return 0, fmt.Errorf("aead frame too large: %v bytes", n)
+		}
+		sealed := make([]byte, n)
+		if _, err := io.ReadFull(c.Conn, sealed); err != nil {
+			return 0, err
+		}
+		plain, err := c.open.Open(sealed[:0], aeadNonce(c.open, c.readNonce), sealed, nil)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decrypt aead frame: %v", err)
+		}
+		c.readNonce++
+		c.pending = plain
+	}
+	n := copy(b, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+// Write ...
+func (c *aeadConn) Write(b []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	written := 0
+	for len(b) > 0 {
+		chunk := b[:min(len(b), aeadMaxFrame)]
+		frame := make([]byte, 4, 4+len(chunk)+c.seal.Overhead())
+		frame = c.seal.Seal(frame, aeadNonce(c.seal, c.writeNonce), chunk, nil)
+		binary.BigEndian.PutUint32(frame, uint32(len(frame)-4))
+		c.writeNonce++
+		if _, err := c.Conn.Write(frame); err != nil {
+			return written, err
+		}
+		written += len(chunk)
+		b = b[len(chunk):]
+	}
+	return written, nil
+}
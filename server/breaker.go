@@ -0,0 +1,140 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of the circuit of a server in a Breaker.
+type CircuitState string
+
+const (
+	// CircuitClosed is the state of servers that are dialed as usual.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen is the state of servers that failed too often. Dials to them fail immediately until the
+	// cooldown of the Breaker has passed.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen is the state of servers whose cooldown has passed. A single dial is let through to
+	// check if the server recovered.
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// ErrCircuitOpen is returned by Breaker.Allow if the circuit of the server dialed is open or half open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// Breaker is a circuit breaker for servers. After a server failed to be dialed a number of times in a row, its
+// circuit is opened and dials to it fail immediately for a cooldown period, so that players fall back to other
+// servers without waiting for the dial to time out.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// circuit holds the state of a single server in a Breaker.
+type circuit struct {
+	state    CircuitState
+	failures int
+	opened   time.Time
+}
+
+// NewBreaker returns a new Breaker that opens the circuit of a server after threshold consecutive failed
+// dials and keeps it open for the cooldown passed.
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		threshold: max(threshold, 1),
+		cooldown:  cooldown,
+		circuits:  make(map[string]*circuit),
+	}
+}
+
+// Allow returns an error if the circuit of the server at the address passed is open. If nil is returned, the
+// result of the dial must be reported using Record, or the dial must be released using Release if it did not
+// complete.
+func (b *Breaker) Allow(addr string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[addr]
+	if !ok {
+		return nil
+	}
+	switch c.state {
+	case CircuitOpen:
+		if remaining := b.cooldown - time.Since(c.opened); remaining > 0 {
+			return fmt.Errorf("circuit of %v is open for %v: %w", addr, remaining.Round(time.Second), ErrCircuitOpen)
+		}
+		c.state = CircuitHalfOpen
+		return nil
+	case CircuitHalfOpen:
+		return fmt.Errorf("circuit of %v is half open: %w", addr, ErrCircuitOpen)
+	}
+	return nil
+}
+
+// Record records the result of a dial to the server at the address passed, opening or closing its circuit.
+func (b *Breaker) Record(addr string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[addr]
+	if !ok {
+		c = &circuit{state: CircuitClosed}
+		b.circuits[addr] = c
+	}
+	if err == nil {
+		c.state, c.failures = CircuitClosed, 0
+		return
+	}
+	c.failures++
+	if c.state == CircuitHalfOpen || c.failures >= b.threshold {
+		c.state, c.opened = CircuitOpen, time.Now()
+	}
+}
+
+// Release releases a dial to the server at the address passed that was allowed by Allow but did not complete,
+// for example because it was cancelled, without recording a result. If the dial was the trial dial of a half
+// open circuit, the circuit is opened again without restarting its cooldown, so that the next dial is let
+// through as trial instead.
+func (b *Breaker) Release(addr string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if c, ok := b.circuits[addr]; ok && c.state == CircuitHalfOpen {
+		c.state = CircuitOpen
+	}
+}
+
+// State returns the state of the circuit of the server at the address passed.
+func (b *Breaker) State(addr string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if c, ok := b.circuits[addr]; ok {
+		return c.state
+	}
+	return CircuitClosed
+}
+
+// States returns the state of the circuit of every server dialed, keyed by the address of the server.
+func (b *Breaker) States() map[string]CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	states := make(map[string]CircuitState, len(b.circuits))
+	for addr, c := range b.circuits {
+		states[addr] = c.state
+	}
+	return states
+}
+
+// Reset closes the circuit of the server at the address passed.
+func (b *Breaker) Reset(addr string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.circuits, addr)
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	packet2 "github.com/spectrum-proxy/spectrum/server/packet"
+	"strings"
+)
+
+// noCompression is the ID prefixed to packets that are not compressed after compression was negotiated.
+const noCompression = 0xff
+
+// Compression configures the compression of the connection to a server. The zero value compresses every
+// packet using flate, which all servers support without negotiation.
+type Compression struct {
+	// Algorithm is the compression algorithm used, either "flate", "snappy" or "none". If empty, flate is
+	// used.
+	Algorithm string `yaml:"algorithm"`
+	// Threshold is the minimum size in bytes of packets that are compressed. Smaller packets are sent
+	// uncompressed.
+	Threshold uint16 `yaml:"threshold"`
+}
+
+// algorithm returns the ID of the compression algorithm.
+func (c Compression) algorithm() (uint16, error) {
+	switch strings.ToLower(c.Algorithm) {
+	case "", "flate":
+		return packet.CompressionAlgorithmFlate, nil
+	case "snappy":
+		return packet.CompressionAlgorithmSnappy, nil
+	case "none":
+		return packet.CompressionAlgorithmNone, nil
+	}
+	return 0, fmt.Errorf("unknown compression algorithm %q", c.Algorithm)
+}
+
+// negotiate negotiates the compression passed with the server. Negotiation is skipped for the default
+// compression, so that servers that do not support negotiation can still be connected to.
+func (c *Conn) negotiate(compression Compression) error {
+	algorithm, err := compression.algorithm()
+	if err != nil {
+		return err
+	}
+	if algorithm == packet.CompressionAlgorithmFlate && compression.Threshold == 0 {
+		return nil
+	}
+
+	err = c.WritePacket(&packet2.CompressionRequest{Algorithm: algorithm, Threshold: compression.Threshold})
+	if err != nil {
+		return fmt.Errorf("failed to write compression request packet: %v", err)
+	}
+	pk, err := c.Expect(packet.IDNetworkSettings, false)
+	if err != nil {
+		return fmt.Errorf("failed to read network settings packet: %v", err)
+	}
+
+	settings := pk.(*packet.NetworkSettings)
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.compressor = nil
+	if settings.CompressionAlgorithm != packet.CompressionAlgorithmNone {
+		compressor, ok := packet.CompressionByID(settings.CompressionAlgorithm)
+		if !ok {
+			return fmt.Errorf("server selected unknown compression algorithm %v", settings.CompressionAlgorithm)
+		}
+		c.compressor = compressor
+	}
+	c.threshold = int(settings.CompressionThreshold)
+	c.negotiated = true
+	return nil
+}
+
+// compress compresses a packet before it is written. Once compression was negotiated, the data is prefixed
+// with the ID of the algorithm it was compressed with, and packets below the threshold are left uncompressed.
+func (c *Conn) compress(data []byte) ([]byte, error) {
+	if !c.negotiated {
+		return c.compressor.Compress(data)
+	}
+	if c.compressor == nil || len(data) < c.threshold {
+		return append([]byte{noCompression}, data...), nil
+	}
+
+	compressed, err := c.compressor.Compress(data)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(c.compressor.EncodeCompression())}, compressed...), nil
+}
+
+// decompress decompresses a packet that was read.
+func (c *Conn) decompress(data []byte) ([]byte, error) {
+	if !c.negotiated {
+		return c.compressor.Decompress(data)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("missing compression algorithm")
+	}
+	if data[0] == noCompression {
+		return data[1:], nil
+	}
+
+	compressor, ok := packet.CompressionByID(uint16(data[0]))
+	if !ok {
+		return nil, fmt.Errorf("unknown compression algorithm %v", data[0])
+	}
+	return compressor.Decompress(data[1:])
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"github.com/sandertv/gophertunnel/minecraft"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
@@ -13,6 +14,7 @@ import (
 	"net"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // Conn is a connection to a server. It is used to read and write packets to the server, and to manage the
@@ -20,6 +22,10 @@ import (
 type Conn struct {
 	conn       net.Conn
 	compressor packet.Compression
+	// threshold is the minimum size of packets that are compressed, and negotiated specifies if compression
+	// was negotiated with the server, in which case packets are prefixed with their compression algorithm.
+	threshold  int
+	negotiated bool
 
 	reader *proto.Reader
 	writer *proto.Writer
@@ -29,12 +35,16 @@ type Conn struct {
 
 	gameData minecraft.GameData
 	shieldID atomic.Int32
+	observer atomic.Pointer[TrafficObserver]
 
 	closed chan struct{}
 
 	pool            packet.Pool
 	header          packet.Header
 	deferredPackets []packet.Packet
+	decode          map[uint32]struct{}
+	resourcePacks   []string
+	packsRequired   bool
 }
 
 // NewConn creates a new Conn with the innerConn and pool passed.
@@ -60,6 +70,7 @@ func NewConn(conn net.Conn, pool packet.Pool) *Conn {
 				return
 			default:
 				if err := c.reader.Read(); err != nil {
+					c.Close()
 					return
 				}
 			}
@@ -73,7 +84,7 @@ func NewConn(conn net.Conn, pool packet.Pool) *Conn {
 func (c *Conn) ReadPacket() (pk packet.Packet, err error) {
 	select {
 	case <-c.closed:
-		return nil, fmt.Errorf("connection closed")
+		return nil, net.ErrClosed
 	default:
 		return c.read()
 	}
@@ -104,8 +115,11 @@ func (c *Conn) WritePacket(pk packet.Packet) error {
 	}
 
 	pk.Marshal(protocol.NewWriter(buf, c.shieldID.Load()))
+	if observer := c.observer.Load(); observer != nil {
+		(*observer)(pk.ID(), buf.Len(), true)
+	}
 
-	data, err := c.compressor.Compress(buf.Bytes())
+	data, err := c.compress(buf.Bytes())
 	if err != nil {
 		return err
 	}
@@ -114,15 +128,25 @@ func (c *Conn) WritePacket(pk packet.Packet) error {
 
 // Expect reads a packet from the connection and expects it to have the ID passed. If the packet read does not
 // have the ID passed, it will be deferred and the function will be called again until a packet with the ID
-// passed is read. It returns the packet read, or an error if the packet could not be read.
+// passed is read. It returns the packet read, or an error if the packet could not be read. If the server sends
+// a Disconnect packet instead, a *DisconnectError is returned.
 func (c *Conn) Expect(id uint32, deferrable bool) (packet.Packet, error) {
 	pk, err := c.ReadPacket()
 	if err != nil {
 		return nil, err
 	}
 
+	if disconnect, ok := pk.(*packet.Disconnect); ok && id != packet.IDDisconnect {
+		return nil, &DisconnectError{Message: disconnect.Message}
+	}
 	if pk.ID() != id {
-		c.deferredPackets = append(c.deferredPackets, pk)
+		handled, err := c.handleResourcePacks(pk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to negotiate resource packs: %v", err)
+		}
+		if !handled {
+			c.deferredPackets = append(c.deferredPackets, pk)
+		}
 		return c.Expect(id, deferrable)
 	}
 
@@ -132,12 +156,39 @@ func (c *Conn) Expect(id uint32, deferrable bool) (packet.Packet, error) {
 	return pk, nil
 }
 
+// SetPassthrough enables passthrough mode for the connection. In passthrough mode, only packets with an ID
+// present in decode are decoded. All other packets are returned by ReadPacket as a *packet.Unknown holding
+// their raw payload, saving the cost of decoding and re-encoding them. Passing nil disables passthrough mode.
+// SetPassthrough must be called before the connection is read from concurrently.
+func (c *Conn) SetPassthrough(decode map[uint32]struct{}) {
+	c.decode = decode
+}
+
 // SetShieldID sets the shield ID of the connection. It is used to set the shield ID of the connection, which is
 // used to read and write packets.
 func (c *Conn) SetShieldID(id int32) {
 	c.shieldID.Store(id)
 }
 
+// TrafficObserver is notified of every packet read from or written to a Conn, with the ID of the packet, its
+// size in bytes before compression, and whether it was written to the server.
+type TrafficObserver func(id uint32, size int, written bool)
+
+// SetTrafficObserver sets the TrafficObserver notified of packets read from and written to the connection.
+// Passing nil removes the observer.
+func (c *Conn) SetTrafficObserver(observer TrafficObserver) {
+	if observer == nil {
+		c.observer.Store(nil)
+		return
+	}
+	c.observer.Store(&observer)
+}
+
+// ShieldID returns the shield ID of the connection.
+func (c *Conn) ShieldID() int32 {
+	return c.shieldID.Load()
+}
+
 // GameData ...
 func (c *Conn) GameData() minecraft.GameData {
 	return c.gameData
@@ -168,7 +219,12 @@ func (c *Conn) read() (pk packet.Packet, err error) {
 	c.readMu.Lock()
 	defer c.readMu.Unlock()
 
-	data, err := c.compressor.Decompress(c.reader.ReadPacket())
+	payload, err := c.reader.ReadPacket()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.decompress(payload)
 	if err != nil {
 		return nil, err
 	}
@@ -188,6 +244,15 @@ func (c *Conn) read() (pk packet.Packet, err error) {
 	if err := header.Read(buf); err != nil {
 		return nil, err
 	}
+	if observer := c.observer.Load(); observer != nil {
+		(*observer)(header.PacketID, len(data), false)
+	}
+
+	if c.decode != nil {
+		if _, ok := c.decode[header.PacketID]; !ok {
+			return &packet.Unknown{PacketID: header.PacketID, Payload: bytes.Clone(buf.Bytes())}, nil
+		}
+	}
 
 	factory, ok := c.pool[header.PacketID]
 	if !ok {
@@ -199,27 +264,53 @@ func (c *Conn) read() (pk packet.Packet, err error) {
 	return pk, nil
 }
 
-// login logs the connection into the server with the address, clientData and identityData passed. It returns
-// an error if the connection could not be logged in.
-func (c *Conn) login(addr string, clientData login.ClientData, identityData login.IdentityData) error {
-	err := c.WritePacket(&packet2.Connect{
+// handshake runs f, which exchanges the packets required before the connection can be used. Reads and writes
+// performed by f fail once the timeout passed has elapsed, if non-zero, or once the context passed is done.
+// The connection is closed if f returns an error.
+func (c *Conn) handshake(ctx context.Context, timeout time.Duration, f func() error) error {
+	if timeout > 0 {
+		_ = c.conn.SetDeadline(time.Now().Add(timeout))
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.conn.SetDeadline(time.Now())
+	})
+
+	err := f()
+	if !stop() && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		c.Close()
+		return err
+	}
+	_ = c.conn.SetDeadline(time.Time{})
+	return nil
+}
+
+// login logs the connection into the server with the address, clientData and identityData passed, requesting
+// the chunk radius passed. The Connect packet is signed with the secret passed if it is not empty. It returns an error if the connection could not be logged in.
+func (c *Conn) login(addr string, clientData login.ClientData, identityData login.IdentityData, chunkRadius int32, secret []byte) error {
+	connect := &packet2.Connect{
 		Addr:     addr,
 		EntityID: computeEntityID(identityData.XUID),
 
 		ClientData:   clientData,
 		IdentityData: identityData,
-	})
-	if err != nil {
+	}
+	if len(secret) > 0 {
+		connect.Sign(secret)
+	}
+	if err := c.WritePacket(connect); err != nil {
 		return fmt.Errorf("failed to write connect packet: %v", err)
 	}
 
 	startGamePacket, err := c.Expect(packet.IDStartGame, false)
 	if err != nil {
-		return fmt.Errorf("failed to read start game packet: %v", err)
+		return fmt.Errorf("failed to read start game packet: %w", err)
 	}
 
 	err = c.WritePacket(&packet.RequestChunkRadius{
-		ChunkRadius: 16,
+		ChunkRadius: chunkRadius,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to write request chunk radius packet: %v", err)
@@ -227,12 +318,12 @@ func (c *Conn) login(addr string, clientData login.ClientData, identityData logi
 
 	chunkRadiusUpdatedPacket, err := c.Expect(packet.IDChunkRadiusUpdated, true)
 	if err != nil {
-		return fmt.Errorf("failed to read chunk radius updated packet: %v", err)
+		return fmt.Errorf("failed to read chunk radius updated packet: %w", err)
 	}
 
 	_, err = c.Expect(packet.IDPlayStatus, true)
 	if err != nil {
-		return fmt.Errorf("failed to read play status packet: %v", err)
+		return fmt.Errorf("failed to read play status packet: %w", err)
 	}
 
 	err = c.WritePacket(&packet.SetLocalPlayerAsInitialised{
@@ -310,6 +401,11 @@ func (c *Conn) login(addr string, clientData login.ClientData, identityData logi
 	return nil
 }
 
+// EntityID returns the entity ID that servers assign to the player with the XUID passed.
+func EntityID(xuid string) int64 {
+	return computeEntityID(xuid)
+}
+
 // computeEntityID generates a deterministic entity ID from an XUID using FNV-1a.
 func computeEntityID(xuid string) int64 {
 	hash := int64(0)
@@ -1,29 +1,147 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
-	"net"
+	"time"
 )
 
+var (
+	// ErrUnreachable is returned by Dialer.Dial if no connection to the server could be opened, including if
+	// the circuit of the server is open.
+	ErrUnreachable = errors.New("server unreachable")
+	// ErrHandshakeFailed is returned by Dialer.Dial if a connection to the server was opened, but negotiating
+	// with or logging in to the server failed.
+	ErrHandshakeFailed = errors.New("handshake failed")
+)
+
+// DisconnectError is returned by Dialer.Dial, wrapped in ErrHandshakeFailed, if the server disconnected the
+// client while logging in, for example because the player is banned or the server is full.
+type DisconnectError struct {
+	// Message is the message the server disconnected the client with.
+	Message string
+}
+
+// Error ...
+func (e *DisconnectError) Error() string {
+	return fmt.Sprintf("disconnected by server: %v", e.Message)
+}
+
 type Dialer struct {
 	Origin       string
 	ClientData   login.ClientData
 	IdentityData login.IdentityData
+	// Compression is the compression negotiated with the server. The zero value uses the default compression
+	// without negotiating.
+	Compression Compression
+	// Transport is the Transport used to connect to the server. If nil, TCP is used.
+	Transport Transport
+	// Pool is a Pool of spare connections to the server. If set and a spare connection is available, it is
+	// used instead of opening a new connection. The Transport and Compression of the Pool are used for it.
+	Pool *Pool
+	// Breaker is the circuit Breaker the result of the dial is recorded in. If set and the circuit of the
+	// server is open, Dial fails immediately.
+	Breaker *Breaker
+	// DialTimeout is the maximum duration connecting to the server may take. If zero, connecting is only
+	// limited by the context passed to DialContext.
+	DialTimeout time.Duration
+	// LoginTimeout is the maximum duration negotiating with the server and receiving its game data may take.
+	// If zero, logging in is only limited by the context passed to DialContext.
+	LoginTimeout time.Duration
+	// Secret is the secret shared with the server that the Connect packet holding the identity of the client is
+	// signed with, so that the server can verify it using packet.Connect.Verify. If empty, the packet is not
+	// signed.
+	Secret []byte
+	// ChunkRadius is the chunk radius requested from the server when logging in. If zero, a radius of 16 is
+	// requested.
+	ChunkRadius int32
 }
 
+// Dial connects to the server at the address passed and logs in on behalf of the client.
 func (d Dialer) Dial(addr string) (*Conn, error) {
-	conn, err := net.Dial("tcp", addr)
-	if err != nil {
-		return nil, err
+	return d.DialContext(context.Background(), addr)
+}
+
+// DialContext connects to the server at the address passed and logs in on behalf of the client. Dialing is
+// aborted if the context passed is done before the connection is logged in.
+func (d Dialer) DialContext(ctx context.Context, addr string) (*Conn, error) {
+	if d.Breaker == nil {
+		return d.dial(ctx, addr)
+	}
+	if err := d.Breaker.Allow(addr); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnreachable, err)
+	}
+	c, err := d.dial(ctx, addr)
+	// Dials that failed because they were cancelled by the caller say nothing about the health of the server,
+	// so they are only released.
+	if err == nil || ctx.Err() == nil {
+		d.Breaker.Record(addr, breakerErr(err))
+	} else {
+		d.Breaker.Release(addr)
 	}
+	return c, err
+}
 
-	if tcpConn, ok := conn.(*net.TCPConn); ok {
-		_ = tcpConn.SetNoDelay(true)
-		_ = tcpConn.SetLinger(0)
-		_ = tcpConn.SetReadBuffer(1024 * 1024 * 8)
-		_ = tcpConn.SetWriteBuffer(1024 * 1024 * 8)
+// chunkRadius returns the chunk radius requested from the server.
+func (d Dialer) chunkRadius() int32 {
+	if d.ChunkRadius <= 0 {
+		return 16
 	}
+	return d.ChunkRadius
+}
+
+func (d Dialer) dial(ctx context.Context, addr string) (*Conn, error) {
+	if d.Pool != nil {
+		if c, ok := d.Pool.Get(); ok {
+			return c, handshakeErr(c.handshake(ctx, d.LoginTimeout, func() error {
+				return c.login(d.Origin, d.ClientData, d.IdentityData, d.chunkRadius(), d.Secret)
+			}))
+		}
+	}
+
+	transport := d.Transport
+	if transport == nil {
+		transport = TCP{}
+	}
+	dialCtx := ctx
+	if d.DialTimeout > 0 {
+		var cancel context.CancelFunc
+		dialCtx, cancel = context.WithTimeout(ctx, d.DialTimeout)
+		defer cancel()
+	}
+	conn, err := transport.Dial(dialCtx, addr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnreachable, err)
+	}
+
 	c := NewConn(conn, packet.NewServerPool())
-	return c, c.login(d.Origin, d.ClientData, d.IdentityData)
+	return c, handshakeErr(c.handshake(ctx, d.LoginTimeout, func() error {
+		if err := c.negotiate(d.Compression); err != nil {
+			return fmt.Errorf("failed to negotiate compression: %v", err)
+		}
+		return c.login(d.Origin, d.ClientData, d.IdentityData, d.chunkRadius(), d.Secret)
+	}))
+}
+
+// breakerErr returns the error recorded in a Breaker for a dial that failed with the error passed. Servers that
+// disconnect the client while logging in are reachable and responding, so nil is returned for them rather than
+// counting the dial as a failure.
+func breakerErr(err error) error {
+	var disconnect *DisconnectError
+	if errors.As(err, &disconnect) {
+		return nil
+	}
+	return err
+}
+
+// handshakeErr wraps the error passed, returned by a handshake, in ErrHandshakeFailed. It returns nil if err
+// is nil.
+func handshakeErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrHandshakeFailed, err)
 }
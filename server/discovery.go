@@ -1,6 +1,9 @@
 package server
 
-import "github.com/sandertv/gophertunnel/minecraft"
+import (
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/spectrum-proxy/spectrum/geoip"
+)
 
 type Discovery interface {
 	Discover(conn *minecraft.Conn) (string, error)
@@ -19,3 +22,33 @@ func NewStaticDiscovery(server string) *StaticDiscovery {
 		server: server,
 	}
 }
+
+// RegionDiscovery is a Discovery that picks a Discovery based on the region of the connecting player, for
+// example to route players to lobbies in their region.
+type RegionDiscovery struct {
+	resolver geoip.Resolver
+	regions  map[string]Discovery
+	fallback Discovery
+}
+
+// NewRegionDiscovery returns a RegionDiscovery that resolves the region of players using the resolver passed
+// and discovers their server with the Discovery of that region. Players whose region is unknown or has no
+// Discovery are passed to the fallback.
+func NewRegionDiscovery(resolver geoip.Resolver, regions map[string]Discovery, fallback Discovery) *RegionDiscovery {
+	return &RegionDiscovery{
+		resolver: resolver,
+		regions:  regions,
+		fallback: fallback,
+	}
+}
+
+func (r *RegionDiscovery) Discover(conn *minecraft.Conn) (string, error) {
+	region, err := geoip.Region(r.resolver, conn.RemoteAddr())
+	if err != nil {
+		return r.fallback.Discover(conn)
+	}
+	if discovery, ok := r.regions[region]; ok {
+		return discovery.Discover(conn)
+	}
+	return r.fallback.Discover(conn)
+}
@@ -0,0 +1,22 @@
+package packet
+
+import "github.com/sandertv/gophertunnel/minecraft/protocol"
+
+// CompressionRequest is sent by the proxy before Connect to request a compression algorithm and threshold
+// other than the default. The server answers with a NetworkSettings packet holding the compression selected,
+// after which every packet is prefixed with the ID of the algorithm it was compressed with.
+type CompressionRequest struct {
+	// Algorithm is the ID of the compression algorithm requested, as in packet.NetworkSettings.
+	Algorithm uint16
+	// Threshold is the minimum size of packets that are compressed.
+	Threshold uint16
+}
+
+func (pk *CompressionRequest) ID() uint32 {
+	return IDCompressionRequest
+}
+
+func (pk *CompressionRequest) Marshal(io protocol.IO) {
+	io.Uint16(&pk.Algorithm)
+	io.Uint16(&pk.Threshold)
+}
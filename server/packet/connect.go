@@ -1,17 +1,39 @@
 package packet
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"io"
+	"time"
 )
 
+// Connect is sent by the proxy to a server when it connects to it on behalf of a player, holding the data the
+// player logged in to the proxy with. If the proxy shares a secret with the server, the packet is signed, so
+// that the server can verify it was sent by the proxy using Verify. The Timestamp and Signature are only
+// encoded for signed packets, so unsigned packets keep the layout understood by servers predating signing.
+// Such servers cannot decode signed packets, so a secret should only be shared with servers that verify it.
 type Connect struct {
 	Addr     string
 	EntityID int64
 
 	ClientData   login.ClientData
 	IdentityData login.IdentityData
+
+	// Timestamp is the Unix time in milliseconds at which the packet was signed.
+	Timestamp int64
+	// Signature is the HMAC-SHA256 of the packet computed with the secret shared by the proxy and the server.
+	// It is empty if the packet is not signed.
+	Signature []byte
+
+	// clientData and identityData hold the JSON encoded ClientData and IdentityData as signed or read, so
+	// that the signature is verified against the exact bytes sent.
+	clientData, identityData []byte
 }
 
 func (pk *Connect) ID() uint32 {
@@ -19,12 +41,94 @@ func (pk *Connect) ID() uint32 {
 }
 
 func (pk *Connect) Marshal(io protocol.IO) {
-	clientData, _ := json.Marshal(pk.ClientData)
-	identityData, _ := json.Marshal(pk.IdentityData)
+	clientData, identityData := pk.clientData, pk.identityData
+	if clientData == nil || identityData == nil {
+		clientData, _ = json.Marshal(pk.ClientData)
+		identityData, _ = json.Marshal(pk.IdentityData)
+	}
 
 	io.String(&pk.Addr)
 	io.Varint64(&pk.EntityID)
 
 	io.ByteSlice(&clientData)
 	io.ByteSlice(&identityData)
+
+	if r, ok := io.(*protocol.Reader); ok {
+		pk.readSignature(r)
+	} else if len(pk.Signature) > 0 {
+		io.Int64(&pk.Timestamp)
+		io.ByteSlice(&pk.Signature)
+	}
+
+	if _, ok := io.(*protocol.Reader); ok {
+		pk.clientData, pk.identityData = clientData, identityData
+		_ = json.Unmarshal(clientData, &pk.ClientData)
+		_ = json.Unmarshal(identityData, &pk.IdentityData)
+	}
+}
+
+// readSignature reads the Timestamp and Signature of the packet from the reader passed, if the packet is
+// signed. Unsigned packets end before them.
+func (pk *Connect) readSignature(r *protocol.Reader) {
+	defer func() {
+		if err := recover(); err != nil && err != io.EOF {
+			panic(err)
+		}
+	}()
+	r.Int64(&pk.Timestamp)
+	r.ByteSlice(&pk.Signature)
+}
+
+// Sign signs the packet with the secret passed, setting its Timestamp and Signature. The ClientData and
+// IdentityData must not be changed after signing.
+func (pk *Connect) Sign(secret []byte) {
+	pk.clientData, _ = json.Marshal(pk.ClientData)
+	pk.identityData, _ = json.Marshal(pk.IdentityData)
+	pk.Timestamp = time.Now().UnixMilli()
+	pk.Signature = pk.mac(secret)
+}
+
+// Verify checks if the packet was signed with the secret passed no longer than maxAge ago, returning an error
+// if it was not. Servers should call Verify on every Connect packet received and reject the connection if it
+// fails, so that players cannot connect to the server directly while pretending to be someone else.
+func (pk *Connect) Verify(secret []byte, maxAge time.Duration) error {
+	if len(pk.Signature) == 0 {
+		return errors.New("connect packet is not signed")
+	}
+	if pk.clientData == nil || pk.identityData == nil {
+		pk.clientData, _ = json.Marshal(pk.ClientData)
+		pk.identityData, _ = json.Marshal(pk.IdentityData)
+	}
+	if !hmac.Equal(pk.Signature, pk.mac(secret)) {
+		return errors.New("invalid connect packet signature")
+	}
+	if age := time.Since(time.UnixMilli(pk.Timestamp)); maxAge > 0 && (age > maxAge || age < -maxAge) {
+		return fmt.Errorf("connect packet signed %v ago, exceeding %v", age, maxAge)
+	}
+	return nil
+}
+
+// VerifyAny checks if the packet was signed with any of the secrets passed no longer than maxAge ago, like
+// Verify. It may be used while rotating secrets, accepting both the old and the new secret until all proxies
+// use the new one.
+func (pk *Connect) VerifyAny(secrets [][]byte, maxAge time.Duration) error {
+	err := errors.New("no secrets to verify connect packet with")
+	for _, secret := range secrets {
+		if err = pk.Verify(secret, maxAge); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// mac computes the HMAC-SHA256 of the packet with the secret passed.
+func (pk *Connect) mac(secret []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	for _, b := range [][]byte{[]byte(pk.Addr), pk.clientData, pk.identityData} {
+		_ = binary.Write(h, binary.LittleEndian, uint32(len(b)))
+		_, _ = h.Write(b)
+	}
+	_ = binary.Write(h, binary.LittleEndian, pk.EntityID)
+	_ = binary.Write(h, binary.LittleEndian, pk.Timestamp)
+	return h.Sum(nil)
 }
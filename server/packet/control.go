@@ -0,0 +1,45 @@
+package packet
+
+import "github.com/sandertv/gophertunnel/minecraft/protocol"
+
+// ControlRequest is sent by a server to instruct the proxy to perform an action, such as transferring a player
+// or broadcasting a message. The proxy answers every request with a ControlResponse carrying the same
+// RequestID.
+type ControlRequest struct {
+	// RequestID is an ID chosen by the server to match the response to the request.
+	RequestID int64
+	// Action is the name of the action to perform, such as "transfer", "broadcast" or "player_count".
+	Action string
+	// Payload holds the JSON encoded arguments of the action.
+	Payload []byte
+}
+
+func (pk *ControlRequest) ID() uint32 {
+	return IDControlRequest
+}
+
+func (pk *ControlRequest) Marshal(io protocol.IO) {
+	io.Int64(&pk.RequestID)
+	io.String(&pk.Action)
+	io.ByteSlice(&pk.Payload)
+}
+
+// ControlResponse is sent by the proxy to a server in response to a ControlRequest.
+type ControlResponse struct {
+	// RequestID is the ID of the ControlRequest the response belongs to.
+	RequestID int64
+	// Error holds the reason the request failed. It is empty if the request succeeded.
+	Error string
+	// Payload holds the JSON encoded result of the action.
+	Payload []byte
+}
+
+func (pk *ControlResponse) ID() uint32 {
+	return IDControlResponse
+}
+
+func (pk *ControlResponse) Marshal(io protocol.IO) {
+	io.Int64(&pk.RequestID)
+	io.String(&pk.Error)
+	io.ByteSlice(&pk.Payload)
+}
@@ -4,4 +4,7 @@ const (
 	IDConnect = iota + 500
 	IDLatency
 	IDTransfer
+	IDControlRequest
+	IDControlResponse
+	IDCompressionRequest
 )
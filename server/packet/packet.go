@@ -5,7 +5,10 @@ import "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 func init() {
 	packet.RegisterPacketFromClient(IDConnect, func() packet.Packet { return &Connect{} })
 	packet.RegisterPacketFromClient(IDLatency, func() packet.Packet { return &Latency{} })
+	packet.RegisterPacketFromClient(IDControlResponse, func() packet.Packet { return &ControlResponse{} })
+	packet.RegisterPacketFromClient(IDCompressionRequest, func() packet.Packet { return &CompressionRequest{} })
 
 	packet.RegisterPacketFromServer(IDLatency, func() packet.Packet { return &Latency{} })
 	packet.RegisterPacketFromServer(IDTransfer, func() packet.Packet { return &Transfer{} })
+	packet.RegisterPacketFromServer(IDControlRequest, func() packet.Packet { return &ControlRequest{} })
 }
@@ -0,0 +1,201 @@
+package server
+
+import (
+	"context"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"sync"
+	"time"
+)
+
+// PoolConfig configures a Pool of spare connections to a server.
+type PoolConfig struct {
+	// Size is the amount of spare connections kept open.
+	Size int `yaml:"size"`
+	// TTL is the time in milliseconds after which a spare connection that was not used is replaced. If zero,
+	// spare connections are kept until they are used or closed by the server.
+	TTL int64 `yaml:"ttl"`
+	// HealthInterval is the interval in milliseconds at which spare connections are checked and replenished.
+	// If zero, a second is used.
+	HealthInterval int64 `yaml:"health_interval"`
+}
+
+// poolDialTimeout is the maximum duration connecting to a server may take when opening a spare connection.
+const poolDialTimeout = time.Second * 10
+
+// pooledConn is a spare connection held by a Pool.
+type pooledConn struct {
+	conn    *Conn
+	created time.Time
+}
+
+// Pool keeps spare connections to a server open, so that sessions joining the server skip connecting and
+// negotiating compression. As logging in requires the identity of the player, spare connections are not
+// logged in until they are used.
+type Pool struct {
+	addr        string
+	transport   Transport
+	compression Compression
+	config      PoolConfig
+
+	mu   sync.Mutex
+	idle []pooledConn
+
+	refill chan struct{}
+	closed chan struct{}
+	once   sync.Once
+}
+
+// NewPool returns a new Pool keeping spare connections to the server at the address passed, connected using
+// the Transport and Compression passed. The Pool starts filling up immediately.
+func NewPool(addr string, transport Transport, compression Compression, config PoolConfig) *Pool {
+	if transport == nil {
+		transport = TCP{}
+	}
+	p := &Pool{
+		addr:        addr,
+		transport:   transport,
+		compression: compression,
+		config:      config,
+		refill:      make(chan struct{}, 1),
+		closed:      make(chan struct{}),
+	}
+	go p.maintain()
+	return p
+}
+
+// Get takes a spare connection from the Pool. It returns false if no healthy connection is available. The
+// Pool is replenished in the background.
+func (p *Pool) Get() (*Conn, bool) {
+	defer p.requestRefill()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.idle) > 0 {
+		pc := p.idle[0]
+		p.idle = p.idle[1:]
+		if p.healthy(pc) {
+			return pc.conn, true
+		}
+		pc.conn.Close()
+	}
+	return nil, false
+}
+
+// Close closes the Pool and all of its spare connections.
+func (p *Pool) Close() {
+	p.once.Do(func() {
+		close(p.closed)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for _, pc := range p.idle {
+			pc.conn.Close()
+		}
+		p.idle = nil
+	})
+}
+
+// maintain checks the spare connections of the Pool at the configured interval, replacing those that were
+// closed or expired, until the Pool is closed.
+func (p *Pool) maintain() {
+	interval := time.Duration(p.config.HealthInterval) * time.Millisecond
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p.prune()
+		p.fill()
+		select {
+		case <-p.closed:
+			return
+		case <-ticker.C:
+		case <-p.refill:
+		}
+	}
+}
+
+// prune closes and removes all spare connections that are no longer healthy.
+func (p *Pool) prune() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	idle := p.idle[:0]
+	for _, pc := range p.idle {
+		if p.healthy(pc) {
+			idle = append(idle, pc)
+			continue
+		}
+		pc.conn.Close()
+	}
+	p.idle = idle
+}
+
+// fill opens spare connections until the Pool holds the configured amount. It stops at the first connection
+// that fails, leaving the remainder to the next attempt.
+func (p *Pool) fill() {
+	for {
+		p.mu.Lock()
+		missing := p.config.Size - len(p.idle)
+		p.mu.Unlock()
+		if missing <= 0 {
+			return
+		}
+
+		conn, err := p.dial()
+		if err != nil {
+			return
+		}
+
+		p.mu.Lock()
+		select {
+		case <-p.closed:
+			conn.Close()
+			p.mu.Unlock()
+			return
+		default:
+		}
+		p.idle = append(p.idle, pooledConn{conn: conn, created: time.Now()})
+		p.mu.Unlock()
+	}
+}
+
+// dial opens a new spare connection to the server.
+func (p *Pool) dial() (*Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), poolDialTimeout)
+	defer cancel()
+
+	netConn, err := p.transport.Dial(ctx, p.addr)
+	if err != nil {
+		return nil, err
+	}
+	conn := NewConn(netConn, packet.NewServerPool())
+	if err := conn.negotiate(p.compression); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// healthy returns true if the spare connection passed is still open and has not expired.
+func (p *Pool) healthy(pc pooledConn) bool {
+	if p.config.TTL > 0 && time.Since(pc.created) > time.Duration(p.config.TTL)*time.Millisecond {
+		return false
+	}
+	select {
+	case <-pc.conn.closed:
+		return false
+	default:
+		return true
+	}
+}
+
+// requestRefill asks the Pool to replenish its spare connections without waiting for the next check.
+func (p *Pool) requestRefill() {
+	select {
+	case p.refill <- struct{}{}:
+	default:
+	}
+}
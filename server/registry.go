@@ -0,0 +1,171 @@
+package server
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Change describes a change made to the servers of a Registry.
+type Change struct {
+	// Name is the name of the server that was changed.
+	Name string
+	// Addr is the new address of the server. It is empty if the server was removed.
+	Addr string
+}
+
+// Registry maps the logical names of servers, such as "lobby" or "skywars-1", to their addresses. Functions
+// subscribed to the registry are notified whenever a server is added, changed or removed.
+type Registry struct {
+	servers     map[string]string
+	subscribers []func(Change)
+	mu          sync.RWMutex
+}
+
+// NewRegistry returns a new Registry holding the servers passed, keyed by their name.
+func NewRegistry(servers map[string]string) *Registry {
+	r := &Registry{servers: make(map[string]string, len(servers))}
+	for name, addr := range servers {
+		r.servers[strings.ToLower(name)] = addr
+	}
+	return r
+}
+
+// Add adds a server with the name and address passed, replacing any server with the same name.
+func (r *Registry) Add(name string, addr string) {
+	r.mu.Lock()
+	changes := r.set(strings.ToLower(name), addr)
+	r.mu.Unlock()
+	r.notify(changes)
+}
+
+// Remove removes the server with the name passed.
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	changes := r.set(strings.ToLower(name), "")
+	r.mu.Unlock()
+	r.notify(changes)
+}
+
+// Set replaces all servers in the registry with the servers passed, keyed by their name. Subscribers are only
+// notified of the servers that actually changed.
+func (r *Registry) Set(servers map[string]string) {
+	normalised := make(map[string]string, len(servers))
+	for name, addr := range servers {
+		normalised[strings.ToLower(name)] = addr
+	}
+
+	r.mu.Lock()
+	var changes []Change
+	for name := range r.servers {
+		if _, ok := normalised[name]; !ok {
+			changes = append(changes, r.set(name, "")...)
+		}
+	}
+	for name, addr := range normalised {
+		changes = append(changes, r.set(name, addr)...)
+	}
+	r.mu.Unlock()
+	r.notify(changes)
+}
+
+// Load reads a YAML file at the path passed that maps server names to addresses and replaces the servers in
+// the registry with them. Load may be called at any time to reload the servers without restarting the proxy.
+func (r *Registry) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read servers: %w", err)
+	}
+
+	servers := make(map[string]string)
+	if err := yaml.Unmarshal(data, &servers); err != nil {
+		return fmt.Errorf("decode servers: %w", err)
+	}
+	r.Set(servers)
+	return nil
+}
+
+// Subscribe registers a function that is called for every change made to the servers of the registry.
+func (r *Registry) Subscribe(f func(Change)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscribers = append(r.subscribers, f)
+}
+
+// Resolve returns the address of the server with the name passed. If no server with that name exists, the
+// name is assumed to already be an address and is returned as is.
+func (r *Registry) Resolve(name string) string {
+	if addr, ok := r.Lookup(name); ok {
+		return addr
+	}
+	return name
+}
+
+// Lookup returns the address of the server with the name passed. The lookup is case-insensitive.
+func (r *Registry) Lookup(name string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	addr, ok := r.servers[strings.ToLower(name)]
+	return addr, ok
+}
+
+// Name returns the name of the server at the address passed. If multiple servers share the address, the
+// first name in alphabetical order is returned. If no server has the address, the address is returned as is.
+func (r *Registry) Name(addr string) string {
+	for _, name := range r.Names() {
+		if a, ok := r.Lookup(name); ok && a == addr {
+			return name
+		}
+	}
+	return addr
+}
+
+// Names returns the names of all servers in the registry in alphabetical order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.servers))
+	for name := range r.servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// set sets the address of the server with the name passed, removing it if addr is empty. It returns the
+// changes made, which must be passed to notify once mu is released. set must be called with mu held.
+func (r *Registry) set(name string, addr string) []Change {
+	current, ok := r.servers[name]
+	if addr == "" {
+		if !ok {
+			return nil
+		}
+		delete(r.servers, name)
+	} else {
+		if ok && current == addr {
+			return nil
+		}
+		r.servers[name] = addr
+	}
+	return []Change{{Name: name, Addr: addr}}
+}
+
+// notify calls all subscribers of the registry with the changes passed.
+func (r *Registry) notify(changes []Change) {
+	if len(changes) == 0 {
+		return
+	}
+
+	r.mu.RLock()
+	subscribers := r.subscribers
+	r.mu.RUnlock()
+	for _, change := range changes {
+		for _, f := range subscribers {
+			f(change)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// handleResourcePacks answers the resource pack negotiation of a server on behalf of the client. Clients can
+// only download resource packs while logging into the proxy, so the server is told that all packs are present
+// and no pack is ever downloaded. The packs the server uses are recorded, so that they can be compared to the
+// packs of the client through ResourcePacks and ResourcePacksRequired. handleResourcePacks returns false if the packet passed is not
+// part of the negotiation.
+func (c *Conn) handleResourcePacks(pk packet.Packet) (bool, error) {
+	switch pk := pk.(type) {
+	case *packet.ResourcePacksInfo:
+		c.resourcePacks = c.resourcePacks[:0]
+		c.packsRequired = pk.TexturePackRequired
+		for _, pack := range pk.TexturePacks {
+			c.resourcePacks = append(c.resourcePacks, pack.UUID+"_"+pack.Version)
+		}
+		for _, pack := range pk.BehaviourPacks {
+			c.resourcePacks = append(c.resourcePacks, pack.UUID+"_"+pack.Version)
+		}
+		return true, c.WritePacket(&packet.ResourcePackClientResponse{Response: packet.PackResponseAllPacksDownloaded})
+	case *packet.ResourcePackStack:
+		return true, c.WritePacket(&packet.ResourcePackClientResponse{Response: packet.PackResponseCompleted})
+	}
+	return false, nil
+}
+
+// ResourcePacks returns the resource packs the server announced while the connection was logging in, in the
+// form "uuid_version".
+func (c *Conn) ResourcePacks() []string {
+	return c.resourcePacks
+}
+
+// ResourcePacksRequired checks if the server requires clients to have the resource packs it announced while the
+// connection was logging in.
+func (c *Conn) ResourcePacksRequired() bool {
+	return c.packsRequired
+}
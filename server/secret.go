@@ -0,0 +1,42 @@
+package server
+
+import (
+	"maps"
+	"sync"
+)
+
+// Secrets holds the secrets shared with servers that the Connect packets sent to them are signed with, so
+// that servers only accept connections from proxies knowing their secret. A server uses its own secret if it
+// has one, or the global secret otherwise. Secrets may be rotated at any time using Set: connections opened
+// after the call use the new secrets.
+type Secrets struct {
+	mu      sync.RWMutex
+	global  []byte
+	servers map[string][]byte
+}
+
+// NewSecrets returns new Secrets holding the global secret and the secrets of servers passed, keyed by the
+// address of the server.
+func NewSecrets(global []byte, servers map[string][]byte) *Secrets {
+	s := &Secrets{}
+	s.Set(global, servers)
+	return s
+}
+
+// Set replaces the global secret and the secrets of servers, keyed by the address of the server.
+func (s *Secrets) Set(global []byte, servers map[string][]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.global, s.servers = global, maps.Clone(servers)
+}
+
+// Secret returns the secret shared with the server at the address passed, or nil if connections to it are
+// not signed.
+func (s *Secrets) Secret(addr string) []byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if secret, ok := s.servers[addr]; ok {
+		return secret
+	}
+	return s.global
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Transport establishes the underlying connections to servers. Transports other than the built in ones may be
+// registered using RegisterTransport so that they can be selected per server in the config. No QUIC transport
+// is built in, as the proxy does not depend on a QUIC implementation. One may be registered by wrapping a
+// bidirectional stream of a QUIC connection in a net.Conn returned by Dial.
+type Transport interface {
+	// Dial connects to the server at the address passed. It must return once the context passed is done.
+	Dial(ctx context.Context, addr string) (net.Conn, error)
+}
+
+// TCP is the default Transport, connecting to servers over plain TCP.
+type TCP struct{}
+
+// Dial ...
+func (TCP) Dial(ctx context.Context, addr string) (net.Conn, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		_ = tcpConn.SetNoDelay(true)
+		_ = tcpConn.SetLinger(0)
+		_ = tcpConn.SetKeepAlive(true)
+		_ = tcpConn.SetKeepAlivePeriod(time.Second * 5)
+		_ = tcpConn.SetReadBuffer(1024 * 1024 * 8)
+		_ = tcpConn.SetWriteBuffer(1024 * 1024 * 8)
+	}
+	return conn, nil
+}
+
+// TLS is a Transport connecting to servers over TCP secured with TLS, which is useful when servers are
+// reached over the internet, for example when they are located in another datacenter.
+type TLS struct {
+	// Config is the TLS configuration used. If nil, the certificate of the server is verified against the
+	// root certificates of the system. If no ServerName is set, the host of the address dialed is used.
+	Config *tls.Config
+}
+
+// Dial ...
+func (t TLS) Dial(ctx context.Context, addr string) (net.Conn, error) {
+	conn, err := TCP{}.Dial(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{}
+	if t.Config != nil {
+		config = t.Config.Clone()
+	}
+	if config.ServerName == "" {
+		host, _, _ := net.SplitHostPort(addr)
+		config.ServerName = host
+	}
+
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to perform TLS handshake: %v", err)
+	}
+	return tlsConn, nil
+}
+
+var (
+	transportsMu sync.RWMutex
+	// transports holds all registered transports, keyed by their name.
+	transports = map[string]Transport{
+		"tcp": TCP{},
+		"tls": TLS{},
+	}
+)
+
+// RegisterTransport registers a Transport under the name passed, so that it can be selected for a server by
+// its name. Registering a Transport with a name that is already taken overwrites the existing Transport.
+func RegisterTransport(name string, transport Transport) {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	transports[strings.ToLower(name)] = transport
+}
+
+// TransportByName looks up a Transport by the name it was registered with. The transports "tcp" and "tls"
+// are always available.
+func TransportByName(name string) (Transport, bool) {
+	transportsMu.RLock()
+	defer transportsMu.RUnlock()
+	transport, ok := transports[strings.ToLower(name)]
+	return transport, ok
+}
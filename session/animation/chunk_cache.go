@@ -0,0 +1,52 @@
+package animation
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"sync"
+)
+
+// chunkKey is the key of an empty chunk in the chunk cache.
+type chunkKey struct {
+	// dimension is the dimension the chunk is sent in, and biomes the dimension whose biomes the chunk holds.
+	dimension, biomes int32
+}
+
+// chunkCache holds LevelChunk packets of empty chunks at the zero position, keyed by chunkKey. Apart from
+// their position, these are identical for every chunk sent, so they are only built once per process.
+var chunkCache sync.Map
+
+// emptyChunk returns the payload of an empty chunk in the dimension passed.
+func emptyChunk(dimension int32) []byte {
+	if dimension == packet.DimensionEnd {
+		return ChunkEnd
+	}
+
+	if dimension == packet.DimensionNether {
+		return ChunkNether
+	}
+
+	return ChunkOverworld
+}
+
+// levelChunk returns a LevelChunk packet holding an empty chunk at the position passed. A new packet is
+// returned for every call, while the payload of the chunk is shared between all of them.
+func levelChunk(dimension, biomes int32, pos protocol.ChunkPos) *packet.LevelChunk {
+	pk := *chunkTemplate(chunkKey{dimension: dimension, biomes: biomes})
+	pk.Position = pos
+	return &pk
+}
+
+// chunkTemplate returns the LevelChunk packet of an empty chunk at the zero position, building it if it is
+// not yet cached.
+func chunkTemplate(key chunkKey) *packet.LevelChunk {
+	if pk, ok := chunkCache.Load(key); ok {
+		return pk.(*packet.LevelChunk)
+	}
+	pk, _ := chunkCache.LoadOrStore(key, &packet.LevelChunk{
+		Dimension:     key.dimension,
+		SubChunkCount: 1,
+		RawPayload:    emptyChunk(key.biomes),
+	})
+	return pk.(*packet.LevelChunk)
+}
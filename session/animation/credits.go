@@ -0,0 +1,24 @@
+package animation
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Credits is an Animation that rolls the end credits while the session is transferred, hiding the world of
+// the client until it is on the new server.
+type Credits struct{}
+
+func (animation *Credits) Play(conn *minecraft.Conn, serverGameData minecraft.GameData) {
+	_ = conn.WritePacket(&packet.ShowCredits{
+		PlayerRuntimeID: conn.GameData().EntityRuntimeID,
+		StatusType:      packet.ShowCreditsStatusStart,
+	})
+}
+
+func (animation *Credits) Clear(conn *minecraft.Conn, serverGameData minecraft.GameData) {
+	_ = conn.WritePacket(&packet.ShowCredits{
+		PlayerRuntimeID: conn.GameData().EntityRuntimeID,
+		StatusType:      packet.ShowCreditsStatusEnd,
+	})
+}
@@ -6,24 +6,62 @@ import (
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
 
+// Dimension is an Animation that moves the client to a placeholder dimension while the session is
+// transferred, showing the loading screen of the client, and moves it to the dimension of the new server
+// afterwards. Empty chunks are sent around the player in the placeholder dimension so that the client does
+// not get stuck on the loading screen.
 type Dimension struct {
+	// Placeholder is the dimension the client is moved to while the session is transferred. If nil or equal to
+	// the dimension of the new server, the nether is used, or the end if the new server is in the nether.
+	Placeholder *int32
+	// ChunkRadius is the radius in chunks around the player in which empty chunks are sent. If zero, a radius
+	// of 4 chunks is used.
+	ChunkRadius int32
+	// NoChunks disables sending empty chunks in the placeholder dimension.
+	NoChunks bool
 }
 
 func (animation *Dimension) Play(conn *minecraft.Conn, serverGameData minecraft.GameData) {
-	var dimension int32
-	if conn.GameData().Dimension == packet.DimensionNether {
-		dimension = packet.DimensionEnd
-	} else {
-		dimension = packet.DimensionNether
-	}
+	dimension := animation.placeholder(serverGameData.Dimension)
 	sendDimension(conn, serverGameData, dimension, false)
+	if animation.NoChunks {
+		return
+	}
+
+	radius := animation.ChunkRadius
+	if radius == 0 {
+		radius = 4
+	}
+
+	// The chunks carry the biomes of the dimension of the new server, so that the fog of the client already
+	// matches it while loading.
+	pos := serverGameData.PlayerPosition
+	chunkX := int32(pos.X()) >> 4
+	chunkZ := int32(pos.Z()) >> 4
+	for x := chunkX - radius; x <= chunkX+radius; x++ {
+		for z := chunkZ - radius; z <= chunkZ+radius; z++ {
+			_ = conn.WritePacket(levelChunk(dimension, serverGameData.Dimension, protocol.ChunkPos{x, z}))
+		}
+	}
 }
 
 func (animation *Dimension) Clear(conn *minecraft.Conn, serverGameData minecraft.GameData) {
 	_ = conn.WritePacket(&packet.PlayStatus{
 		Status: packet.PlayStatusPlayerSpawn,
 	})
-	sendDimension(conn, serverGameData, packet.DimensionOverworld, true)
+	sendDimension(conn, serverGameData, serverGameData.Dimension, true)
+}
+
+// placeholder returns the dimension the client is moved to while transferring to a server in the dimension
+// passed.
+func (animation *Dimension) placeholder(target int32) int32 {
+	if animation.Placeholder != nil && *animation.Placeholder != target {
+		return *animation.Placeholder
+	}
+	if target == packet.DimensionNether {
+		return packet.DimensionEnd
+	}
+	return packet.DimensionNether
 }
 
 func sendDimension(conn *minecraft.Conn, serverGameData minecraft.GameData, dimension int32, playStatus bool) {
@@ -0,0 +1,48 @@
+package animation
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/spectrum-proxy/spectrum/session/camera"
+	"image/color"
+	"time"
+)
+
+// Fade is an Animation that fades the screen of the client to a colour while the session is transferred, and
+// fades it back in once the client is on the new server.
+type Fade struct {
+	// Colour is the colour the screen is faded to. Only the red, green and blue components are used, so
+	// the zero value fades the screen to black.
+	Colour color.RGBA
+	// FadeIn is the time it takes for the screen to be covered. If zero, 250 milliseconds are used.
+	FadeIn time.Duration
+	// FadeOut is the time it takes for the screen to be uncovered after the transfer. If zero, 250
+	// milliseconds are used.
+	FadeOut time.Duration
+	// Base is an Animation played underneath the fade, such as Dimension. If nil, the dimension of the
+	// client is not changed.
+	Base Animation
+}
+
+func (animation *Fade) Play(conn *minecraft.Conn, serverGameData minecraft.GameData) {
+	// The screen stays covered until Clear is called, so the wait duration only serves as an upper bound in
+	// case the transfer never completes.
+	camera.New(conn).FadeOut(animation.Colour, orDefault(animation.FadeIn), time.Minute, 0)
+	if animation.Base != nil {
+		animation.Base.Play(conn, serverGameData)
+	}
+}
+
+func (animation *Fade) Clear(conn *minecraft.Conn, serverGameData minecraft.GameData) {
+	if animation.Base != nil {
+		animation.Base.Clear(conn, serverGameData)
+	}
+	camera.New(conn).FadeOut(animation.Colour, 0, 0, orDefault(animation.FadeOut))
+}
+
+// orDefault returns the duration passed, or 250 milliseconds if it is zero.
+func orDefault(d time.Duration) time.Duration {
+	if d == 0 {
+		return time.Millisecond * 250
+	}
+	return d
+}
@@ -0,0 +1,53 @@
+package animation
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	animationsMu sync.RWMutex
+	// animations holds functions creating the registered animations, keyed by their lowercase name.
+	animations = map[string]func() Animation{
+		"credits":   func() Animation { return &Credits{} },
+		"dimension": func() Animation { return &Dimension{} },
+		"fade":      func() Animation { return &Fade{} },
+		"seamless":  func() Animation { return &Seamless{} },
+		"title":     func() Animation { return &Title{} },
+	}
+)
+
+// Register registers a function creating an Animation under the name passed, so that it may be selected by
+// its name. Registering a name that is already taken overwrites the existing animation.
+func Register(name string, f func() Animation) {
+	animationsMu.Lock()
+	defer animationsMu.Unlock()
+	animations[strings.ToLower(name)] = f
+}
+
+// ByName creates a new instance of the animation registered under the name passed. The lookup is
+// case-insensitive.
+func ByName(name string) (Animation, bool) {
+	animationsMu.RLock()
+	defer animationsMu.RUnlock()
+
+	f, ok := animations[strings.ToLower(name)]
+	if !ok {
+		return nil, false
+	}
+	return f(), true
+}
+
+// Names returns the names of all registered animations in alphabetical order.
+func Names() []string {
+	animationsMu.RLock()
+	defer animationsMu.RUnlock()
+
+	names := make([]string, 0, len(animations))
+	for name := range animations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,32 @@
+package animation
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft"
+)
+
+// Seamless is an Animation that transfers sessions without a dimension change. Instead of flushing the world
+// of the client, only the parts of the game data that differ between the two servers are resent, allowing
+// near instant transfers between servers with similar worlds.
+// A dimension change cannot be performed seamlessly, so Play and Clear are only called by the session when
+// the servers are in different dimensions, in which case they delegate to Fallback.
+type Seamless struct {
+	// Fallback is the Animation played when the session cannot be transferred seamlessly. If nil, Dimension
+	// is used.
+	Fallback Animation
+}
+
+func (animation *Seamless) Play(conn *minecraft.Conn, serverGameData minecraft.GameData) {
+	animation.fallback().Play(conn, serverGameData)
+}
+
+func (animation *Seamless) Clear(conn *minecraft.Conn, serverGameData minecraft.GameData) {
+	animation.fallback().Clear(conn, serverGameData)
+}
+
+// fallback returns the Animation played when a seamless transfer is not possible.
+func (animation *Seamless) fallback() Animation {
+	if animation.Fallback == nil {
+		return &Dimension{}
+	}
+	return animation.Fallback
+}
@@ -0,0 +1,55 @@
+package animation
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Title is an Animation that shows a loading title while the session is transferred.
+type Title struct {
+	// Title is the title shown. If empty, "Loading..." is shown.
+	Title string
+	// Subtitle is the subtitle shown below the title.
+	Subtitle string
+	// Base is an Animation played alongside the title, such as Fade. If nil, the dimension of the client is
+	// not changed.
+	Base Animation
+}
+
+func (animation *Title) Play(conn *minecraft.Conn, serverGameData minecraft.GameData) {
+	if animation.Base != nil {
+		animation.Base.Play(conn, serverGameData)
+	}
+
+	title := animation.Title
+	if title == "" {
+		title = "Loading..."
+	}
+	// The title remains until Clear is called, so the remain duration only serves as an upper bound in case
+	// the transfer never completes.
+	sendTitle(conn, packet.TitleActionSetDurations, "", 0, 1200, 10)
+	if animation.Subtitle != "" {
+		sendTitle(conn, packet.TitleActionSetSubtitle, animation.Subtitle, 0, 0, 0)
+	}
+	sendTitle(conn, packet.TitleActionSetTitle, title, 0, 0, 0)
+}
+
+func (animation *Title) Clear(conn *minecraft.Conn, serverGameData minecraft.GameData) {
+	if animation.Base != nil {
+		animation.Base.Clear(conn, serverGameData)
+	}
+	sendTitle(conn, packet.TitleActionClear, "", 0, 0, 0)
+	sendTitle(conn, packet.TitleActionReset, "", 0, 0, 0)
+}
+
+// sendTitle sends a SetTitle packet with the action, text and durations in ticks passed to the client.
+func sendTitle(conn *minecraft.Conn, action int32, text string, fadeIn, remain, fadeOut int32) {
+	_ = conn.WritePacket(&packet.SetTitle{
+		ActionType:      action,
+		Text:            text,
+		FadeInDuration:  fadeIn,
+		RemainDuration:  remain,
+		FadeOutDuration: fadeOut,
+		XUID:            conn.IdentityData().XUID,
+	})
+}
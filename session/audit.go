@@ -0,0 +1,33 @@
+package session
+
+import (
+	"github.com/spectrum-proxy/spectrum/audit"
+	"net"
+	"time"
+)
+
+// audit writes a record of the action passed to the audit sink of the session, if any.
+func (s *Session) audit(action audit.Action, from, to, reason string) {
+	if s.opts.Audit == nil {
+		return
+	}
+
+	identity := s.Client().IdentityData()
+	ip := s.Client().RemoteAddr().String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	err := s.opts.Audit.Write(audit.Record{
+		Time:   time.Now(),
+		Action: action,
+		Name:   identity.DisplayName,
+		XUID:   identity.XUID,
+		IP:     ip,
+		From:   from,
+		To:     to,
+		Reason: reason,
+	})
+	if err != nil {
+		s.logger.Error("Failed to write audit record", "err", err)
+	}
+}
@@ -0,0 +1,109 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// BossBar is a boss bar shown to a client by the proxy.
+type BossBar struct {
+	// Text is the text shown above the boss bar.
+	Text string
+	// Health is the fill of the boss bar, ranging from 0 to 1.
+	Health float32
+	// Colour is the colour of the boss bar, such as packet.BossEventColourPurple.
+	Colour uint32
+}
+
+// ShowBossBar shows a boss bar to the client and returns its ID, which may be used to update or hide the boss
+// bar. Boss bars shown by the proxy are shown again automatically after a transfer.
+func (s *Session) ShowBossBar(bar BossBar) int64 {
+	id := s.nextEntityID()
+
+	s.bossBarsMu.Lock()
+	s.bossBars[id] = bar
+	s.bossBarsMu.Unlock()
+
+	s.sendBossBar(id, bar)
+	return id
+}
+
+// UpdateBossBar updates the boss bar with the ID passed. It is a no-op if no such boss bar is shown.
+func (s *Session) UpdateBossBar(id int64, bar BossBar) {
+	s.bossBarsMu.Lock()
+	_, ok := s.bossBars[id]
+	if ok {
+		s.bossBars[id] = bar
+	}
+	s.bossBarsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	_ = s.Client().WritePacket(&packet.BossEvent{
+		BossEntityUniqueID: id,
+		EventType:          packet.BossEventTitle,
+		BossBarTitle:       bar.Text,
+	})
+	_ = s.Client().WritePacket(&packet.BossEvent{
+		BossEntityUniqueID: id,
+		EventType:          packet.BossEventHealthPercentage,
+		HealthPercentage:   bar.Health,
+	})
+	_ = s.Client().WritePacket(&packet.BossEvent{
+		BossEntityUniqueID: id,
+		EventType:          packet.BossEventAppearanceProperties,
+		Colour:             bar.Colour,
+	})
+}
+
+// HideBossBar hides the boss bar with the ID passed.
+func (s *Session) HideBossBar(id int64) {
+	s.bossBarsMu.Lock()
+	_, ok := s.bossBars[id]
+	delete(s.bossBars, id)
+	s.bossBarsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	_ = s.Client().WritePacket(&packet.BossEvent{
+		BossEntityUniqueID: id,
+		EventType:          packet.BossEventHide,
+	})
+	_ = s.Client().WritePacket(&packet.RemoveActor{EntityUniqueID: id})
+}
+
+// resendBossBars shows all boss bars of the proxy again. It is called after a transfer, during which the
+// client forgets the entities the boss bars are attached to.
+func (s *Session) resendBossBars() {
+	s.bossBarsMu.Lock()
+	defer s.bossBarsMu.Unlock()
+	for id, bar := range s.bossBars {
+		s.sendBossBar(id, bar)
+	}
+}
+
+// sendBossBar spawns the invisible entity the boss bar with the ID passed is attached to and shows the boss
+// bar.
+func (s *Session) sendBossBar(id int64, bar BossBar) {
+	metadata := protocol.NewEntityMetadata()
+	metadata.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagInvisible)
+	metadata.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagNoAI)
+	metadata[protocol.EntityDataKeyScale] = float32(0)
+
+	_ = s.Client().WritePacket(&packet.AddActor{
+		EntityUniqueID:  id,
+		EntityRuntimeID: uint64(id),
+		EntityType:      "minecraft:slime",
+		Position:        s.Position(),
+		EntityMetadata:  metadata,
+	})
+	_ = s.Client().WritePacket(&packet.BossEvent{
+		BossEntityUniqueID: id,
+		EventType:          packet.BossEventShow,
+		BossBarTitle:       bar.Text,
+		HealthPercentage:   bar.Health,
+		Colour:             bar.Colour,
+	})
+}
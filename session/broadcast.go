@@ -0,0 +1,65 @@
+package session
+
+import (
+	"bytes"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"sync"
+)
+
+// broadcastWorkers is the maximum amount of goroutines used to write a broadcast packet to sessions.
+const broadcastWorkers = 16
+
+// broadcastPool is the pool used to copy packets broadcast to sessions. It is only read from.
+var broadcastPool = packet.NewServerPool()
+
+// Broadcast writes the packet passed to the client of every session in the registry.
+func (r *Registry) Broadcast(pk packet.Packet) {
+	r.BroadcastFunc(nil, pk)
+}
+
+// BroadcastFunc writes the packet passed to the client of every session for which filter returns true. If
+// filter is nil, the packet is written to all sessions. The packet is written concurrently using a bounded
+// amount of goroutines, and BroadcastFunc returns once it was written to all sessions. Every session is written
+// a copy of the packet, which is delivered like packets of the server of the session.
+func (r *Registry) BroadcastFunc(filter func(*Session) bool, pk packet.Packet) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, broadcastWorkers)
+	for _, s := range r.Sessions() {
+		if filter != nil && !filter(s) {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(s *Session) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			s.deliverMu.Lock()
+			err := s.writeClientPacket(copyPacket(pk))
+			s.deliverMu.Unlock()
+			if err != nil {
+				s.logger.Debug("Failed to broadcast packet", "err", err)
+			}
+		}(s)
+	}
+	wg.Wait()
+}
+
+// copyPacket returns a copy of the packet passed, as writing a packet to a session may modify it, for example
+// to translate entity IDs. Packets unknown to the pool are returned as is.
+func copyPacket(pk packet.Packet) packet.Packet {
+	f, ok := broadcastPool[pk.ID()]
+	if !ok {
+		return pk
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, 256))
+	pk.Marshal(protocol.NewWriter(buf, 0))
+
+	c := f()
+	c.Marshal(protocol.NewReader(buf, 0, false))
+	return c
+}
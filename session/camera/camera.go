@@ -0,0 +1,110 @@
+package camera
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"image/color"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// PresetFree is the index of the "minecraft:free" preset in DefaultPresets. It allows the camera to be
+	// placed anywhere in the world.
+	PresetFree uint32 = iota
+	// PresetFirstPerson is the index of the "minecraft:first_person" preset in DefaultPresets.
+	PresetFirstPerson
+	// PresetThirdPerson is the index of the "minecraft:third_person" preset in DefaultPresets.
+	PresetThirdPerson
+	// PresetThirdPersonFront is the index of the "minecraft:third_person_front" preset in DefaultPresets.
+	PresetThirdPersonFront
+)
+
+// DefaultPresets holds the vanilla camera presets. Camera sends these to the client before the first
+// instruction that refers to a preset.
+var DefaultPresets = []protocol.CameraPreset{
+	{Name: "minecraft:free"},
+	{Name: "minecraft:first_person"},
+	{Name: "minecraft:third_person"},
+	{Name: "minecraft:third_person_front"},
+}
+
+// Writer is a connection that packets may be written to, such as a *minecraft.Conn.
+type Writer interface {
+	WritePacket(pk packet.Packet) error
+}
+
+// Camera controls the camera of a client. Note that servers may send their own camera presets, in which case
+// the preset indices used by Camera may refer to different presets until SendPresets is called again.
+type Camera struct {
+	w           Writer
+	presetsSent atomic.Bool
+}
+
+// New returns a new Camera writing its instructions to the Writer passed.
+func New(w Writer) *Camera {
+	return &Camera{w: w}
+}
+
+// SendPresets sends DefaultPresets to the client, overwriting any presets sent by the server.
+func (c *Camera) SendPresets() {
+	c.presetsSent.Store(true)
+	_ = c.w.WritePacket(&packet.CameraPresets{Presets: DefaultPresets})
+}
+
+// FadeOut fades the screen of the client to the colour passed over fadeIn, keeps it covered for wait and
+// uncovers it again over fadeOut. Only the red, green and blue components of the colour are used.
+func (c *Camera) FadeOut(colour color.RGBA, fadeIn, wait, fadeOut time.Duration) {
+	_ = c.w.WritePacket(&packet.CameraInstruction{
+		Fade: protocol.Option(protocol.CameraInstructionFade{
+			FadeInDuration:  float32(fadeIn.Seconds()),
+			WaitDuration:    float32(wait.Seconds()),
+			FadeOutDuration: float32(fadeOut.Seconds()),
+			Colour:          colour,
+		}),
+	})
+}
+
+// EaseTo moves the camera of the client to the position passed and rotates it to the pitch and yaw passed,
+// using the easing type, such as protocol.EasingTypeInOutSine, over the duration passed.
+func (c *Camera) EaseTo(pos mgl32.Vec3, pitch, yaw float32, easing uint8, duration time.Duration) {
+	c.set(protocol.CameraInstructionSet{
+		Preset:   PresetFree,
+		Ease:     protocol.Option(protocol.CameraEase{Type: easing, Duration: float32(duration.Seconds())}),
+		Position: protocol.Option(pos),
+		Rotation: protocol.Option(mgl32.Vec2{pitch, yaw}),
+	})
+}
+
+// LookAt places the camera of the client at the position passed, facing the target passed.
+func (c *Camera) LookAt(pos, target mgl32.Vec3) {
+	c.set(protocol.CameraInstructionSet{
+		Preset:   PresetFree,
+		Position: protocol.Option(pos),
+		Facing:   protocol.Option(target),
+	})
+}
+
+// SetPreset switches the camera of the client to the preset with the index passed, such as
+// PresetThirdPerson.
+func (c *Camera) SetPreset(preset uint32) {
+	c.set(protocol.CameraInstructionSet{Preset: preset})
+}
+
+// Reset returns the camera of the client to the player, removing all instructions previously sent.
+func (c *Camera) Reset() {
+	_ = c.w.WritePacket(&packet.CameraInstruction{
+		Clear: protocol.Option(true),
+	})
+}
+
+// set sends a set instruction to the client, sending the presets first if they have not yet been sent.
+func (c *Camera) set(instruction protocol.CameraInstructionSet) {
+	if !c.presetsSent.Load() {
+		c.SendPresets()
+	}
+	_ = c.w.WritePacket(&packet.CameraInstruction{
+		Set: protocol.Option(instruction),
+	})
+}
@@ -0,0 +1,96 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/capture"
+	"io"
+	"time"
+)
+
+// StartCapture starts recording all packets passing through the session in both directions to the io.Writer
+// passed, using the format of the capture package. Packets are recorded as received, before handlers process
+// them. An error is returned if the session is already being captured.
+func (s *Session) StartCapture(w io.Writer) error {
+	cw, err := capture.NewWriter(w, s.Server().ShieldID())
+	if err != nil {
+		return err
+	}
+	if !s.capturer.CompareAndSwap(nil, cw) {
+		return errors.New("session is already being captured")
+	}
+	s.logger.Info("Started packet capture")
+	return nil
+}
+
+// StopCapture stops recording the packets of the session and flushes the capture. Nothing is written to the
+// io.Writer passed to StartCapture after StopCapture returns.
+func (s *Session) StopCapture() error {
+	cw := s.capturer.Swap(nil)
+	if cw == nil {
+		return errors.New("session is not being captured")
+	}
+	s.logger.Info("Stopped packet capture")
+	return cw.Close()
+}
+
+// capturePacket records the packet passed if the session is being captured. The capture is stopped if writing
+// the packet fails.
+func (s *Session) capturePacket(dir capture.Direction, pk packet.Packet) {
+	cw := s.capturer.Load()
+	if cw == nil {
+		return
+	}
+	if err := cw.Write(dir, time.Now(), pk); err != nil {
+		s.logger.Error("Failed to capture packet", "err", err)
+		s.capturer.CompareAndSwap(cw, nil)
+	}
+}
+
+// Replay replays the packets the server sent in the capture read from the io.Reader passed to the client of
+// the session, keeping the time between the packets as recorded. Packets pass through the handlers of the
+// session like packets sent by the server and are delivered in between the packets of the server, so replaying
+// is safe while the session is forwarding packets. Packets sent by the client are not replayed. Replay blocks
+// until all packets were replayed or the context passed is done.
+func (s *Session) Replay(ctx context.Context, r io.Reader) error {
+	cr, err := capture.NewReader(r)
+	if err != nil {
+		return err
+	}
+
+	pool := packet.NewServerPool()
+	var start, first time.Time
+	for {
+		record, err := cr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if record.Direction != capture.Clientbound {
+			continue
+		}
+
+		if first.IsZero() {
+			start, first = time.Now(), record.Time
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.ctx.Done():
+			return ErrSessionClosed
+		case <-time.After(time.Until(start.Add(record.Time.Sub(first)))):
+		}
+
+		conn := s.Server()
+		pk, err := record.Decode(pool, conn.ShieldID())
+		if err != nil {
+			return fmt.Errorf("failed to decode packet: %v", err)
+		}
+		if err := s.deliverServerPackets(conn, s.processServerPacket(pk)); err != nil {
+			return fmt.Errorf("failed to write packet to client: %v", err)
+		}
+	}
+}
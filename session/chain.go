@@ -0,0 +1,184 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/command"
+	"github.com/spectrum-proxy/spectrum/event"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Handler priorities commonly used with Session.Attach. Handlers with a higher priority are called first.
+const (
+	PriorityLowest  = -200
+	PriorityLow     = -100
+	PriorityNormal  = 0
+	PriorityHigh    = 100
+	PriorityHighest = 200
+)
+
+// attachedHandler is a Handler attached to a handlerChain with a priority.
+type attachedHandler struct {
+	h        Handler
+	priority int
+}
+
+// handlerChain is a Handler calling any number of attached handlers in order of their priority. Handlers of
+// cancellable events are no longer called once the event is cancelled. The handlers may be changed while
+// events are handled: events that are already being handled are not affected.
+type handlerChain struct {
+	mu       sync.Mutex
+	handlers atomic.Pointer[[]attachedHandler]
+}
+
+// newHandlerChain returns a new handlerChain without handlers.
+func newHandlerChain() *handlerChain {
+	c := &handlerChain{}
+	c.handlers.Store(&[]attachedHandler{})
+	return c
+}
+
+// attach attaches the Handler passed with the priority passed. Handlers with the same priority are called in
+// the order they were attached.
+func (c *handlerChain) attach(h Handler, priority int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	handlers := slices.Clone(*c.handlers.Load())
+	i, _ := slices.BinarySearchFunc(handlers, priority, func(a attachedHandler, priority int) int {
+		if a.priority >= priority {
+			return -1
+		}
+		return 1
+	})
+	handlers = slices.Insert(handlers, i, attachedHandler{h: h, priority: priority})
+	c.handlers.Store(&handlers)
+}
+
+// detach detaches the Handler passed. It returns false if the handler was not attached.
+func (c *handlerChain) detach(h Handler) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	handlers := *c.handlers.Load()
+	i := slices.IndexFunc(handlers, func(a attachedHandler) bool {
+		return a.h == h
+	})
+	if i == -1 {
+		return false
+	}
+	handlers = slices.Delete(slices.Clone(handlers), i, i+1)
+	c.handlers.Store(&handlers)
+	return true
+}
+
+// set replaces all attached handlers with the Handler passed.
+func (c *handlerChain) set(h Handler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers.Store(&[]attachedHandler{{h: h, priority: PriorityNormal}})
+}
+
+// each calls f for every attached handler in order, until f returns false.
+func (c *handlerChain) each(f func(h Handler) bool) {
+	for _, a := range *c.handlers.Load() {
+		if !f(a.h) {
+			return
+		}
+	}
+}
+
+// HandleServerPacket ...
+func (c *handlerChain) HandleServerPacket(ctx *Context, pk packet.Packet) {
+	c.each(func(h Handler) bool {
+		h.HandleServerPacket(ctx, pk)
+		if legacy, ok := h.(LegacyHandler); ok && !ctx.Cancelled() {
+			legacy.HandleIncoming(ctx.Context, pk)
+		}
+		return !ctx.Cancelled()
+	})
+}
+
+// HandleClientPacket ...
+func (c *handlerChain) HandleClientPacket(ctx *Context, pk packet.Packet) {
+	c.each(func(h Handler) bool {
+		h.HandleClientPacket(ctx, pk)
+		if legacy, ok := h.(LegacyHandler); ok && !ctx.Cancelled() {
+			legacy.HandleOutgoing(ctx.Context, pk)
+		}
+		return !ctx.Cancelled()
+	})
+}
+
+// OnPreTransfer ...
+func (c *handlerChain) OnPreTransfer(ctx *event.Context, addr *string) {
+	c.each(func(h Handler) bool {
+		h.OnPreTransfer(ctx, addr)
+		return !ctx.Cancelled()
+	})
+}
+
+// OnExternalTransfer ...
+func (c *handlerChain) OnExternalTransfer(ctx *event.Context, host *string, port *uint16) {
+	c.each(func(h Handler) bool {
+		h.OnExternalTransfer(ctx, host, port)
+		return !ctx.Cancelled()
+	})
+}
+
+// OnPostTransfer ...
+func (c *handlerChain) OnPostTransfer(from string, to string) {
+	c.each(func(h Handler) bool {
+		h.OnPostTransfer(from, to)
+		return true
+	})
+}
+
+// HandleCommand ...
+func (c *handlerChain) HandleCommand(ctx *event.Context, cmd command.Command, args string) {
+	c.each(func(h Handler) bool {
+		h.HandleCommand(ctx, cmd, args)
+		return !ctx.Cancelled()
+	})
+}
+
+// HandleFloodViolation ...
+func (c *handlerChain) HandleFloodViolation(ctx *event.Context, id uint32, action *FloodAction) {
+	c.each(func(h Handler) bool {
+		h.HandleFloodViolation(ctx, id, action)
+		return !ctx.Cancelled()
+	})
+}
+
+// HandleControlRequest ...
+func (c *handlerChain) HandleControlRequest(ctx *event.Context, action string, payload []byte) {
+	c.each(func(h Handler) bool {
+		h.HandleControlRequest(ctx, action, payload)
+		return !ctx.Cancelled()
+	})
+}
+
+// HandlePrivateMessage ...
+func (c *handlerChain) HandlePrivateMessage(ctx *event.Context, target *Session, message *string) {
+	c.each(func(h Handler) bool {
+		h.HandlePrivateMessage(ctx, target, message)
+		return !ctx.Cancelled()
+	})
+}
+
+// HandleMovementViolation ...
+func (c *handlerChain) HandleMovementViolation(ctx *event.Context, check string, verdict *Verdict) {
+	c.each(func(h Handler) bool {
+		h.HandleMovementViolation(ctx, check, verdict)
+		return !ctx.Cancelled()
+	})
+}
+
+// HandleDialFailure ...
+func (c *handlerChain) HandleDialFailure(ctx *event.Context, addr string, err error, message *string) {
+	c.each(func(h Handler) bool {
+		h.HandleDialFailure(ctx, addr, err, message)
+		return !ctx.Cancelled()
+	})
+}
@@ -0,0 +1,89 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// defaultChatFormat is the format of bridged chat messages used by channels without a format.
+const defaultChatFormat = "[{server}] <{player}> {message}"
+
+// ChatChannel is a channel over which chat messages are bridged between servers. Chat messages sent by
+// players on a server in the channel are shown to players on the other servers in the channel, without the
+// servers themselves having to support it.
+type ChatChannel struct {
+	// Servers holds the names or addresses of the servers in the channel. If empty, the channel contains all
+	// servers.
+	Servers []string `yaml:"servers"`
+	// Format is the format of messages bridged over the channel. The placeholders {server}, {player} and
+	// {message} are replaced with the name of the server the message was sent on, the name of the player that
+	// sent it and the message itself. If empty, "[{server}] <{player}> {message}" is used.
+	Format string `yaml:"format"`
+}
+
+// contains checks if the channel contains the server at the address passed.
+func (c ChatChannel) contains(s *Session, addr string) bool {
+	if len(c.Servers) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(c.Servers, func(name string) bool {
+		return s.resolveServer(name) == addr
+	})
+}
+
+// format formats the message passed, sent by the player of the session passed, for the channel.
+func (c ChatChannel) format(s *Session, message string) string {
+	format := c.Format
+	if format == "" {
+		format = defaultChatFormat
+	}
+	return strings.NewReplacer(
+		"{server}", s.serverName(s.ServerAddr()),
+		"{player}", s.Client().IdentityData().DisplayName,
+		"{message}", message,
+	).Replace(format)
+}
+
+// bridgeChat shows the chat message passed, sent by the client of the session, to the sessions on other
+// servers that share a chat channel with its server, unless they ignore its player. Sessions sharing multiple
+// channels with it receive the message once, formatted by the first channel in alphabetical order.
+func (s *Session) bridgeChat(message string) {
+	if len(s.opts.ChatChannels) == 0 {
+		return
+	}
+	from, xuid := s.ServerAddr(), s.Client().IdentityData().XUID
+
+	names := make([]string, 0, len(s.opts.ChatChannels))
+	for name := range s.opts.ChatChannels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	received := make(map[*Session]struct{})
+	for _, name := range names {
+		channel := s.opts.ChatChannels[name]
+		if !channel.contains(s, from) {
+			continue
+		}
+		pk := &packet.Text{TextType: packet.TextTypeRaw, Message: channel.format(s, message)}
+		s.registry.BroadcastFunc(func(other *Session) bool {
+			addr := other.ServerAddr()
+			if _, ok := received[other]; ok || addr == from || !channel.contains(s, addr) || other.ignores(xuid) {
+				return false
+			}
+			received[other] = struct{}{}
+			return true
+		}, pk)
+	}
+}
+
+// serverName returns the name of the server at the address passed, or the address itself if the server has no
+// name.
+func (s *Session) serverName(addr string) string {
+	if s.opts.Servers == nil {
+		return addr
+	}
+	return s.opts.Servers.Name(addr)
+}
@@ -0,0 +1,109 @@
+package session
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// maxDeviceModelLength is the maximum length of a valid device model.
+const maxDeviceModelLength = 64
+
+// ClientDataPolicy configures how the ClientData of players is filtered before it is forwarded to servers,
+// protecting servers from oversized or malformed skins and from data that players cannot normally send.
+type ClientDataPolicy struct {
+	// StripGeometry replaces custom skin geometry with the default geometry of the arm size of the skin.
+	StripGeometry bool `yaml:"strip_geometry"`
+	// MaxSkinSize is the maximum width and height in pixels of skin images. Larger skins are replaced with a
+	// blank skin. If zero, skins of any size are forwarded.
+	MaxSkinSize int `yaml:"max_skin_size"`
+	// DropCapes removes capes that are not in AllowedCapes, such as capes of third-party clients.
+	DropCapes bool `yaml:"drop_capes"`
+	// AllowedCapes holds the IDs of the capes kept if DropCapes is set.
+	AllowedCapes []string `yaml:"allowed_capes"`
+	// NormalizeDeviceModels clears device models that are too long or hold control characters.
+	NormalizeDeviceModels bool `yaml:"normalize_device_models"`
+	// RejectInvalid rejects players whose ClientData fails Validate instead of only filtering it.
+	RejectInvalid bool `yaml:"reject_invalid"`
+}
+
+// Validate checks the ClientData passed for values that a client cannot normally send, such as skin images
+// that do not match their size or exceed the MaxSkinSize, returning an error describing the first problem
+// found.
+func (p ClientDataPolicy) Validate(data login.ClientData) error {
+	if err := data.Validate(); err != nil {
+		return err
+	}
+	skin, err := base64.StdEncoding.DecodeString(data.SkinData)
+	if err != nil {
+		return fmt.Errorf("invalid skin data: %v", err)
+	}
+	if len(skin) != data.SkinImageWidth*data.SkinImageHeight*4 {
+		return fmt.Errorf("skin data of %v bytes does not match size %vx%v", len(skin), data.SkinImageWidth, data.SkinImageHeight)
+	}
+	if p.MaxSkinSize > 0 && (data.SkinImageWidth > p.MaxSkinSize || data.SkinImageHeight > p.MaxSkinSize) {
+		return fmt.Errorf("skin of size %vx%v exceeds %v pixels", data.SkinImageWidth, data.SkinImageHeight, p.MaxSkinSize)
+	}
+	cape, err := base64.StdEncoding.DecodeString(data.CapeData)
+	if err != nil {
+		return fmt.Errorf("invalid cape data: %v", err)
+	}
+	if len(cape) != data.CapeImageWidth*data.CapeImageHeight*4 {
+		return fmt.Errorf("cape data of %v bytes does not match size %vx%v", len(cape), data.CapeImageWidth, data.CapeImageHeight)
+	}
+	if !validDeviceModel(data.DeviceModel) {
+		return fmt.Errorf("invalid device model %q", data.DeviceModel)
+	}
+	return nil
+}
+
+// Apply returns the ClientData passed filtered according to the policy.
+func (p ClientDataPolicy) Apply(data login.ClientData) login.ClientData {
+	oversized := p.MaxSkinSize > 0 && (data.SkinImageWidth > p.MaxSkinSize || data.SkinImageHeight > p.MaxSkinSize)
+	if oversized {
+		blank := blankSkin()
+		data.SkinID = blank.SkinID
+		data.SkinData = base64.StdEncoding.EncodeToString(blank.SkinData)
+		data.SkinImageWidth, data.SkinImageHeight = int(blank.SkinImageWidth), int(blank.SkinImageHeight)
+		data.AnimatedImageData = nil
+		data.PersonaSkin, data.PersonaPieces, data.PieceTintColours = false, nil, nil
+	}
+	if p.StripGeometry || oversized {
+		geometry := "geometry.humanoid.custom"
+		if data.ArmSize == "slim" {
+			geometry = "geometry.humanoid.customSlim"
+		}
+		data.SkinResourcePatch = base64.StdEncoding.EncodeToString([]byte(`{"geometry":{"default":"` + geometry + `"}}`))
+		data.SkinGeometry = ""
+		data.SkinGeometryVersion = base64.StdEncoding.EncodeToString([]byte("0.0.0"))
+		data.SkinAnimationData = ""
+	}
+	if p.DropCapes && data.CapeData != "" && !slices.Contains(p.AllowedCapes, data.CapeID) {
+		data.CapeID, data.CapeData = "", ""
+		data.CapeImageWidth, data.CapeImageHeight = 0, 0
+		data.CapeOnClassicSkin = false
+	}
+	if p.NormalizeDeviceModels && !validDeviceModel(data.DeviceModel) {
+		data.DeviceModel = ""
+	}
+	return data
+}
+
+// validDeviceModel checks if the device model passed is of a valid length and does not hold control
+// characters.
+func validDeviceModel(model string) bool {
+	return len(model) <= maxDeviceModelLength && !strings.ContainsFunc(model, unicode.IsControl)
+}
+
+// clientData returns the ClientData of the client of the session with the skin set through SetSkin, filtered
+// according to the ClientDataPolicy in the Opts of the session.
+func (s *Session) clientData() login.ClientData {
+	data := s.Client().ClientData()
+	if skin := s.skin.Load(); skin != nil {
+		applySkin(&data, *skin)
+	}
+	return s.opts.ClientDataPolicy.Apply(data)
+}
@@ -0,0 +1,27 @@
+package session
+
+import (
+	"github.com/spectrum-proxy/spectrum/command"
+	"github.com/spectrum-proxy/spectrum/event"
+	"strings"
+)
+
+// handleCommand executes the command line passed if it refers to a command registered on the proxy. It
+// returns true if the command was handled by the proxy, in which case it must not be forwarded to the server.
+func (s *Session) handleCommand(line string) bool {
+	name, args, _ := strings.Cut(strings.TrimPrefix(line, "/"), " ")
+	cmd, ok := command.ByAlias(name)
+	if !ok {
+		return false
+	}
+
+	ctx := event.New()
+	s.handler.HandleCommand(ctx, cmd, args)
+	if ctx.Cancelled() {
+		return true
+	}
+
+	cmd.Execute(s, args)
+	s.logger.Debug("Executed proxy command", "command", cmd.Name())
+	return true
+}
@@ -0,0 +1,42 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/event"
+)
+
+// Context is the context of a packet passing through a Session. Handlers may cancel the context to drop the
+// packet, or replace the packet with any number of other packets that are forwarded in its place.
+type Context struct {
+	*event.Context
+
+	session      *Session
+	replaced     bool
+	replacements []packet.Packet
+}
+
+// newContext returns a new Context for a packet passing through the session passed.
+func newContext(s *Session) *Context {
+	return &Context{Context: event.New(), session: s}
+}
+
+// Session returns the session the packet is passing through.
+func (ctx *Context) Session() *Session {
+	return ctx.session
+}
+
+// Replace replaces the packet handled with the packets passed, which are forwarded in its place in the order
+// they are passed. Calling Replace multiple times only keeps the last replacement.
+func (ctx *Context) Replace(pks ...packet.Packet) {
+	ctx.replaced = true
+	ctx.replacements = pks
+}
+
+// Packets returns the packets that should be forwarded in place of the packet passed, which are either the
+// replacements set through Replace or pk itself.
+func (ctx *Context) Packets(pk packet.Packet) []packet.Packet {
+	if ctx.replaced {
+		return ctx.replacements
+	}
+	return []packet.Packet{pk}
+}
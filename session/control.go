@@ -0,0 +1,162 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/event"
+	"github.com/spectrum-proxy/spectrum/server"
+	packet2 "github.com/spectrum-proxy/spectrum/server/packet"
+	"sync"
+)
+
+// errControlAllPlayers is returned by control actions if they would affect other players than the player of
+// the session the request was sent through, while this is not allowed by the Opts of the session.
+var errControlAllPlayers = errors.New("controlling other players is not allowed")
+
+// ControlFunc executes a control action requested by the server of a session. The payload passed holds the
+// JSON encoded arguments of the action. The value returned is encoded as JSON and sent back to the server.
+type ControlFunc func(s *Session, payload json.RawMessage) (any, error)
+
+var (
+	controlMu sync.RWMutex
+	// controlActions holds all control actions servers may request, keyed by their name.
+	controlActions = map[string]ControlFunc{
+		"transfer":     controlTransfer,
+		"broadcast":    controlBroadcast,
+		"player_count": controlPlayerCount,
+	}
+)
+
+// RegisterControlAction registers a control action that servers may request through a ControlRequest packet.
+// Registering an action with a name that is already taken overwrites the existing action.
+func RegisterControlAction(action string, f ControlFunc) {
+	controlMu.Lock()
+	defer controlMu.Unlock()
+	controlActions[action] = f
+}
+
+// handleControl executes the control action requested by the server passed and writes the result back to it.
+func (s *Session) handleControl(conn *server.Conn, pk *packet2.ControlRequest) {
+	response := &packet2.ControlResponse{RequestID: pk.RequestID}
+	result, err := s.dispatchControl(pk.Action, pk.Payload)
+	if err == nil && result != nil {
+		response.Payload, err = json.Marshal(result)
+	}
+	if err != nil {
+		s.logger.Debug("Failed to execute control request", "action", pk.Action, "err", err)
+		response.Error = err.Error()
+	}
+
+	if err := conn.WritePacket(response); err != nil {
+		s.logger.Error("Failed to write control response", "err", err)
+	}
+}
+
+// dispatchControl looks up the control action with the name passed and executes it.
+func (s *Session) dispatchControl(action string, payload []byte) (any, error) {
+	controlMu.RLock()
+	f, ok := controlActions[action]
+	controlMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+
+	if len(payload) == 0 {
+		payload = []byte("{}")
+	}
+	if !json.Valid(payload) {
+		return nil, errors.New("invalid payload")
+	}
+
+	ctx := event.New()
+	s.handler.HandleControlRequest(ctx, action, payload)
+	if ctx.Cancelled() {
+		return nil, errors.New("request denied")
+	}
+	return f(s, payload)
+}
+
+// controlTransfer transfers a player to another server. If no player is passed, the player of the session
+// that received the request is transferred. Other players may only be transferred if allowed by the Opts of
+// the session.
+func controlTransfer(s *Session, payload json.RawMessage) (any, error) {
+	var req struct {
+		Player    string   `json:"player"`
+		Addr      string   `json:"addr"`
+		Fallbacks []string `json:"fallbacks"`
+	}
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return nil, fmt.Errorf("invalid payload: %v", err)
+	}
+	if req.Addr == "" {
+		return nil, errors.New("missing addr")
+	}
+
+	target := s
+	if req.Player != "" {
+		if target = s.registry.Lookup(req.Player); target == nil {
+			return nil, fmt.Errorf("player %q not found", req.Player)
+		}
+		if target != s && !s.opts.ControlAllPlayers {
+			return nil, errControlAllPlayers
+		}
+	}
+	return nil, target.Transfer(context.Background(), req.Addr, req.Fallbacks...)
+}
+
+// controlBroadcast sends a chat message to all players, or only to the players on a server if one is passed.
+// If messaging other players is not allowed by the Opts of the session, the message is only sent to the player
+// of the session that received the request.
+func controlBroadcast(s *Session, payload json.RawMessage) (any, error) {
+	var req struct {
+		Message string `json:"message"`
+		Server  string `json:"server"`
+	}
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return nil, fmt.Errorf("invalid payload: %v", err)
+	}
+	if req.Message == "" {
+		return nil, errors.New("missing message")
+	}
+	if !s.opts.ControlAllPlayers {
+		if req.Server != "" {
+			return nil, errControlAllPlayers
+		}
+		_ = s.Client().WritePacket(&packet.Text{TextType: packet.TextTypeRaw, Message: req.Message})
+		return nil, nil
+	}
+
+	var filter func(*Session) bool
+	if req.Server != "" {
+		addr := s.resolveServer(req.Server)
+		filter = func(other *Session) bool {
+			return other.ServerAddr() == addr
+		}
+	}
+	s.registry.BroadcastFunc(filter, &packet.Text{TextType: packet.TextTypeRaw, Message: req.Message})
+	return nil, nil
+}
+
+// controlPlayerCount returns the amount of players on the proxy and on every server. If a server is passed,
+// count holds the amount of players on that server only.
+func controlPlayerCount(s *Session, payload json.RawMessage) (any, error) {
+	var req struct {
+		Server string `json:"server"`
+	}
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return nil, fmt.Errorf("invalid payload: %v", err)
+	}
+
+	servers := s.registry.ServerCounts()
+	count := s.registry.Count()
+	if req.Server != "" {
+		count = servers[s.resolveServer(req.Server)]
+	}
+	return struct {
+		Count   int            `json:"count"`
+		Servers map[string]int `json:"servers"`
+	}{Count: count, Servers: servers}, nil
+}
@@ -0,0 +1,230 @@
+package session
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"maps"
+)
+
+// Cosmetic is a visual entity owned by the proxy that is attached to a player, such as a pet, halo or trail.
+// Cosmetics ride the player they are attached to and are shown to the player and all other players on the same
+// server. They are tracked separately from the entities of servers, so that they survive transfers.
+type Cosmetic struct {
+	// EntityType is the type of the entity shown, such as "minecraft:armor_stand".
+	EntityType string
+	// Offset is the offset of the entity from the player it is attached to.
+	Offset mgl32.Vec3
+	// Metadata holds additional metadata of the entity, such as its flags or name tag. It may be nil.
+	Metadata protocol.EntityMetadata
+}
+
+// AttachCosmetic attaches a cosmetic to the player of the session and returns its ID, which may be used to
+// detach the cosmetic. The cosmetic is shown to the player and to all other players on the same server, and
+// follows the player when it is transferred.
+func (s *Session) AttachCosmetic(c Cosmetic) int64 {
+	id := nextCosmeticID()
+
+	s.cosmeticsMu.Lock()
+	s.cosmetics[id] = c
+	s.cosmeticsMu.Unlock()
+
+	for _, viewer := range s.cosmeticViewers() {
+		viewer.showCosmetic(s, id, c)
+	}
+	return id
+}
+
+// DetachCosmetic detaches the cosmetic with the ID passed from the player of the session and removes it for
+// all players it is shown to. It is a no-op if no such cosmetic is attached.
+func (s *Session) DetachCosmetic(id int64) {
+	s.cosmeticsMu.Lock()
+	_, ok := s.cosmetics[id]
+	delete(s.cosmetics, id)
+	s.cosmeticsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	for _, viewer := range s.cosmeticViewers() {
+		_ = viewer.Client().WritePacket(&packet.RemoveActor{EntityUniqueID: id})
+	}
+}
+
+// Cosmetics returns the cosmetics attached to the player of the session, keyed by their ID.
+func (s *Session) Cosmetics() map[int64]Cosmetic {
+	s.cosmeticsMu.Lock()
+	defer s.cosmeticsMu.Unlock()
+	return maps.Clone(s.cosmetics)
+}
+
+// cosmeticViewers returns the sessions that the cosmetics of the session are shown to: the session itself and
+// the sessions on the same server that have the player of the session spawned.
+func (s *Session) cosmeticViewers() []*Session {
+	viewers := []*Session{s}
+	xuid := s.Client().IdentityData().XUID
+	for _, other := range s.registry.SessionsOn(s.ServerAddr()) {
+		if other == s {
+			continue
+		}
+		if _, ok := other.viewedEntity(xuid); ok {
+			viewers = append(viewers, other)
+		}
+	}
+	return viewers
+}
+
+// viewedEntity returns the entity ID the client of the session knows the player with the XUID passed by, if it
+// has the player spawned.
+func (s *Session) viewedEntity(xuid string) (int64, bool) {
+	if xuid == s.Client().IdentityData().XUID {
+		return s.Client().GameData().EntityUniqueID, true
+	}
+	s.cosmeticsMu.Lock()
+	defer s.cosmeticsMu.Unlock()
+	id, ok := s.viewing[xuid]
+	return id, ok
+}
+
+// showCosmetic spawns the cosmetic with the ID passed, attached to the player of the owner session, on the
+// client of the session.
+func (s *Session) showCosmetic(owner *Session, id int64, c Cosmetic) {
+	if pk, ok := s.cosmeticPacket(owner, id, c); ok {
+		_ = s.Client().WritePacket(pk)
+	}
+}
+
+// cosmeticPacket returns the packet spawning the cosmetic with the ID passed, attached to the player of the
+// owner session, on the client of the session. False is returned if the client does not have the player
+// spawned.
+func (s *Session) cosmeticPacket(owner *Session, id int64, c Cosmetic) (*packet.AddActor, bool) {
+	ridden, ok := s.viewedEntity(owner.Client().IdentityData().XUID)
+	if !ok {
+		return nil, false
+	}
+	metadata := protocol.NewEntityMetadata()
+	maps.Copy(metadata, c.Metadata)
+	metadata[protocol.EntityDataKeySeatOffset] = c.Offset
+
+	return &packet.AddActor{
+		EntityUniqueID:  id,
+		EntityRuntimeID: uint64(id),
+		EntityType:      c.EntityType,
+		EntityMetadata:  metadata,
+		EntityLinks: []protocol.EntityLink{{
+			RiddenEntityUniqueID: ridden,
+			RiderEntityUniqueID:  id,
+			Type:                 protocol.EntityLinkPassenger,
+		}},
+	}, true
+}
+
+// handleCosmeticPacket tracks the players spawned on the client of the session from the packet passed, after
+// it was translated, and returns the packets spawning or removing the cosmetics attached to them.
+func (s *Session) handleCosmeticPacket(pk packet.Packet) []packet.Packet {
+	switch pk := pk.(type) {
+	case *packet.AddPlayer:
+		owner := s.sessionByUUID(pk.UUID)
+		if owner == nil || owner == s {
+			return nil
+		}
+		s.cosmeticsMu.Lock()
+		s.viewing[owner.Client().IdentityData().XUID] = pk.AbilityData.EntityUniqueID
+		s.cosmeticsMu.Unlock()
+
+		var pks []packet.Packet
+		for id, c := range owner.Cosmetics() {
+			if pk, ok := s.cosmeticPacket(owner, id, c); ok {
+				pks = append(pks, pk)
+			}
+		}
+		return pks
+	case *packet.RemoveActor:
+		var xuid string
+		s.cosmeticsMu.Lock()
+		for x, id := range s.viewing {
+			if id == pk.EntityUniqueID {
+				xuid = x
+				delete(s.viewing, x)
+				break
+			}
+		}
+		for x, id := range s.hidden {
+			if id == pk.EntityUniqueID {
+				delete(s.hidden, x)
+			}
+		}
+		s.cosmeticsMu.Unlock()
+
+		owner := s.registry.GetSession(xuid)
+		if xuid == "" || owner == nil {
+			return nil
+		}
+		var pks []packet.Packet
+		for id := range owner.Cosmetics() {
+			pks = append(pks, &packet.RemoveActor{EntityUniqueID: id})
+		}
+		return pks
+	}
+	return nil
+}
+
+// sessionByUUID returns the session of the player with the UUID passed, or nil if the player is not on the
+// proxy.
+func (s *Session) sessionByUUID(id uuid.UUID) (session *Session) {
+	s.registry.Range(func(other *Session) bool {
+		if other.Client().IdentityData().Identity == id.String() {
+			session = other
+			return false
+		}
+		return true
+	})
+	return session
+}
+
+// clearCosmetics removes all cosmetics shown on the client of the session, including those attached to its own
+// player if own is true. It is called during a transfer, before the players of the previous server are removed.
+func (s *Session) clearCosmetics(own bool) {
+	s.cosmeticsMu.Lock()
+	viewing := s.viewing
+	s.viewing = make(map[string]int64)
+	clear(s.hidden)
+	s.cosmeticsMu.Unlock()
+
+	for xuid := range viewing {
+		if owner := s.registry.GetSession(xuid); owner != nil {
+			for id := range owner.Cosmetics() {
+				_ = s.Client().WritePacket(&packet.RemoveActor{EntityUniqueID: id})
+			}
+		}
+	}
+	if !own {
+		return
+	}
+	for id := range s.Cosmetics() {
+		_ = s.Client().WritePacket(&packet.RemoveActor{EntityUniqueID: id})
+	}
+}
+
+// resendCosmetics shows the cosmetics attached to the player of the session to its client again. It is called
+// after a transfer. Cosmetics of other players are shown again once the new server spawns them.
+func (s *Session) resendCosmetics() {
+	for id, c := range s.Cosmetics() {
+		s.showCosmetic(s, id, c)
+	}
+}
+
+// hideCosmetics removes the cosmetics attached to the player of the session for all other players it is shown
+// to. It is called when the session is closed.
+func (s *Session) hideCosmetics() {
+	cosmetics := s.Cosmetics()
+	if len(cosmetics) == 0 {
+		return
+	}
+	for _, viewer := range s.cosmeticViewers()[1:] {
+		for id := range cosmetics {
+			_ = viewer.Client().WritePacket(&packet.RemoveActor{EntityUniqueID: id})
+		}
+	}
+}
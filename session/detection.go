@@ -0,0 +1,21 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/detection"
+)
+
+// Risk returns the risk that the client of the session was modified, as assessed from its login data and the
+// first packets it sent. Handlers may use it to restrict or ban players, for example once the score exceeds a
+// threshold.
+func (s *Session) Risk() detection.Risk {
+	return s.detector.Risk()
+}
+
+// inspect passes a packet sent by the client to the detector of the session, logging the signals it raises.
+func (s *Session) inspect(pk packet.Packet) {
+	for _, signal := range s.detector.HandlePacket(pk) {
+		riskSignalsTotal.With(signal.Name).Inc()
+		s.logger.Warn("Client raised risk signal", "signal", signal.Name, "score", s.Risk().Score)
+	}
+}
@@ -0,0 +1,50 @@
+package session
+
+import "encoding/json"
+
+// Diagnostics holds a snapshot of the state of a session, used for debugging.
+type Diagnostics struct {
+	Name    string `json:"name"`
+	XUID    string `json:"xuid"`
+	Server  string `json:"server"`
+	Latency int64  `json:"latency"`
+	Jitter  int64  `json:"jitter"`
+	// Transferring specifies if a transfer of the session is currently pending.
+	Transferring bool `json:"transferring"`
+	// IncomingQueue and OutgoingQueue are the amount of packets pending delivery to the client and server
+	// respectively. They are always zero if packets are not processed concurrently.
+	IncomingQueue int `json:"incoming_queue"`
+	OutgoingQueue int `json:"outgoing_queue"`
+	// Store holds the values attached to the session through its Store. Values that cannot be encoded as JSON
+	// are left out.
+	Store map[string]any `json:"store,omitempty"`
+}
+
+// Diagnostics returns a snapshot of the current state of the session.
+func (s *Session) Diagnostics() Diagnostics {
+	identity := s.Client().IdentityData()
+	d := Diagnostics{
+		Name:         identity.DisplayName,
+		XUID:         identity.XUID,
+		Server:       s.ServerAddr(),
+		Latency:      s.Latency(),
+		Jitter:       s.Jitter(),
+		Transferring: s.transferring.Load(),
+	}
+	for key, value := range s.store.All() {
+		if _, err := json.Marshal(value); err != nil {
+			continue
+		}
+		if d.Store == nil {
+			d.Store = make(map[string]any)
+		}
+		d.Store[key] = value
+	}
+	if p := s.incoming.Load(); p != nil {
+		d.IncomingQueue = p.pending()
+	}
+	if p := s.outgoing.Load(); p != nil {
+		d.OutgoingQueue = p.pending()
+	}
+	return d
+}
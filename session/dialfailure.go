@@ -0,0 +1,64 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"github.com/spectrum-proxy/spectrum/event"
+	"net"
+	"syscall"
+)
+
+// defaultDialFailureMessage is the message clients are disconnected with if the server they join cannot be
+// reached and no DialFailureMessage is set.
+const defaultDialFailureMessage = "Could not connect to the server (%v), please try again later."
+
+// DialFailure is the category of the reason connecting to a server failed.
+type DialFailure string
+
+const (
+	// DialFailureTimeout is the category of dials that did not complete in time.
+	DialFailureTimeout DialFailure = "timeout"
+	// DialFailureRefused is the category of dials to servers that are not accepting connections, including
+	// servers of which the circuit is open.
+	DialFailureRefused DialFailure = "refused"
+	// DialFailureFull is the category of dials to servers that are at their capacity.
+	DialFailureFull DialFailure = "full"
+	// DialFailureError is the category of all other failed dials.
+	DialFailureError DialFailure = "error"
+)
+
+// ClassifyDialError returns the category of the error passed, as returned when connecting to a server.
+func ClassifyDialError(err error) DialFailure {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrBackendFull):
+		return DialFailureFull
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return DialFailureTimeout
+	case errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, ErrBackendUnreachable):
+		return DialFailureRefused
+	}
+	return DialFailureError
+}
+
+// failDial disconnects the client of the session after it failed to connect to the server at the address
+// passed when joining, with a message holding the category of the error passed.
+func (s *Session) failDial(addr string, err error) {
+	category := ClassifyDialError(err)
+	dialFailuresTotal.With(string(category)).Inc()
+	s.logger.Error("Failed to dial server", "server", addr, "category", category, "err", err)
+
+	template := s.opts.DialFailureMessage
+	if template == "" {
+		template = defaultDialFailureMessage
+	}
+	message := s.Translate(template, category)
+
+	ctx := event.New()
+	s.handler.HandleDialFailure(ctx, addr, err, &message)
+	if ctx.Cancelled() {
+		s.Close()
+		return
+	}
+	s.Disconnect(message)
+}
@@ -0,0 +1,25 @@
+package session
+
+import "sync/atomic"
+
+// proxyEntityOffset is the first entity ID used for entities owned by the proxy. It lies far outside the range
+// of IDs assigned by servers, so that entities of the proxy never collide with those of a server.
+const proxyEntityOffset = int64(1) << 62
+
+// nextEntityID allocates a new entity ID for an entity owned by the proxy. The ID may be used both as unique
+// and as runtime ID of the entity.
+func (s *Session) nextEntityID() int64 {
+	return proxyEntityOffset + s.entityIDs.Add(1)
+}
+
+// cosmeticEntityOffset is the first entity ID used for cosmetics. Cosmetics are shown to the clients of several
+// sessions, so their IDs are allocated globally, in a range that the IDs allocated by nextEntityID never reach.
+const cosmeticEntityOffset = proxyEntityOffset + int64(1)<<60
+
+// cosmeticIDs is the counter of the entity IDs allocated to cosmetics.
+var cosmeticIDs atomic.Int64
+
+// nextCosmeticID allocates a new entity ID for a cosmetic, unique across all sessions.
+func nextCosmeticID() int64 {
+	return cosmeticEntityOffset + cosmeticIDs.Add(1)
+}
@@ -0,0 +1,31 @@
+package session
+
+import (
+	"errors"
+	"github.com/spectrum-proxy/spectrum/server"
+)
+
+var (
+	// ErrAlreadyTransferring is returned by Session.Transfer if another transfer of the session is pending.
+	ErrAlreadyTransferring = errors.New("already transferring")
+	// ErrBackendUnreachable is returned by Session.Transfer and Session.Dial if no connection to the server
+	// could be opened, for example because it is down or its circuit is open.
+	ErrBackendUnreachable = server.ErrUnreachable
+	// ErrBackendFull is returned by Session.Transfer if the server transferred to is at the capacity configured
+	// in the Opts of the session.
+	ErrBackendFull = errors.New("server is full")
+	// ErrHandshakeFailed is returned by Session.Transfer and Session.Dial if a connection to the server was
+	// opened, but logging in to it failed.
+	ErrHandshakeFailed = server.ErrHandshakeFailed
+	// ErrTransferCancelled is returned by Session.Transfer if the transfer was cancelled by a Handler, through
+	// Session.CancelTransfer or because the session was closed.
+	ErrTransferCancelled = errors.New("transfer cancelled")
+	// ErrSessionClosed is returned by methods of a Session that cannot complete because the session was
+	// closed.
+	ErrSessionClosed = errors.New("session closed")
+
+	// ErrServerFull is returned by Session.Transfer if the server transferred to is full.
+	//
+	// Deprecated: Use ErrBackendFull instead.
+	ErrServerFull = ErrBackendFull
+)
@@ -0,0 +1,65 @@
+package session
+
+// Event is an event in the lifecycle of a Session, published on the event bus passed in the Opts of the
+// session. It is one of SessionStart, TransferStart, TransferEnd or SessionClose.
+type Event interface {
+	// Session returns the session the event occurred for.
+	Session() *Session
+}
+
+// SessionStart is published once the session is connected to its first server and packets are forwarded.
+type SessionStart struct {
+	s *Session
+}
+
+// Session ...
+func (e SessionStart) Session() *Session {
+	return e.s
+}
+
+// TransferStart is published when the session starts transferring to another server.
+type TransferStart struct {
+	s *Session
+	// From is the address of the server the session is transferring from.
+	From string
+	// Targets holds the addresses or names of the servers the session attempts to transfer to, in order.
+	Targets []string
+}
+
+// Session ...
+func (e TransferStart) Session() *Session {
+	return e.s
+}
+
+// TransferEnd is published when a transfer of the session has ended, whether it succeeded or not.
+type TransferEnd struct {
+	s *Session
+	// From is the address of the server the session transferred from.
+	From string
+	// To is the address of the server the session is connected to after the transfer.
+	To string
+	// Err is the error the transfer failed with, or nil if it succeeded.
+	Err error
+}
+
+// Session ...
+func (e TransferEnd) Session() *Session {
+	return e.s
+}
+
+// SessionClose is published when the session is closed.
+type SessionClose struct {
+	s *Session
+}
+
+// Session ...
+func (e SessionClose) Session() *Session {
+	return e.s
+}
+
+// publish publishes the event passed on the event bus of the session, if any.
+func (s *Session) publish(e Event) {
+	if s.opts.Events != nil {
+		s.opts.Events.Publish(e)
+	}
+}
@@ -0,0 +1,36 @@
+package session
+
+import (
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/event"
+	"net"
+	"strconv"
+)
+
+// TransferExternal moves the client to a server or proxy outside of the proxy at the public host and port
+// passed, using the Transfer packet of the game. The client leaves the proxy, so the session is closed once
+// the packet was sent.
+func (s *Session) TransferExternal(host string, port uint16) error {
+	if s.closed.Load() {
+		return ErrSessionClosed
+	}
+	if !s.transferring.CompareAndSwap(false, true) {
+		return ErrAlreadyTransferring
+	}
+	defer s.transferring.Store(false)
+
+	ctx := event.New()
+	s.handler.OnExternalTransfer(ctx, &host, &port)
+	if ctx.Cancelled() {
+		return ErrTransferCancelled
+	}
+
+	if err := s.Client().WritePacket(&packet.Transfer{Address: host, Port: port}); err != nil {
+		return fmt.Errorf("failed to write transfer packet: %v", err)
+	}
+	externalTransfersTotal.Inc()
+	s.logger.Info("Transferred session to external address", "target", net.JoinHostPort(host, strconv.Itoa(int(port))))
+	s.Close()
+	return nil
+}
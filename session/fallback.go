@@ -0,0 +1,30 @@
+package session
+
+// FallbackResolver resolves the address of the server a Session is moved to when the connection to its
+// current server is lost unexpectedly.
+type FallbackResolver interface {
+	// Resolve returns the address of the fallback server for the session passed. If an error is returned,
+	// the session is closed instead.
+	Resolve(s *Session) (string, error)
+}
+
+// HealthChecker reports whether servers are reachable.
+type HealthChecker interface {
+	// Healthy returns true if the server at the address passed is considered reachable.
+	Healthy(addr string) bool
+}
+
+// StaticFallbackResolver is a FallbackResolver that always resolves to the same address.
+type StaticFallbackResolver struct {
+	addr string
+}
+
+// NewStaticFallbackResolver returns a StaticFallbackResolver resolving to the address passed.
+func NewStaticFallbackResolver(addr string) *StaticFallbackResolver {
+	return &StaticFallbackResolver{addr: addr}
+}
+
+// Resolve ...
+func (r *StaticFallbackResolver) Resolve(*Session) (string, error) {
+	return r.addr, nil
+}
@@ -0,0 +1,122 @@
+package session
+
+import (
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/event"
+	"github.com/spectrum-proxy/spectrum/internal"
+	"strings"
+	"time"
+)
+
+// FloodAction is the action taken when a client exceeds the FloodLimit of a packet.
+type FloodAction int
+
+const (
+	// FloodActionDrop drops packets exceeding the limit.
+	FloodActionDrop FloodAction = iota
+	// FloodActionThrottle delays reading further packets from the client until the limit allows the packet
+	// again.
+	FloodActionThrottle
+	// FloodActionDisconnect disconnects the client.
+	FloodActionDisconnect
+)
+
+// String returns the name of the action, such as "drop".
+func (a FloodAction) String() string {
+	switch a {
+	case FloodActionDrop:
+		return "drop"
+	case FloodActionThrottle:
+		return "throttle"
+	case FloodActionDisconnect:
+		return "disconnect"
+	}
+	return fmt.Sprintf("FloodAction(%d)", int(a))
+}
+
+// UnmarshalText decodes an action from its name, so that actions may be specified by name in config files.
+func (a *FloodAction) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "drop":
+		*a = FloodActionDrop
+	case "throttle":
+		*a = FloodActionThrottle
+	case "disconnect":
+		*a = FloodActionDisconnect
+	default:
+		return fmt.Errorf("unknown flood action %q", text)
+	}
+	return nil
+}
+
+// FloodLimit limits the rate at which a client may send a packet.
+type FloodLimit struct {
+	// Rate is the amount of packets allowed per second.
+	Rate float64 `yaml:"rate"`
+	// Burst is the amount of packets allowed in quick succession before Rate applies.
+	Burst int `yaml:"burst"`
+	// Action is the action taken when the limit is exceeded.
+	Action FloodAction `yaml:"action"`
+}
+
+// floodLimiter enforces the flood limits of a session on the packets sent by its client. It is only used by
+// the goroutine reading packets from the client.
+type floodLimiter struct {
+	limits  map[uint32]FloodLimit
+	buckets map[uint32]*internal.TokenBucket
+}
+
+// newFloodLimiter returns a floodLimiter enforcing the limits passed, keyed by packet ID, or nil if there are
+// no limits.
+func newFloodLimiter(limits map[uint32]FloodLimit) *floodLimiter {
+	if len(limits) == 0 {
+		return nil
+	}
+	return &floodLimiter{limits: limits, buckets: make(map[uint32]*internal.TokenBucket)}
+}
+
+// handleFlood checks the packet passed against the flood limits of the session. It returns false if the
+// packet must be dropped. The session is closed if the client is disconnected.
+func (s *Session) handleFlood(pk packet.Packet) bool {
+	l := s.flood
+	if l == nil {
+		return true
+	}
+
+	id := pk.ID()
+	limit, ok := l.limits[id]
+	if !ok {
+		return true
+	}
+	bucket, ok := l.buckets[id]
+	if !ok {
+		bucket = internal.NewTokenBucket(limit.Rate, max(limit.Burst, 1))
+		l.buckets[id] = bucket
+	}
+	if bucket.Allow() {
+		return true
+	}
+
+	action := limit.Action
+	ctx := event.New()
+	s.handler.HandleFloodViolation(ctx, id, &action)
+	if ctx.Cancelled() {
+		return true
+	}
+
+	switch action {
+	case FloodActionThrottle:
+		for !bucket.Allow() {
+			if s.closed.Load() {
+				return false
+			}
+			time.Sleep(time.Second / 20)
+		}
+		return true
+	case FloodActionDisconnect:
+		s.logger.Info("Disconnecting session for sending too many packets", "id", id)
+		s.Disconnect("You are sending too many packets.")
+	}
+	return false
+}
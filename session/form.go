@@ -0,0 +1,55 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// formIDOffset is the first form ID in the range reserved for forms sent by the proxy. Responses to forms with
+// an ID in this range are handled by the proxy rather than being forwarded to the server.
+const formIDOffset = 0xffff0000
+
+// FormCallback is called with the response of the client to a form sent using Session.SendForm. The response
+// holds the JSON encoded response data, or is nil if the client closed the form without responding.
+type FormCallback func(response []byte)
+
+// SendForm sends a form to the client of the session. The form is encoded to JSON, so it may either be a
+// value marshaling to a valid form or a json.RawMessage holding one. The callback passed is called once the
+// client responds to the form. Forms sent by the proxy survive transfers.
+func (s *Session) SendForm(form any, callback FormCallback) error {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return fmt.Errorf("encode form: %w", err)
+	}
+
+	s.formsMu.Lock()
+	id := formIDOffset + s.nextFormID
+	s.nextFormID = (s.nextFormID + 1) & 0xffff
+	s.forms[id] = callback
+	s.formsMu.Unlock()
+
+	return s.Client().WritePacket(&packet.ModalFormRequest{
+		FormID:   id,
+		FormData: data,
+	})
+}
+
+// handleFormResponse handles a ModalFormResponse sent by the client. It returns true if the response was to a
+// form sent by the proxy, in which case it must not be forwarded to the server.
+func (s *Session) handleFormResponse(pk *packet.ModalFormResponse) bool {
+	if pk.FormID < formIDOffset {
+		return false
+	}
+
+	s.formsMu.Lock()
+	callback, ok := s.forms[pk.FormID]
+	delete(s.forms, pk.FormID)
+	s.formsMu.Unlock()
+
+	if ok && callback != nil {
+		data, _ := pk.ResponseData.Value()
+		callback(data)
+	}
+	return true
+}
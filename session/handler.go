@@ -2,17 +2,76 @@ package session
 
 import (
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/command"
 	"github.com/spectrum-proxy/spectrum/event"
 )
 
 type Handler interface {
-	// HandleIncoming handle incoming packets to the session
+	// HandleServerPacket handles a packet sent by the server before it is forwarded to the client. The packet
+	// may be dropped by cancelling ctx, modified in place, or replaced by one or more packets through
+	// ctx.Replace.
+	HandleServerPacket(ctx *Context, pk packet.Packet)
+	// HandleClientPacket handles a packet sent by the client before it is forwarded to the server. The packet
+	// may be dropped by cancelling ctx, modified in place, or replaced by one or more packets through
+	// ctx.Replace.
+	HandleClientPacket(ctx *Context, pk packet.Packet)
+	// OnPreTransfer is called before the session is transferred to addr, and again for every fallback server
+	// tried if the transfer fails. The transfer may be cancelled through ctx, or redirected to another server by
+	// changing the address addr points to. Cancelling the transfer to a fallback server skips it.
+	OnPreTransfer(ctx *event.Context, addr *string)
+	// OnExternalTransfer is called before the session is transferred to an external address through
+	// Session.TransferExternal. The transfer may be cancelled through ctx, or redirected by changing the host
+	// and port.
+	OnExternalTransfer(ctx *event.Context, host *string, port *uint16)
+	// OnPostTransfer is called after the session was successfully transferred from one server to another.
+	OnPostTransfer(from string, to string)
+	// HandleCommand is called before a command registered on the proxy is executed by the session. Cancelling
+	// ctx prevents the command from being executed, which may be used to implement permission checks.
+	HandleCommand(ctx *event.Context, cmd command.Command, args string)
+	// HandleFloodViolation is called when the client exceeds the FloodLimit of the packet with the ID passed.
+	// The action taken may be changed through the pointer passed, or the violation may be ignored entirely by
+	// cancelling ctx.
+	HandleFloodViolation(ctx *event.Context, id uint32, action *FloodAction)
+	// HandleControlRequest is called when the server of the session requests the control action passed
+	// through a ControlRequest packet. Cancelling ctx denies the request.
+	HandleControlRequest(ctx *event.Context, action string, payload []byte)
+	// HandlePrivateMessage is called before the session sends a private message to the target session passed.
+	// The message may be changed through the pointer passed, or the message may be blocked by cancelling
+	// ctx.
+	HandlePrivateMessage(ctx *event.Context, target *Session, message *string)
+	// HandleMovementViolation is called when the MovementAnalyzer performing the check passed flags the
+	// player. The action taken may be changed through the pointer passed, or the violation may be ignored
+	// entirely by cancelling ctx.
+	HandleMovementViolation(ctx *event.Context, check string, verdict *Verdict)
+	// HandleDialFailure is called when the session could not connect to the server at the address passed when
+	// joining the proxy. The message the client is disconnected with may be changed through the pointer
+	// passed, or the client may be disconnected without a message by cancelling ctx.
+	HandleDialFailure(ctx *event.Context, addr string, err error, message *string)
+}
+
+// LegacyHandler holds the packet handling methods of Handler before they were replaced by HandleServerPacket
+// and HandleClientPacket. If a Handler attached to a session also implements LegacyHandler, its methods are
+// called after the corresponding methods of Handler, unless the packet was dropped. Cancelling ctx drops the
+// packet.
+//
+// Deprecated: Implement HandleServerPacket and HandleClientPacket instead.
+type LegacyHandler interface {
+	// HandleIncoming handles a packet sent by the server before it is forwarded to the client.
 	HandleIncoming(ctx *event.Context, pk packet.Packet)
-	// HandleOutgoing handle outgoing packets from the session
+	// HandleOutgoing handles a packet sent by the client before it is forwarded to the server.
 	HandleOutgoing(ctx *event.Context, pk packet.Packet)
 }
 
 type NoopHandler struct{}
 
-func (NoopHandler) HandleIncoming(*event.Context, packet.Packet) {}
-func (NoopHandler) HandleOutgoing(*event.Context, packet.Packet) {}
+func (NoopHandler) HandleServerPacket(*Context, packet.Packet)                {}
+func (NoopHandler) HandleClientPacket(*Context, packet.Packet)                {}
+func (NoopHandler) OnPreTransfer(*event.Context, *string)                     {}
+func (NoopHandler) OnExternalTransfer(*event.Context, *string, *uint16)       {}
+func (NoopHandler) OnPostTransfer(string, string)                             {}
+func (NoopHandler) HandleCommand(*event.Context, command.Command, string)     {}
+func (NoopHandler) HandleFloodViolation(*event.Context, uint32, *FloodAction) {}
+func (NoopHandler) HandleControlRequest(*event.Context, string, []byte)       {}
+func (NoopHandler) HandlePrivateMessage(*event.Context, *Session, *string)    {}
+func (NoopHandler) HandleMovementViolation(*event.Context, string, *Verdict)  {}
+func (NoopHandler) HandleDialFailure(*event.Context, string, error, *string)  {}
@@ -0,0 +1,131 @@
+package session
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"slices"
+)
+
+// hologramLineSpacing is the vertical distance in blocks between the lines of a hologram.
+const hologramLineSpacing = 0.3
+
+// Hologram is floating text shown to a client by the proxy. Holograms are shown again automatically after a
+// transfer.
+type Hologram struct {
+	// Lines holds the lines of text shown, from top to bottom.
+	Lines []string
+	// Position is the position of the top line of the hologram.
+	Position mgl32.Vec3
+}
+
+// shownHologram is a Hologram shown to the client along with the IDs of the entities of its lines.
+type shownHologram struct {
+	Hologram
+	entities []int64
+}
+
+// ShowHologram shows a hologram to the client and returns its ID, which may be used to update or hide it.
+func (s *Session) ShowHologram(h Hologram) int64 {
+	id := s.nextEntityID()
+	h.Lines = slices.Clone(h.Lines)
+
+	s.hologramsMu.Lock()
+	defer s.hologramsMu.Unlock()
+
+	shown := &shownHologram{Hologram: h}
+	s.holograms[id] = shown
+	s.sendHologram(shown)
+	return id
+}
+
+// UpdateHologram replaces the lines of the hologram with the ID passed. Lines that changed are updated in
+// place, while lines are added or removed if the amount of lines changed. It is a no-op if no such hologram is
+// shown.
+func (s *Session) UpdateHologram(id int64, lines []string) {
+	s.hologramsMu.Lock()
+	defer s.hologramsMu.Unlock()
+
+	shown, ok := s.holograms[id]
+	if !ok {
+		return
+	}
+	for i, line := range lines {
+		if i >= len(shown.entities) {
+			shown.entities = append(shown.entities, s.nextEntityID())
+			s.sendHologramLine(shown.entities[i], line, shown.linePosition(i))
+			continue
+		}
+		if line != shown.Lines[i] {
+			metadata := protocol.NewEntityMetadata()
+			metadata[protocol.EntityDataKeyName] = line
+			_ = s.Client().WritePacket(&packet.SetActorData{EntityRuntimeID: uint64(shown.entities[i]), EntityMetadata: metadata})
+		}
+	}
+	for _, entity := range shown.entities[min(len(lines), len(shown.entities)):] {
+		_ = s.Client().WritePacket(&packet.RemoveActor{EntityUniqueID: entity})
+	}
+	shown.entities = shown.entities[:len(lines)]
+	shown.Lines = slices.Clone(lines)
+}
+
+// HideHologram hides the hologram with the ID passed.
+func (s *Session) HideHologram(id int64) {
+	s.hologramsMu.Lock()
+	shown, ok := s.holograms[id]
+	delete(s.holograms, id)
+	s.hologramsMu.Unlock()
+	if !ok {
+		return
+	}
+	for _, entity := range shown.entities {
+		_ = s.Client().WritePacket(&packet.RemoveActor{EntityUniqueID: entity})
+	}
+}
+
+// resendHolograms shows all holograms of the proxy again. It is called after a transfer, during which the
+// client forgets the entities of the holograms.
+func (s *Session) resendHolograms() {
+	s.hologramsMu.Lock()
+	defer s.hologramsMu.Unlock()
+	for _, shown := range s.holograms {
+		s.sendHologram(shown)
+	}
+}
+
+// sendHologram spawns an entity for every line of the hologram passed, allocating IDs for lines that do not yet
+// have an entity. sendHologram must be called with hologramsMu held.
+func (s *Session) sendHologram(shown *shownHologram) {
+	for i, line := range shown.Lines {
+		if i >= len(shown.entities) {
+			shown.entities = append(shown.entities, s.nextEntityID())
+		}
+		s.sendHologramLine(shown.entities[i], line, shown.linePosition(i))
+	}
+}
+
+// sendHologramLine spawns the invisible entity showing a line of a hologram as its name tag.
+func (s *Session) sendHologramLine(id int64, line string, pos mgl32.Vec3) {
+	metadata := protocol.NewEntityMetadata()
+	metadata.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagInvisible)
+	metadata.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagNoAI)
+	metadata.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagShowName)
+	metadata.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagAlwaysShowName)
+	metadata[protocol.EntityDataKeyName] = line
+	metadata[protocol.EntityDataKeyAlwaysShowNameTag] = uint8(1)
+	metadata[protocol.EntityDataKeyWidth] = float32(0)
+	metadata[protocol.EntityDataKeyHeight] = float32(0)
+
+	_ = s.Client().WritePacket(&packet.AddActor{
+		EntityUniqueID:  id,
+		EntityRuntimeID: uint64(id),
+		EntityType:      "minecraft:armor_stand",
+		Position:        pos,
+		EntityMetadata:  metadata,
+	})
+}
+
+// linePosition returns the position of the line of the hologram with the index passed.
+func (h *shownHologram) linePosition(i int) mgl32.Vec3 {
+	return h.Position.Sub(mgl32.Vec3{0, float32(i) * hologramLineSpacing})
+}
@@ -0,0 +1,154 @@
+package session
+
+import (
+	"slices"
+	"sync"
+)
+
+// Join is the state of a session joining the proxy, which is passed through the steps of its JoinPipeline
+// before the game of the client is started.
+type Join struct {
+	// Session is the session joining. Its client is still on the loading screen, so nothing but a disconnect
+	// may be sent to it.
+	Session *Session
+	// Server is the name or address of the server the session joins. Steps may change it to select another
+	// server.
+	Server string
+
+	started []func(s *Session)
+}
+
+// OnStart registers a function called once the game of the client was started and the session joined its
+// server, for example to show the player a form.
+func (j *Join) OnStart(f func(s *Session)) {
+	j.started = append(j.started, f)
+}
+
+// JoinStep is a single step of a JoinPipeline, such as a ban check or the selection of the server the session
+// joins.
+type JoinStep interface {
+	// Name returns the name of the step, by which it may be replaced or removed in a JoinPipeline.
+	Name() string
+	// Join runs the step for the session joining. A Rejection is returned to disconnect the client with a
+	// message, while any other error closes the session without one.
+	Join(j *Join) error
+}
+
+// NewJoinStep returns a JoinStep with the name passed that calls the function passed.
+func NewJoinStep(name string, f func(j *Join) error) JoinStep {
+	return joinStep{name: name, f: f}
+}
+
+// joinStep is a JoinStep calling a function.
+type joinStep struct {
+	name string
+	f    func(j *Join) error
+}
+
+// Name ...
+func (s joinStep) Name() string {
+	return s.name
+}
+
+// Join ...
+func (s joinStep) Join(j *Join) error {
+	return s.f(j)
+}
+
+// Rejection is returned by a JoinStep to reject the session joining, disconnecting its client with the
+// message of the Rejection.
+type Rejection struct {
+	// Message is the message shown to the player on the disconnect screen. It may be a translation key.
+	Message string
+}
+
+// Error ...
+func (r Rejection) Error() string {
+	return "join rejected: " + r.Message
+}
+
+// JoinPipeline holds the ordered steps that every session passes through when it joins the proxy, before the
+// game of its client is started. Steps may be added, replaced and removed at any time, affecting sessions
+// joining afterwards. A JoinPipeline is safe for concurrent use.
+type JoinPipeline struct {
+	mu    sync.RWMutex
+	steps []JoinStep
+}
+
+// NewJoinPipeline returns a JoinPipeline running the steps passed in order.
+func NewJoinPipeline(steps ...JoinStep) *JoinPipeline {
+	return &JoinPipeline{steps: steps}
+}
+
+// DefaultJoinPipeline returns a JoinPipeline checking the resource packs of the client against the server it
+// joins and resolving the name of the server through the server registry of the session.
+func DefaultJoinPipeline() *JoinPipeline {
+	return NewJoinPipeline(ServerSelection{}, ResourcePackPolicy{})
+}
+
+// Steps returns the steps of the pipeline in the order they are run.
+func (p *JoinPipeline) Steps() []JoinStep {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Clone(p.steps)
+}
+
+// Add adds a step to the end of the pipeline.
+func (p *JoinPipeline) Add(step JoinStep) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.steps = append(p.steps, step)
+}
+
+// Insert inserts a step before the step with the name passed. If no such step exists, the step is added to the
+// end of the pipeline.
+func (p *JoinPipeline) Insert(before string, step JoinStep) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i := p.index(before)
+	if i == -1 {
+		i = len(p.steps)
+	}
+	p.steps = slices.Insert(p.steps, i, step)
+}
+
+// Replace replaces the step with the same name as the step passed. It returns false if no such step exists.
+func (p *JoinPipeline) Replace(step JoinStep) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i := p.index(step.Name())
+	if i == -1 {
+		return false
+	}
+	p.steps[i] = step
+	return true
+}
+
+// Remove removes the step with the name passed. It returns false if no such step exists.
+func (p *JoinPipeline) Remove(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i := p.index(name)
+	if i == -1 {
+		return false
+	}
+	p.steps = slices.Delete(p.steps, i, i+1)
+	return true
+}
+
+// index returns the index of the step with the name passed, or -1 if no such step exists. index must be
+// called with mu held.
+func (p *JoinPipeline) index(name string) int {
+	return slices.IndexFunc(p.steps, func(step JoinStep) bool { return step.Name() == name })
+}
+
+// run runs the steps of the pipeline for the join passed, stopping at the first step returning an error.
+func (p *JoinPipeline) run(j *Join) error {
+	for _, step := range p.Steps() {
+		if err := step.Join(j); err != nil {
+			joinRejectionsTotal.With(step.Name()).Inc()
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package session
+
+import "strings"
+
+// ServerSelection is the JoinStep selecting the server a session joins.
+type ServerSelection struct {
+	// Select returns the name or address of the server the session joining joins. If nil, the server passed
+	// to NewSession is joined. Names are resolved through the server registry of the session either way.
+	Select func(j *Join) (string, error)
+}
+
+// Name ...
+func (ServerSelection) Name() string {
+	return "server"
+}
+
+// Join ...
+func (sel ServerSelection) Join(j *Join) error {
+	if sel.Select != nil {
+		server, err := sel.Select(j)
+		if err != nil {
+			return err
+		}
+		j.Server = server
+	}
+	j.Server = j.Session.resolveServer(j.Server)
+	return nil
+}
+
+// ResourcePackPolicy is the JoinStep rejecting sessions whose client did not receive the resource packs
+// required by the server it joins, as configured through the ResourcePacks in the Opts of the session.
+type ResourcePackPolicy struct {
+	// Message is the message shown to players that are rejected. If empty, a default message is used.
+	Message string
+}
+
+// Name ...
+func (ResourcePackPolicy) Name() string {
+	return "resource_packs"
+}
+
+// Join ...
+func (p ResourcePackPolicy) Join(j *Join) error {
+	if err := j.Session.checkResourcePacks(j.Server, j.Session.resolveServer(j.Server)); err != nil {
+		j.Session.logger.Warn("Rejected session", "err", err)
+		if p.Message == "" {
+			return Rejection{Message: "You are missing resource packs required by this server."}
+		}
+		return Rejection{Message: p.Message}
+	}
+	return nil
+}
+
+// Rules is a JoinStep showing players a form with the rules of the proxy once they joined, disconnecting them
+// if they decline.
+type Rules struct {
+	// Title and Content are the title and text of the form.
+	Title, Content string
+	// Accepted reports if the player with the XUID passed accepted the rules before, in which case the form is
+	// not shown. If nil, the form is shown on every join.
+	Accepted func(xuid string) bool
+	// OnAccept is called when the player with the XUID passed accepts the rules, for example to remember that
+	// they did. It may be nil.
+	OnAccept func(xuid string)
+}
+
+// Name ...
+func (Rules) Name() string {
+	return "rules"
+}
+
+// Join ...
+func (r Rules) Join(j *Join) error {
+	xuid := j.Session.Client().IdentityData().XUID
+	if r.Accepted != nil && r.Accepted(xuid) {
+		return nil
+	}
+	j.OnStart(func(s *Session) {
+		form := map[string]any{
+			"type":    "modal",
+			"title":   r.Title,
+			"content": r.Content,
+			"button1": "Accept",
+			"button2": "Decline",
+		}
+		err := s.SendForm(form, func(response []byte) {
+			if strings.TrimSpace(string(response)) != "true" {
+				s.Disconnect("You must accept the rules to play on this server.")
+				return
+			}
+			if r.OnAccept != nil {
+				r.OnAccept(xuid)
+			}
+		})
+		if err != nil {
+			s.logger.Error("Failed to send rules", "err", err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,80 @@
+package session
+
+import (
+	"errors"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/server"
+	packet2 "github.com/spectrum-proxy/spectrum/server/packet"
+	"github.com/spectrum-proxy/spectrum/session/latency"
+	"net"
+	"time"
+)
+
+// handleLatency measures the latency of the client at the interval passed in milliseconds and forwards it to
+// the server of the session, allowing the server to compensate for the latency added by the proxy, for example
+// in combat and movement checks. The latency is also forwarded directly after every transfer.
+func handleLatency(s *Session, interval int64) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Millisecond * time.Duration(interval))
+	defer ticker.Stop()
+
+	// The latency measured by RakNet is observed once, so that the latency is known before the first
+	// NetworkStackLatency round-trip completes.
+	s.latency.Observe(s.Client().Latency())
+	for {
+		if s.closed.Load() {
+			return
+		}
+		if !s.transferring.Load() {
+			s.measureLatency(time.Millisecond * time.Duration(interval))
+			latencyHistogram.Observe(float64(s.latency.Latency().Milliseconds()))
+			s.sendLatency(s.Server())
+			if s.ping != nil {
+				s.ping.update()
+			}
+		}
+		<-ticker.C
+	}
+}
+
+// measureLatency takes a latency sample using the latency.Strategy of the session. Requests that were not
+// answered within the timeout passed are abandoned.
+func (s *Session) measureLatency(timeout time.Duration) {
+	switch s.opts.LatencyStrategy {
+	case latency.StrategyNetworkStack:
+		if timestamp, ok := s.probe.Start(timeout); ok {
+			_ = s.Client().WritePacket(&packet.NetworkStackLatency{Timestamp: timestamp, NeedsResponse: true})
+		}
+	default:
+		s.latency.Observe(s.Client().Latency())
+	}
+}
+
+// handleLatencyResponse observes the round-trip time of a NetworkStackLatency request sent by the proxy. It
+// returns true if the packet passed was the response to such a request, in which case it must not be
+// forwarded to the server.
+func (s *Session) handleLatencyResponse(pk packet.Packet) bool {
+	response, ok := pk.(*packet.NetworkStackLatency)
+	if !ok {
+		return false
+	}
+	rtt, ok := s.probe.Finish(response.Timestamp)
+	if ok {
+		s.latency.Observe(rtt)
+	}
+	return ok
+}
+
+// sendLatency writes the current latency of the client to the server connection passed.
+func (s *Session) sendLatency(conn *server.Conn) {
+	err := conn.WritePacket(&packet2.Latency{
+		Latency:   s.latency.Latency().Milliseconds(),
+		Timestamp: time.Now().UnixMilli(),
+	})
+	if err != nil && !s.closed.Load() && !errors.Is(err, net.ErrClosed) {
+		s.logger.Error("Failed to send latency packet", "err", err)
+	}
+}
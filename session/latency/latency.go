@@ -0,0 +1,96 @@
+// Package latency implements measuring the latency of connections. Samples are smoothed using an exponential
+// moving average, and the jitter and 99th percentile of recent samples are tracked alongside.
+package latency
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Strategy is a method of measuring the latency of a client.
+type Strategy string
+
+const (
+	// StrategyRakNet uses the latency measured by RakNet on the connection of the client.
+	StrategyRakNet Strategy = "raknet"
+	// StrategyNetworkStack measures the round-trip time of NetworkStackLatency packets sent to the client,
+	// which includes the time the client takes to process packets.
+	StrategyNetworkStack Strategy = "network_stack"
+)
+
+const (
+	// DefaultSmoothing is the weight of new samples in the moving average used if none is configured.
+	DefaultSmoothing = 0.2
+	// samples is the amount of recent samples kept to compute percentiles.
+	samples = 64
+)
+
+// Tracker tracks the latency of a connection from the samples observed. A Tracker is safe for concurrent use.
+type Tracker struct {
+	smoothing float64
+
+	mu      sync.Mutex
+	latency float64
+	jitter  float64
+	last    time.Duration
+	recent  []time.Duration
+	next    int
+}
+
+// NewTracker returns a new Tracker weighing new samples with the smoothing factor passed, which must lie in
+// (0, 1]. If it does not, DefaultSmoothing is used.
+func NewTracker(smoothing float64) *Tracker {
+	if smoothing <= 0 || smoothing > 1 {
+		smoothing = DefaultSmoothing
+	}
+	return &Tracker{smoothing: smoothing, recent: make([]time.Duration, 0, samples)}
+}
+
+// Observe records a latency sample.
+func (t *Tracker) Observe(sample time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.recent) == 0 {
+		t.latency = float64(sample)
+	} else {
+		t.latency += t.smoothing * (float64(sample) - t.latency)
+		t.jitter += t.smoothing * (float64((sample - t.last).Abs()) - t.jitter)
+	}
+	t.last = sample
+
+	if len(t.recent) < samples {
+		t.recent = append(t.recent, sample)
+		return
+	}
+	t.recent[t.next] = sample
+	t.next = (t.next + 1) % samples
+}
+
+// Latency returns the moving average of the latency observed.
+func (t *Tracker) Latency() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return time.Duration(t.latency)
+}
+
+// Jitter returns the moving average of the difference between consecutive samples.
+func (t *Tracker) Jitter() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return time.Duration(t.jitter)
+}
+
+// P99 returns the 99th percentile of the recent samples observed.
+func (t *Tracker) P99() time.Duration {
+	t.mu.Lock()
+	sorted := slices.Clone(t.recent)
+	t.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return 0
+	}
+	slices.Sort(sorted)
+	return sorted[(len(sorted)*99-1)/100]
+}
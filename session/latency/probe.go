@@ -0,0 +1,45 @@
+package latency
+
+import (
+	"sync"
+	"time"
+)
+
+// Probe matches NetworkStackLatency responses of a client to the requests sent to it. Only one request is
+// outstanding at a time, so that a slow client does not accumulate requests.
+type Probe struct {
+	mu        sync.Mutex
+	timestamp int64
+	sent      time.Time
+}
+
+// Start starts a new measurement and returns the timestamp to send in a NetworkStackLatency packet. It
+// returns false if the previous request has not been answered yet and was sent less than the timeout passed
+// ago.
+func (p *Probe) Start(timeout time.Duration) (int64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if p.timestamp != 0 && now.Sub(p.sent) < timeout {
+		return 0, false
+	}
+	// The timestamp is rounded to a multiple of 1000, as some versions of the client divide it by 1000 before
+	// responding.
+	p.timestamp, p.sent = now.UnixMilli()*1000, now
+	return p.timestamp, true
+}
+
+// Finish returns the round-trip time of the request with the timestamp the client responded with. It returns
+// false if the response does not belong to a request of the Probe, in which case it should be forwarded to
+// the server.
+func (p *Probe) Finish(timestamp int64) (time.Duration, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.timestamp == 0 || (timestamp != p.timestamp && timestamp*1000 != p.timestamp) {
+		return 0, false
+	}
+	p.timestamp = 0
+	return time.Since(p.sent), true
+}
@@ -0,0 +1,37 @@
+package session
+
+import (
+	"fmt"
+	"github.com/spectrum-proxy/spectrum/locale"
+)
+
+// Locale returns the locale of the client of the session, such as en_US, as reported in its client data.
+func (s *Session) Locale() string {
+	return locale.Normalize(s.Client().ClientData().LanguageCode)
+}
+
+// Translate translates the message with the key passed to the locale of the session using the translator
+// passed in its Opts, formatting it with the arguments passed. If the message has no translation, the key is
+// used as template instead.
+func (s *Session) Translate(key string, args ...any) string {
+	if s.opts.Translator != nil {
+		if message, ok := s.opts.Translator.Translate(s.Locale(), key, args...); ok {
+			return message
+		}
+	}
+	if len(args) == 0 {
+		return key
+	}
+	return fmt.Sprintf(key, args...)
+}
+
+// localize translates the message passed if it is the key of a translated message, so that messages sent by
+// the proxy are localized automatically. Other messages are returned as is.
+func (s *Session) localize(message string) string {
+	if s.opts.Translator != nil {
+		if translated, ok := s.opts.Translator.Translate(s.Locale(), message); ok {
+			return translated
+		}
+	}
+	return message
+}
@@ -0,0 +1,40 @@
+package session
+
+import (
+	"context"
+	"log/slog"
+)
+
+// newSessionLogger returns a logger that attaches the fields of the session passed to every record logged.
+func newSessionLogger(s *Session, logger *slog.Logger) *slog.Logger {
+	identity := s.Client().IdentityData()
+	return slog.New(sessionHandler{Handler: logger.Handler(), s: s}).With(
+		"xuid", identity.XUID,
+		"name", identity.DisplayName,
+		"version", s.Client().ClientData().GameVersion,
+	)
+}
+
+// sessionHandler is a slog.Handler that adds the address of the server a session is connected to to every
+// record. Unlike the other fields of the session, the address changes over time, so it cannot be attached
+// through slog.Logger.With.
+type sessionHandler struct {
+	slog.Handler
+	s *Session
+}
+
+// Handle ...
+func (h sessionHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.AddAttrs(slog.String("server", h.s.ServerAddr()))
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs ...
+func (h sessionHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return sessionHandler{Handler: h.Handler.WithAttrs(attrs), s: h.s}
+}
+
+// WithGroup ...
+func (h sessionHandler) WithGroup(name string) slog.Handler {
+	return sessionHandler{Handler: h.Handler.WithGroup(name), s: h.s}
+}
@@ -0,0 +1,201 @@
+package session
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"slices"
+)
+
+const (
+	// menuWindowID is the window ID of menus opened by the proxy. It lies above the window IDs servers assign
+	// to containers and below the special window IDs of the protocol.
+	menuWindowID = 110
+	// MenuSize is the amount of slots of a menu.
+	MenuSize = 27
+)
+
+// Menu is a chest inventory opened by the proxy, of which the items cannot be moved. Clicks on the items are
+// handled by the proxy and never forwarded to the server. The network IDs of the items must match those of
+// the server the session is connected to.
+type Menu struct {
+	// Title is the title shown at the top of the menu.
+	Title string
+	// Items holds the items in the slots of the menu. Items beyond MenuSize are ignored.
+	Items []protocol.ItemInstance
+	// OnClick is called when the player clicks a slot of the menu. It may be nil.
+	OnClick func(s *Session, slot int)
+	// OnClose is called when the menu is closed, whether by the player, by opening another menu or by a
+	// transfer. It may be nil.
+	OnClose func(s *Session)
+}
+
+// openMenu is a Menu opened for the client along with the ID of the entity holding it.
+type openMenu struct {
+	Menu
+	entity int64
+}
+
+// OpenMenu opens a menu for the client, closing the menu currently open, if any. The menu is held by an
+// invisible entity at the position of the player.
+func (s *Session) OpenMenu(m Menu) {
+	s.CloseMenu()
+
+	m.Items = slices.Clone(m.Items[:min(len(m.Items), MenuSize)])
+	menu := &openMenu{Menu: m, entity: s.nextEntityID()}
+	s.menuMu.Lock()
+	s.menu = menu
+	s.menuMu.Unlock()
+
+	pos := s.Position().Sub(mgl32.Vec3{0, 2})
+	metadata := protocol.NewEntityMetadata()
+	metadata.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagInvisible)
+	metadata.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagNoAI)
+	metadata[protocol.EntityDataKeyName] = m.Title
+	metadata[protocol.EntityDataKeyContainerType] = uint8(protocol.ContainerTypeContainer)
+	metadata[protocol.EntityDataKeyContainerSize] = int32(MenuSize)
+
+	_ = s.Client().WritePacket(&packet.AddActor{
+		EntityUniqueID:  menu.entity,
+		EntityRuntimeID: uint64(menu.entity),
+		EntityType:      "minecraft:chest_minecart",
+		Position:        pos,
+		EntityMetadata:  metadata,
+	})
+	_ = s.Client().WritePacket(&packet.ContainerOpen{
+		WindowID:                menuWindowID,
+		ContainerType:           protocol.ContainerTypeContainer,
+		ContainerPosition:       protocol.BlockPos{int32(pos[0]), int32(pos[1]), int32(pos[2])},
+		ContainerEntityUniqueID: menu.entity,
+	})
+	s.sendMenuItems(menu)
+}
+
+// SetMenuItem replaces the item in the slot passed of the menu currently open. It is a no-op if no menu is
+// open.
+func (s *Session) SetMenuItem(slot int, item protocol.ItemInstance) {
+	s.menuMu.Lock()
+	menu := s.menu
+	if menu != nil && slot >= 0 && slot < MenuSize {
+		if slot >= len(menu.Items) {
+			menu.Items = append(menu.Items, make([]protocol.ItemInstance, slot+1-len(menu.Items))...)
+		}
+		menu.Items[slot] = item
+	}
+	s.menuMu.Unlock()
+	if menu == nil || slot < 0 || slot >= MenuSize {
+		return
+	}
+	_ = s.Client().WritePacket(&packet.InventorySlot{
+		WindowID: menuWindowID,
+		Slot:     uint32(slot),
+		NewItem:  item,
+	})
+}
+
+// CloseMenu closes the menu currently open. It is a no-op if no menu is open.
+func (s *Session) CloseMenu() {
+	if menu := s.takeMenu(); menu != nil {
+		_ = s.Client().WritePacket(&packet.ContainerClose{WindowID: menuWindowID, ServerSide: true})
+		s.removeMenu(menu)
+	}
+}
+
+// handleMenuPacket handles a packet sent by the client while a menu is open. It returns true if the packet
+// concerns the menu, in which case it must not be forwarded to the server.
+func (s *Session) handleMenuPacket(pk packet.Packet) bool {
+	s.menuMu.Lock()
+	menu := s.menu
+	s.menuMu.Unlock()
+	if menu == nil {
+		return false
+	}
+
+	switch pk := pk.(type) {
+	case *packet.ContainerClose:
+		if pk.WindowID != menuWindowID || s.takeMenu() != menu {
+			return false
+		}
+		_ = s.Client().WritePacket(&packet.ContainerClose{WindowID: menuWindowID})
+		s.removeMenu(menu)
+		return true
+	case *packet.ItemStackRequest:
+		// Items in a menu cannot be moved, so all requests made while it is open are rejected, which reverts
+		// them on the client.
+		responses := make([]protocol.ItemStackResponse, 0, len(pk.Requests))
+		for _, req := range pk.Requests {
+			if slot, ok := menuSlot(req.Actions); ok {
+				s.clickMenu(menu, slot)
+			}
+			responses = append(responses, protocol.ItemStackResponse{Status: protocol.ItemStackResponseStatusError, RequestID: req.RequestID})
+		}
+		_ = s.Client().WritePacket(&packet.ItemStackResponse{Responses: responses})
+		return true
+	case *packet.InventoryTransaction:
+		for _, action := range pk.Actions {
+			if action.WindowID == menuWindowID {
+				s.clickMenu(menu, int(action.InventorySlot))
+				s.sendMenuItems(menu)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// menuSlot returns the slot of the menu that the actions of an item stack request passed refer to.
+func menuSlot(actions []protocol.StackRequestAction) (int, bool) {
+	for _, action := range actions {
+		var slots []protocol.StackRequestSlotInfo
+		switch action := action.(type) {
+		case *protocol.TakeStackRequestAction:
+			slots = []protocol.StackRequestSlotInfo{action.Source, action.Destination}
+		case *protocol.PlaceStackRequestAction:
+			slots = []protocol.StackRequestSlotInfo{action.Source, action.Destination}
+		case *protocol.SwapStackRequestAction:
+			slots = []protocol.StackRequestSlotInfo{action.Source, action.Destination}
+		case *protocol.DropStackRequestAction:
+			slots = []protocol.StackRequestSlotInfo{action.Source}
+		}
+		for _, slot := range slots {
+			if slot.ContainerID == protocol.ContainerLevelEntity {
+				return int(slot.Slot), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// clickMenu calls the click callback of the menu passed for the slot passed.
+func (s *Session) clickMenu(menu *openMenu, slot int) {
+	if menu.OnClick != nil && slot >= 0 && slot < MenuSize {
+		menu.OnClick(s, slot)
+	}
+}
+
+// takeMenu removes the menu currently open from the session and returns it, or nil if no menu is open.
+func (s *Session) takeMenu() *openMenu {
+	s.menuMu.Lock()
+	defer s.menuMu.Unlock()
+	menu := s.menu
+	s.menu = nil
+	return menu
+}
+
+// removeMenu removes the entity holding the menu passed and calls its close callback.
+func (s *Session) removeMenu(menu *openMenu) {
+	_ = s.Client().WritePacket(&packet.RemoveActor{EntityUniqueID: menu.entity})
+	if menu.OnClose != nil {
+		menu.OnClose(s)
+	}
+}
+
+// sendMenuItems sends the items of the menu passed to the client.
+func (s *Session) sendMenuItems(menu *openMenu) {
+	s.menuMu.Lock()
+	content := make([]protocol.ItemInstance, MenuSize)
+	copy(content, menu.Items)
+	s.menuMu.Unlock()
+
+	_ = s.Client().WritePacket(&packet.InventoryContent{WindowID: menuWindowID, Content: content})
+}
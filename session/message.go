@@ -0,0 +1,65 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"time"
+)
+
+// SendMessage sends a raw chat message to the client of the session. If the message is the key of a
+// translated message, it is translated to the locale of the session. The same applies to the other methods
+// sending text.
+func (s *Session) SendMessage(message string) {
+	s.sendText(packet.TextTypeRaw, message)
+}
+
+// SendTip sends a tip to the client of the session, which is displayed above the hotbar.
+func (s *Session) SendTip(message string) {
+	s.sendText(packet.TextTypeTip, message)
+}
+
+// SendPopup sends a popup to the client of the session, which is displayed above the hotbar, above any tip.
+func (s *Session) SendPopup(message string) {
+	s.sendText(packet.TextTypePopup, message)
+}
+
+// SendTitle sends a title and subtitle to the client of the session. The durations passed control how long
+// the title takes to fade in, how long it remains on screen and how long it takes to fade out. If subtitle is
+// empty, no subtitle is shown.
+func (s *Session) SendTitle(title, subtitle string, fadeIn, remain, fadeOut time.Duration) {
+	s.sendTitle(packet.TitleActionSetDurations, "", fadeIn, remain, fadeOut)
+	if subtitle != "" {
+		s.sendTitle(packet.TitleActionSetSubtitle, subtitle, fadeIn, remain, fadeOut)
+	}
+	s.sendTitle(packet.TitleActionSetTitle, title, fadeIn, remain, fadeOut)
+}
+
+// SendActionBar sends a message to the action bar of the client of the session.
+func (s *Session) SendActionBar(message string) {
+	s.sendTitle(packet.TitleActionSetActionBar, message, 0, 0, 0)
+}
+
+// sendText writes a Text packet with the type and message passed to the client.
+func (s *Session) sendText(textType byte, message string) {
+	_ = s.Client().WritePacket(&packet.Text{
+		TextType: textType,
+		Message:  s.localize(message),
+		XUID:     s.Client().IdentityData().XUID,
+	})
+}
+
+// sendTitle writes a SetTitle packet with the action, text and durations passed to the client.
+func (s *Session) sendTitle(action int32, text string, fadeIn, remain, fadeOut time.Duration) {
+	_ = s.Client().WritePacket(&packet.SetTitle{
+		ActionType:      action,
+		Text:            s.localize(text),
+		FadeInDuration:  durationToTicks(fadeIn),
+		RemainDuration:  durationToTicks(remain),
+		FadeOutDuration: durationToTicks(fadeOut),
+		XUID:            s.Client().IdentityData().XUID,
+	})
+}
+
+// durationToTicks converts a time.Duration to an amount of game ticks.
+func durationToTicks(d time.Duration) int32 {
+	return int32(d / (time.Second / 20))
+}
@@ -0,0 +1,52 @@
+package session
+
+import (
+	"github.com/spectrum-proxy/spectrum/metrics"
+	"strconv"
+)
+
+var (
+	transfersTotal         = metrics.NewCounter("spectrum_transfers_total", "Amount of successful transfers.")
+	dialFailuresTotal      = metrics.NewCounterVec("spectrum_dial_failures_total", "Amount of sessions that could not connect to the server they joined by category.", "category")
+	joinRejectionsTotal    = metrics.NewCounterVec("spectrum_join_rejections_total", "Amount of sessions rejected while joining by join step.", "step")
+	externalTransfersTotal = metrics.NewCounter("spectrum_external_transfers_total", "Amount of transfers to external addresses.")
+	// transferFailuresTotal counts every server a transfer failed to connect to, including fallbacks.
+	transferFailuresTotal = metrics.NewCounter("spectrum_transfer_failures_total", "Amount of failed transfer attempts.")
+
+	packetsTotal = metrics.NewCounterVec("spectrum_packets_total", "Amount of packets forwarded by direction.", "direction")
+	// clientboundPackets and serverboundPackets are cached, so that the label does not need to be looked up
+	// for every packet.
+	clientboundPackets = packetsTotal.With("clientbound")
+	serverboundPackets = packetsTotal.With("serverbound")
+
+	movementViolationsTotal = metrics.NewCounterVec("spectrum_movement_violations_total", "Amount of times players were flagged for their movement by check.", "check")
+	riskSignalsTotal        = metrics.NewCounterVec("spectrum_risk_signals_total", "Amount of signals of modified clients raised by signal.", "signal")
+
+	latencyHistogram = metrics.NewHistogram("spectrum_latency_milliseconds", "Latency between clients and the proxy.",
+		[]float64{10, 25, 50, 75, 100, 150, 200, 300, 500, 1000})
+
+	_ = metrics.NewGaugeVecFunc("spectrum_clientbound_packets_by_id", "Amount of packets sent by servers per packet ID.", "id", trafficMetric(false, false))
+	_ = metrics.NewGaugeVecFunc("spectrum_clientbound_bytes_by_id", "Bytes of packets sent by servers per packet ID.", "id", trafficMetric(false, true))
+	_ = metrics.NewGaugeVecFunc("spectrum_serverbound_packets_by_id", "Amount of packets sent to servers per packet ID.", "id", trafficMetric(true, false))
+	_ = metrics.NewGaugeVecFunc("spectrum_serverbound_bytes_by_id", "Bytes of packets sent to servers per packet ID.", "id", trafficMetric(true, true))
+)
+
+// trafficMetric returns a function reporting the global traffic per packet ID in the direction passed, either
+// as amount of packets or as bytes.
+func trafficMetric(serverbound, bytes bool) func() map[string]float64 {
+	return func() map[string]float64 {
+		stats := GlobalTraffic()
+		traffic := stats.Clientbound
+		if serverbound {
+			traffic = stats.Serverbound
+		}
+		values := make(map[string]float64, len(traffic))
+		for id, t := range traffic {
+			values[strconv.Itoa(int(id))] = float64(t.Count)
+			if bytes {
+				values[strconv.Itoa(int(id))] = float64(t.Bytes)
+			}
+		}
+		return values
+	}
+}
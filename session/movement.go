@@ -0,0 +1,145 @@
+package session
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/event"
+	"slices"
+)
+
+// Verdict is the outcome of analysing a Movement of a player.
+type Verdict uint8
+
+const (
+	// VerdictAllow allows the movement.
+	VerdictAllow Verdict = iota
+	// VerdictFlag allows the movement, but flags the player for it.
+	VerdictFlag
+	// VerdictRubberBand flags the player and reverts the movement, teleporting the player back to the last
+	// position that was allowed.
+	VerdictRubberBand
+	// VerdictDisconnect flags the player and disconnects it.
+	VerdictDisconnect
+)
+
+// String ...
+func (v Verdict) String() string {
+	switch v {
+	case VerdictAllow:
+		return "allow"
+	case VerdictFlag:
+		return "flag"
+	case VerdictRubberBand:
+		return "rubber_band"
+	case VerdictDisconnect:
+		return "disconnect"
+	}
+	return "unknown"
+}
+
+// Movement is the movement of a player in a single tick, as reported by its client in a PlayerAuthInput
+// packet.
+type Movement struct {
+	// Tick is the tick of the client the movement happened in.
+	Tick uint64
+	// Position is the position of the feet of the player after the movement, and Previous the position of
+	// the feet of the player before it, which is the last position that was allowed.
+	Position, Previous mgl32.Vec3
+	// Delta is the velocity of the player as predicted by the client.
+	Delta mgl32.Vec3
+	// Pitch, Yaw and HeadYaw are the rotation of the player.
+	Pitch, Yaw, HeadYaw float32
+	// MoveVector is the movement input of the player, ranging from -1 to 1 on both axes.
+	MoveVector mgl32.Vec2
+	// InputData holds the input flags of the player, such as packet.InputFlagJumping.
+	InputData uint64
+	// InputMode is the input mode of the client, such as packet.InputModeTouch.
+	InputMode uint32
+	// PlayMode is the play mode of the client, such as packet.PlayModeNormal.
+	PlayMode uint32
+}
+
+// MovementAnalyzer analyses the movement of players, for example to detect cheats. Analyzers are called for
+// every tick of movement in the order they were added, and may keep state per session in its Store.
+type MovementAnalyzer interface {
+	// Name returns the name of the check the analyzer performs, which is reported when it flags a player.
+	Name() string
+	// AnalyzeMovement analyses a Movement of the player of the session passed and returns the action taken.
+	AnalyzeMovement(s *Session, m Movement) Verdict
+}
+
+// AddMovementAnalyzer adds an analyzer that the movement of the player of the session is passed to.
+func (s *Session) AddMovementAnalyzer(a MovementAnalyzer) {
+	s.analyzersMu.Lock()
+	defer s.analyzersMu.Unlock()
+
+	analyzers := append(slices.Clone(*s.analyzers.Load()), a)
+	s.analyzers.Store(&analyzers)
+}
+
+// handleMovement passes the movement in the packet passed, sent by the client, to the analyzers of the session
+// and records the position of the player if it is allowed. It returns false if the packet must be dropped.
+func (s *Session) handleMovement(pk packet.Packet) bool {
+	input, ok := pk.(*packet.PlayerAuthInput)
+	if !ok {
+		return true
+	}
+	analyzers := *s.analyzers.Load()
+	if len(analyzers) == 0 {
+		s.trackPosition(input)
+		return true
+	}
+
+	m := Movement{
+		Tick:       input.Tick,
+		Position:   input.Position.Sub(mgl32.Vec3{0, eyeHeight}),
+		Previous:   s.Position(),
+		Delta:      input.Delta,
+		Pitch:      input.Pitch,
+		Yaw:        input.Yaw,
+		HeadYaw:    input.HeadYaw,
+		MoveVector: input.MoveVector,
+		InputData:  input.InputData,
+		InputMode:  input.InputMode,
+		PlayMode:   input.PlayMode,
+	}
+	verdict := VerdictAllow
+	for _, a := range analyzers {
+		if v := a.AnalyzeMovement(s, m); v != VerdictAllow {
+			verdict = max(verdict, s.flag(a.Name(), v))
+		}
+	}
+
+	switch verdict {
+	case VerdictRubberBand:
+		// The server is told the player did not move, while the client is moved back.
+		input.Position = m.Previous.Add(mgl32.Vec3{0, eyeHeight})
+		_ = s.Client().WritePacket(&packet.MovePlayer{
+			EntityRuntimeID: s.Client().GameData().EntityRuntimeID,
+			Position:        input.Position,
+			Pitch:           input.Pitch,
+			Yaw:             input.Yaw,
+			HeadYaw:         input.HeadYaw,
+			Mode:            packet.MoveModeReset,
+			Tick:            input.Tick,
+		})
+	case VerdictDisconnect:
+		s.Disconnect("You were disconnected for suspicious movement.")
+		return false
+	}
+	s.trackPosition(input)
+	return true
+}
+
+// flag reports that the check passed flagged the player with the verdict passed. It returns the verdict after
+// it was passed to the handler of the session, which may change it.
+func (s *Session) flag(check string, verdict Verdict) Verdict {
+	ctx := event.New()
+	s.handler.HandleMovementViolation(ctx, check, &verdict)
+	if ctx.Cancelled() {
+		return VerdictAllow
+	}
+	movementViolationsTotal.With(check).Inc()
+	s.logger.Warn("Player flagged for movement", "check", check, "verdict", verdict)
+	return verdict
+}
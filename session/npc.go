@@ -0,0 +1,128 @@
+package session
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"maps"
+)
+
+// NPC is an entity shown to a client by the proxy that players can click, such as a server selector in a lobby.
+// NPCs are not known to the server: clicks on them are handled by the proxy and never forwarded.
+type NPC struct {
+	// Name is the name tag shown above the NPC. If empty, no name tag is shown.
+	Name string
+	// EntityType is the type of the entity shown, such as "minecraft:npc" or "minecraft:villager_v2".
+	EntityType string
+	// Position is the position of the NPC in the world of the server the session is connected to.
+	Position mgl32.Vec3
+	// Yaw and Pitch are the rotation of the NPC.
+	Yaw, Pitch float32
+	// Metadata holds additional metadata of the entity, such as its variant or scale. It may be nil.
+	Metadata protocol.EntityMetadata
+	// Persistent keeps the NPC shown after the session is transferred. Otherwise, the NPC is removed when the
+	// session leaves the server it was spawned on.
+	Persistent bool
+	// OnClick is called when the player clicks the NPC. Attack is true if the player hit the NPC rather than
+	// interacting with it. It may be nil.
+	OnClick func(s *Session, attack bool)
+}
+
+// SpawnNPC spawns an NPC for the client and returns its ID, which may be used to remove it.
+func (s *Session) SpawnNPC(npc NPC) int64 {
+	id := s.nextEntityID()
+
+	s.npcsMu.Lock()
+	s.npcs[id] = npc
+	s.npcsMu.Unlock()
+
+	s.sendNPC(id, npc)
+	return id
+}
+
+// RemoveNPC removes the NPC with the ID passed. It is a no-op if no such NPC is shown.
+func (s *Session) RemoveNPC(id int64) {
+	s.npcsMu.Lock()
+	_, ok := s.npcs[id]
+	delete(s.npcs, id)
+	s.npcsMu.Unlock()
+	if ok {
+		_ = s.Client().WritePacket(&packet.RemoveActor{EntityUniqueID: id})
+	}
+}
+
+// NPCs returns the NPCs shown to the client, keyed by their ID.
+func (s *Session) NPCs() map[int64]NPC {
+	s.npcsMu.Lock()
+	defer s.npcsMu.Unlock()
+	return maps.Clone(s.npcs)
+}
+
+// handleNPCPacket handles a packet sent by the client aimed at an NPC. It returns true if the packet was aimed
+// at an NPC, in which case it must not be forwarded to the server.
+func (s *Session) handleNPCPacket(pk packet.Packet) bool {
+	switch pk := pk.(type) {
+	case *packet.InventoryTransaction:
+		data, ok := pk.TransactionData.(*protocol.UseItemOnEntityTransactionData)
+		if !ok {
+			return false
+		}
+		npc, ok := s.npc(data.TargetEntityRuntimeID)
+		if ok && npc.OnClick != nil {
+			npc.OnClick(s, data.ActionType == protocol.UseItemOnEntityActionAttack)
+		}
+		return ok
+	case *packet.Interact:
+		_, ok := s.npc(pk.TargetEntityRuntimeID)
+		return ok
+	}
+	return false
+}
+
+// npc returns the NPC with the runtime ID passed, if it is shown.
+func (s *Session) npc(runtimeID uint64) (NPC, bool) {
+	s.npcsMu.Lock()
+	defer s.npcsMu.Unlock()
+	npc, ok := s.npcs[int64(runtimeID)]
+	return npc, ok
+}
+
+// resendNPCs removes the NPCs that are not persistent and shows the others again. It is called after a
+// transfer, during which the client forgets the entities of the NPCs.
+func (s *Session) resendNPCs() {
+	s.npcsMu.Lock()
+	defer s.npcsMu.Unlock()
+	for id, npc := range s.npcs {
+		if !npc.Persistent {
+			delete(s.npcs, id)
+			continue
+		}
+		s.sendNPC(id, npc)
+	}
+}
+
+// sendNPC spawns the entity of the NPC with the ID passed.
+func (s *Session) sendNPC(id int64, npc NPC) {
+	metadata := protocol.NewEntityMetadata()
+	metadata.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagNoAI)
+	metadata[protocol.EntityDataKeyScale] = float32(1)
+	if npc.Name != "" {
+		metadata[protocol.EntityDataKeyName] = npc.Name
+		metadata[protocol.EntityDataKeyAlwaysShowNameTag] = uint8(1)
+		metadata.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagShowName)
+		metadata.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagAlwaysShowName)
+	}
+	maps.Copy(metadata, npc.Metadata)
+
+	_ = s.Client().WritePacket(&packet.AddActor{
+		EntityUniqueID:  id,
+		EntityRuntimeID: uint64(id),
+		EntityType:      npc.EntityType,
+		Position:        npc.Position,
+		Pitch:           npc.Pitch,
+		Yaw:             npc.Yaw,
+		HeadYaw:         npc.Yaw,
+		BodyYaw:         npc.Yaw,
+		EntityMetadata:  metadata,
+	})
+}
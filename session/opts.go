@@ -0,0 +1,163 @@
+package session
+
+import (
+	"github.com/spectrum-proxy/spectrum/audit"
+	"github.com/spectrum-proxy/spectrum/event"
+	"github.com/spectrum-proxy/spectrum/locale"
+	"github.com/spectrum-proxy/spectrum/resourcepack"
+	"github.com/spectrum-proxy/spectrum/server"
+	"github.com/spectrum-proxy/spectrum/session/latency"
+	"github.com/spectrum-proxy/spectrum/social"
+)
+
+// Opts holds the options used by a Session.
+type Opts struct {
+	// LatencyInterval is the interval at which the latency of the connection is updated in milliseconds.
+	LatencyInterval int64
+	// LatencyStrategy is the strategy used to measure the latency of the client. If empty,
+	// latency.StrategyRakNet is used.
+	LatencyStrategy latency.Strategy
+	// LatencySmoothing is the weight of new latency samples in the moving average of the latency, between 0
+	// and 1. If zero, latency.DefaultSmoothing is used.
+	LatencySmoothing float64
+	// PingDisplay enables showing the latency measured by the proxy next to the names of players in the player
+	// list of the client. It is updated at LatencyInterval.
+	PingDisplay bool
+	// TransferRetries is the amount of times dialing a server is retried during a transfer before moving on
+	// to the next fallback address.
+	TransferRetries int
+	// TransferBackoff is the time in milliseconds waited before retrying a failed dial. The backoff is doubled
+	// after every failed attempt.
+	TransferBackoff int64
+	// FallbackResolver resolves the server the session is moved to when the connection to its current server
+	// is lost. If nil, the session is closed instead.
+	FallbackResolver FallbackResolver
+	// Servers is the registry used to resolve server names passed to Transfer to addresses. If nil, all
+	// servers must be referred to by their address.
+	Servers *server.Registry
+	// Compression holds the compression negotiated with servers, keyed by the name or address of the server.
+	// Servers without an entry use the default compression.
+	Compression map[string]server.Compression
+	// Transports holds the names of the transports used to connect to servers, as registered using
+	// server.RegisterTransport, keyed by the name or address of the server. Servers without an entry are
+	// connected to over TCP.
+	Transports map[string]string
+	// Pools holds pools of spare connections to servers, keyed by the address of the server. Sessions joining
+	// a server with a pool use one of its spare connections if available.
+	Pools map[string]*server.Pool
+	// Capacities holds the maximum amount of players of servers, keyed by the name or address of the server.
+	// Transfers to a server at its capacity fail with ErrBackendFull. Servers without an entry have no limit.
+	Capacities map[string]int
+	// Health reports whether servers are reachable. Sessions are not transferred to servers it reports as
+	// down. If nil, all servers are considered reachable.
+	Health HealthChecker
+	// ClientDataPolicy configures how the ClientData of players is filtered before it is forwarded to servers.
+	ClientDataPolicy ClientDataPolicy
+	// Secrets holds the secrets shared with servers that the identity of players is signed with when connecting
+	// to a server, so that servers can reject connections not made by the proxy. If nil, it is not signed.
+	Secrets *server.Secrets
+	// ControlAllPlayers allows servers to request control actions affecting players other than the player of
+	// the session the request was sent through, such as transferring another player or broadcasting a message
+	// to all players. If false, such requests are denied.
+	ControlAllPlayers bool
+	// Breaker is the circuit breaker used when dialing servers. If nil, servers are always dialed.
+	Breaker *server.Breaker
+	// DialTimeout is the maximum time in milliseconds connecting to a server may take. If zero, there is no
+	// limit.
+	DialTimeout int64
+	// LoginTimeout is the maximum time in milliseconds logging in to a server and receiving its game data may
+	// take. If zero, there is no limit.
+	LoginTimeout int64
+	// Events is the bus lifecycle events of the session, such as SessionStart and TransferEnd, are published
+	// on. If nil, no events are published.
+	Events *event.Bus[Event]
+	// ReconnectGrace is the time in milliseconds the connection to the server of a session is kept open after
+	// its client lost its connection unexpectedly. If the client reconnects within that time, the session is
+	// resumed through Session.Resume. Sessions of clients that leave are always closed immediately. If zero,
+	// sessions are closed immediately.
+	ReconnectGrace int64
+	// ReplayWindow is the time in milliseconds of gameplay kept in memory for every session, which may be
+	// saved through Session.SaveReplay, for example when a player is reported. If zero, no gameplay is kept.
+	ReplayWindow int64
+	// JoinPipeline holds the steps the session passes through before the game of its client is started. If
+	// nil, the DefaultJoinPipeline is used.
+	JoinPipeline *JoinPipeline
+	// DialFailureMessage is the message the client is disconnected with if the server it joins cannot be
+	// reached. It may be a translation key, and is formatted with the DialFailure describing why connecting
+	// failed. If empty, a default message is used.
+	DialFailureMessage string
+	// Translator translates messages sent by the proxy to the locale of the session. If nil, messages are not
+	// translated.
+	Translator *locale.Translator
+	// Audit is the sink joins, quits, transfers and kicks of the session are recorded in. If nil, nothing is
+	// recorded.
+	Audit audit.Sink
+	// Throttle limits the bandwidth used to send packets to the client. The zero value does not limit it.
+	Throttle Throttle
+	// ChunkPacing limits the rate at which chunks are sent to the client after joining and transferring. The
+	// zero value does not limit it.
+	ChunkPacing ChunkPacing
+	// ViewDistances holds the maximum view distance in chunks of servers, keyed by the name or address of the
+	// server. The chunk radius requested by the client is clamped to it and chunks outside it are dropped.
+	ViewDistances map[string]int32
+	// NetworkPlayerList makes the player lists of sessions show all players connected to the proxy rather
+	// than only those on the same server.
+	NetworkPlayerList bool
+	// MovementAnalyzers holds the analyzers the movement of players is passed to, for example to detect cheats.
+	MovementAnalyzers []MovementAnalyzer
+	// PlayerListHeader and PlayerListFooter are the texts shown at the top and bottom of the player lists of
+	// sessions. If empty, no header or footer is shown.
+	PlayerListHeader, PlayerListFooter string
+	// PlayerListRanks holds the rank prefixes set through Session.SetRank in the order players are listed in
+	// the player lists of sessions. If empty, players are listed in the order they were added.
+	PlayerListRanks []string
+	// ChatChannels holds the channels over which chat messages are bridged between servers, keyed by their
+	// name. If empty, chat is not bridged.
+	ChatChannels map[string]ChatChannel
+	// Limbo is the address of the limbo server sessions are parked on when the server they join cannot be
+	// reached, or when the connection to their server is lost and no fallback server is available. If empty,
+	// such sessions are closed.
+	Limbo string
+	// Ignores is the store holding the players that players ignore. Players do not receive private messages
+	// and bridged chat messages of players they ignore. If nil, players cannot ignore other players.
+	Ignores social.IgnoreStore
+	// PipelineWorkers enables writing the packets of the session on a separate goroutine per direction, so
+	// that reading packets is not held up by slow writes. Up to four times PipelineWorkers packets are queued
+	// per direction. Packets are always processed in order by the goroutine reading them. If zero, packets are
+	// written by the goroutine reading them.
+	PipelineWorkers int
+	// Passthrough enables passthrough mode, in which packets sent by the server are forwarded to the client
+	// without being decoded, unless the proxy needs to inspect them or their ID is in PassthroughDecode.
+	// Packets that are passed through are not passed to the handler of the session. Packets sent by the
+	// client are always decoded.
+	Passthrough bool
+	// PassthroughDecode holds the IDs of additional packets that are decoded in passthrough mode, such as
+	// packets that the handler of the session needs to receive.
+	PassthroughDecode []uint32
+	// TranslateEntityIDs enables translating the IDs servers assign to entities to IDs that are unique for
+	// the lifetime of the session. This prevents entities of different servers from being confused by the
+	// client when servers assign overlapping IDs, at the cost of decoding every packet referencing an entity.
+	TranslateEntityIDs bool
+	// SeamlessTransfer makes sessions use animation.Seamless by default, transferring them without a
+	// dimension change when both servers are in the same dimension.
+	SeamlessTransfer bool
+	// TransferForwarding keeps packets flowing between the client and the server the session is on while the
+	// connection to the server transferred to is opened, so that the player does not freeze during a transfer.
+	// Forwarding only pauses while the session is switched over to the new server.
+	TransferForwarding bool
+	// Animation is the name of the animation, as registered in the animation package, played when sessions
+	// are transferred. If empty or unknown, animation.Dimension is played.
+	Animation string
+	// FloodLimits limits the rate at which the client may send packets, keyed by packet ID. Packets without a
+	// limit may be sent at any rate.
+	FloodLimits map[uint32]FloodLimit
+	// ResourcePacks holds the resource packs required by servers. Sessions are not transferred to servers
+	// requiring packs the client did not receive when joining. If nil, servers do not require any packs.
+	ResourcePacks *resourcepack.Manager
+	// Region is the region of the player the session belongs to, as resolved by a geoip.Resolver when the
+	// player connected. It is empty if the region is unknown.
+	Region string
+	// Protocol is the ID of the protocol the client logged in with, as returned by version.Take. If zero, the
+	// client is assumed to be on the latest protocol.
+	Protocol int32
+}
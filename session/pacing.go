@@ -0,0 +1,102 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/internal"
+	"sync/atomic"
+	"time"
+)
+
+// chunkPacingQueueSize is the amount of chunk packets queued by a chunkPacer before writing further chunks
+// blocks.
+const chunkPacingQueueSize = 4096
+
+// ChunkPacing limits the rate at which chunks are sent to a client after it joins or is transferred, when
+// servers send all chunks around the player at once. Sending them gradually prevents low-end devices from
+// freezing while they process the chunks.
+type ChunkPacing struct {
+	// Rate is the amount of chunk packets sent per second. If zero, chunks are not paced.
+	Rate float64 `yaml:"rate"`
+	// Burst is the amount of chunk packets that may be sent at once before Rate applies.
+	Burst int `yaml:"burst"`
+	// Window is the time in milliseconds after joining or transferring during which chunks are paced. If
+	// zero, chunks are always paced.
+	Window int64 `yaml:"window"`
+}
+
+// chunkPacer queues the chunk packets sent to the client of a session and writes them at the rate of the
+// ChunkPacing of the session.
+type chunkPacer struct {
+	s      *Session
+	window time.Duration
+	bucket *internal.TokenBucket
+	queue  chan packet.Packet
+	// until is the time in Unix nanoseconds at which the pacing window ends.
+	until atomic.Int64
+}
+
+// newChunkPacer returns a chunkPacer for the session passed, or nil if the ChunkPacing passed does not pace
+// chunks.
+func newChunkPacer(s *Session, p ChunkPacing) *chunkPacer {
+	if p.Rate <= 0 {
+		return nil
+	}
+	return &chunkPacer{
+		s:      s,
+		window: time.Duration(p.Window) * time.Millisecond,
+		bucket: internal.NewTokenBucket(p.Rate, max(p.Burst, 1)),
+		queue:  make(chan packet.Packet, chunkPacingQueueSize),
+	}
+}
+
+// activate starts a new pacing window.
+func (p *chunkPacer) activate() {
+	p.until.Store(time.Now().Add(p.window).UnixNano())
+}
+
+// pace queues the packet passed if it is a chunk and chunks are currently paced. It returns false if the
+// packet should be written to the client directly.
+func (p *chunkPacer) pace(pk packet.Packet) bool {
+	if id := pk.ID(); id != packet.IDLevelChunk && id != packet.IDSubChunk {
+		return false
+	}
+	// Chunks are still queued after the window ended if earlier chunks have not been sent yet, so that they
+	// are not sent out of order.
+	if p.window > 0 && time.Now().UnixNano() > p.until.Load() && len(p.queue) == 0 {
+		return false
+	}
+	select {
+	case p.queue <- pk:
+	case <-p.s.ctx.Done():
+	}
+	return true
+}
+
+// reset drops all queued chunks, which no longer apply after the session was transferred to another server.
+func (p *chunkPacer) reset() {
+	for {
+		select {
+		case <-p.queue:
+		default:
+			return
+		}
+	}
+}
+
+// run writes the queued chunks to the client at the paced rate until the session is closed.
+func (p *chunkPacer) run() {
+	for {
+		select {
+		case <-p.s.ctx.Done():
+			return
+		case pk := <-p.queue:
+			if delay := p.bucket.Reserve(1); delay > 0 {
+				time.Sleep(delay)
+			}
+			if err := p.s.writeClient(pk); err != nil && !p.s.Suspended() {
+				p.s.logger.Error("Failed to write chunk to client", "err", err)
+				return
+			}
+		}
+	}
+}
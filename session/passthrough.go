@@ -0,0 +1,63 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	packet2 "github.com/spectrum-proxy/spectrum/server/packet"
+	"github.com/spectrum-proxy/spectrum/version"
+)
+
+// decodedPackets holds the IDs of packets sent by the server that are always decoded in passthrough mode, as
+// the proxy itself needs to inspect them.
+var decodedPackets = []uint32{
+	packet.IDAddActor,
+	packet.IDAddItemActor,
+	packet.IDAddPainting,
+	packet.IDAddPlayer,
+	packet.IDAvailableCommands,
+	packet.IDBossEvent,
+	packet.IDContainerClose,
+	packet.IDContainerOpen,
+	packet.IDInventoryContent,
+	packet.IDInventorySlot,
+	packet.IDMobEffect,
+	packet.IDModalFormRequest,
+	packet.IDPlayerList,
+	packet.IDPlayerSkin,
+	packet.IDRemoveActor,
+	packet.IDRemoveObjective,
+	packet.IDScriptMessage,
+	packet.IDSetDisplayObjective,
+	packet.IDSetScore,
+
+	packet2.IDLatency,
+	packet2.IDTransfer,
+	packet2.IDControlRequest,
+}
+
+// passthroughFilter returns the set of packet IDs that are decoded in passthrough mode, or nil if passthrough
+// mode is disabled. Passthrough mode is always disabled for clients on an older protocol version, as their
+// packets must be decoded to be converted.
+func (s *Session) passthroughFilter() map[uint32]struct{} {
+	if !s.opts.Passthrough || !version.Latest(s.Protocol()) {
+		return nil
+	}
+
+	decode := make(map[uint32]struct{}, len(decodedPackets)+len(s.opts.PassthroughDecode))
+	for _, id := range decodedPackets {
+		decode[id] = struct{}{}
+	}
+	if s.opts.TranslateEntityIDs {
+		for _, id := range translatedPackets {
+			decode[id] = struct{}{}
+		}
+	}
+	if len(s.opts.ViewDistances) > 0 {
+		for _, id := range viewDistancePackets {
+			decode[id] = struct{}{}
+		}
+	}
+	for _, id := range s.opts.PassthroughDecode {
+		decode[id] = struct{}{}
+	}
+	return decode
+}
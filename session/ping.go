@@ -0,0 +1,146 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"sync"
+)
+
+const (
+	// pingObjective is the name of the objective the proxy shows in the player list to display the latency of
+	// players.
+	pingObjective = "spectrum:ping"
+	// pingEntryOffset is the first scoreboard entry ID used for the latency of players. It lies above the
+	// entry IDs used for the lines of the Scoreboard.
+	pingEntryOffset = proxyEntryOffset + int64(1)<<40
+)
+
+// pingPlayer is a player in the player list of the client.
+type pingPlayer struct {
+	xuid     string
+	uniqueID int64
+	entryID  int64
+}
+
+// pingDisplay shows the latency measured by the proxy next to the names of players in the player list, using
+// a scoreboard objective in the list display slot. Only players connected to the proxy have their latency
+// shown. If the server displays an objective in the list slot itself, the latency is not shown.
+type pingDisplay struct {
+	s  *Session
+	mu sync.Mutex
+
+	players   map[[16]byte]pingPlayer
+	nextEntry int64
+
+	// shown specifies if the objective of the proxy is currently shown in the player list.
+	shown bool
+	// serverList is the scoped name of the objective the server displays in the player list, or empty if it
+	// does not display one.
+	serverList string
+}
+
+// newPingDisplay returns a new pingDisplay for the session passed.
+func newPingDisplay(s *Session) *pingDisplay {
+	return &pingDisplay{s: s, players: make(map[[16]byte]pingPlayer), nextEntry: pingEntryOffset}
+}
+
+// handleServerPacket tracks the players in the player list and the objectives the server displays in the list
+// slot. It must be called after the objective names in the packet were scoped by the Scoreboard.
+func (d *pingDisplay) handleServerPacket(pk packet.Packet) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch pk := pk.(type) {
+	case *packet.PlayerList:
+		for _, entry := range pk.Entries {
+			if pk.ActionType == packet.PlayerListActionAdd {
+				if entry.XUID == "" {
+					continue
+				}
+				d.nextEntry++
+				d.players[entry.UUID] = pingPlayer{xuid: entry.XUID, uniqueID: entry.EntityUniqueID, entryID: d.nextEntry}
+				continue
+			}
+			if player, ok := d.players[entry.UUID]; ok {
+				delete(d.players, entry.UUID)
+				if d.shown {
+					d.write(&packet.SetScore{ActionType: packet.ScoreboardActionRemove, Entries: []protocol.ScoreboardEntry{{
+						EntryID:       player.entryID,
+						ObjectiveName: pingObjective,
+					}}})
+				}
+			}
+		}
+	case *packet.SetDisplayObjective:
+		if pk.DisplaySlot != packet.ScoreboardSlotList {
+			return
+		}
+		d.serverList = pk.ObjectiveName
+		if d.shown {
+			d.write(&packet.RemoveObjective{ObjectiveName: pingObjective})
+			d.shown = false
+		}
+	case *packet.RemoveObjective:
+		if pk.ObjectiveName == d.serverList {
+			d.serverList = ""
+		}
+	}
+}
+
+// update shows the objective of the proxy in the player list if needed and updates the latency of all
+// players in it.
+func (d *pingDisplay) update() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.serverList != "" {
+		return
+	}
+	if !d.shown {
+		d.write(&packet.SetDisplayObjective{
+			DisplaySlot:   packet.ScoreboardSlotList,
+			ObjectiveName: pingObjective,
+			DisplayName:   "ms",
+			CriteriaName:  "dummy",
+			SortOrder:     packet.ScoreboardSortOrderAscending,
+		})
+		d.shown = true
+	}
+
+	entries := make([]protocol.ScoreboardEntry, 0, len(d.players))
+	for _, player := range d.players {
+		session := d.s.registry.GetSession(player.xuid)
+		if session == nil {
+			continue
+		}
+		entries = append(entries, protocol.ScoreboardEntry{
+			EntryID:        player.entryID,
+			ObjectiveName:  pingObjective,
+			Score:          int32(session.Latency()),
+			IdentityType:   protocol.ScoreboardIdentityPlayer,
+			EntityUniqueID: player.uniqueID,
+		})
+	}
+	if len(entries) > 0 {
+		d.write(&packet.SetScore{ActionType: packet.ScoreboardActionModify, Entries: entries})
+	}
+}
+
+// reset removes the objective of the proxy after the players and objectives of the server were cleared during
+// a transfer. It is shown again on the next update.
+func (d *pingDisplay) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	clear(d.players)
+	d.serverList = ""
+	if d.shown {
+		d.write(&packet.RemoveObjective{ObjectiveName: pingObjective})
+		d.shown = false
+	}
+}
+
+// write writes a packet to the client of the session.
+func (d *pingDisplay) write(pk packet.Packet) {
+	_ = d.s.Client().WritePacket(pk)
+}
@@ -0,0 +1,62 @@
+package session
+
+import (
+	"sync"
+)
+
+// pipeline passes the packets processed by the goroutine reading them to a goroutine writing them, so that
+// reading is not held up by slow writes. Packets are processed in order by the reading goroutine, and are
+// delivered in the same order.
+type pipeline[T any] struct {
+	queue chan T
+
+	done chan struct{}
+	once sync.Once
+}
+
+// newPipeline returns a new pipeline holding up to size results pending delivery.
+func newPipeline[T any](size int) *pipeline[T] {
+	return &pipeline[T]{
+		queue: make(chan T, size),
+		done:  make(chan struct{}),
+	}
+}
+
+// push queues the result passed for delivery after all results pushed earlier. push blocks if too many results
+// are pending delivery, and returns false if the pipeline was closed.
+func (p *pipeline[T]) push(result T) bool {
+	select {
+	case p.queue <- result:
+		return true
+	case <-p.done:
+		return false
+	}
+}
+
+// deliver calls f for every result pushed to the pipeline in order, until f returns an error or the pipeline
+// is closed.
+func (p *pipeline[T]) deliver(f func(result T) error) error {
+	defer p.close()
+	for {
+		select {
+		case r := <-p.queue:
+			if err := f(r); err != nil {
+				return err
+			}
+		case <-p.done:
+			return nil
+		}
+	}
+}
+
+// pending returns the amount of results waiting to be delivered.
+func (p *pipeline[T]) pending() int {
+	return len(p.queue)
+}
+
+// close closes the pipeline, stopping delivery of any pending results.
+func (p *pipeline[T]) close() {
+	p.once.Do(func() {
+		close(p.done)
+	})
+}
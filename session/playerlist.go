@@ -0,0 +1,532 @@
+package session
+
+import (
+	"encoding/base64"
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/server"
+	"slices"
+	"sync"
+)
+
+var (
+	// headerUUID and footerUUID are the UUIDs of the entries used to show the header and footer of the player
+	// list.
+	headerUUID = uuid.NewSHA1(uuid.NameSpaceOID, []byte("spectrum:player_list:header"))
+	footerUUID = uuid.NewSHA1(uuid.NameSpaceOID, []byte("spectrum:player_list:footer"))
+)
+
+// PlayerList virtualizes the player list of a session. Entries added through the PlayerList are owned by the
+// proxy: they take precedence over entries of the server with the same UUID and survive transfers. Entries
+// sent by the server may be hidden using a filter.
+//
+// The player list of Bedrock Edition has no header or footer. They are emulated using entries that are kept
+// at the top and bottom of the list respectively, as clients list entries in the order they were added. For
+// the same reason, entries are sorted by adding all of them again in order whenever the list changes.
+type PlayerList struct {
+	s  *Session
+	mu sync.Mutex
+
+	entries map[uuid.UUID]protocol.PlayerListEntry
+	// server holds the entries sent by the current server, whether they are shown or not.
+	server map[uuid.UUID]protocol.PlayerListEntry
+	filter func(entry protocol.PlayerListEntry) bool
+	sorter func(a, b protocol.PlayerListEntry) int
+	// vanished holds the UUIDs of vanished players, whose entries are never shown.
+	vanished map[uuid.UUID]struct{}
+
+	header, footer string
+}
+
+// newPlayerList returns a new PlayerList for the session passed.
+func newPlayerList(s *Session) *PlayerList {
+	return &PlayerList{
+		s:        s,
+		entries:  make(map[uuid.UUID]protocol.PlayerListEntry),
+		server:   make(map[uuid.UUID]protocol.PlayerListEntry),
+		vanished: make(map[uuid.UUID]struct{}),
+	}
+}
+
+// Add adds entries owned by the proxy to the player list, replacing entries with the same UUID.
+func (l *PlayerList) Add(entries ...protocol.PlayerListEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	shown := make([]protocol.PlayerListEntry, 0, len(entries))
+	for _, entry := range entries {
+		l.entries[entry.UUID] = entry
+		if _, ok := l.vanished[entry.UUID]; !ok {
+			shown = append(shown, entry)
+		}
+	}
+	if len(shown) > 0 {
+		l.add(shown)
+	}
+}
+
+// Remove removes the entries owned by the proxy with the UUIDs passed from the player list. If the server sent
+// an entry with the same UUID, it is shown again.
+func (l *PlayerList) Remove(ids ...uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var removed, restored []protocol.PlayerListEntry
+	for _, id := range ids {
+		if _, ok := l.entries[id]; !ok {
+			continue
+		}
+		delete(l.entries, id)
+		if _, ok := l.vanished[id]; ok {
+			continue
+		}
+		removed = append(removed, protocol.PlayerListEntry{UUID: id})
+		if entry, ok := l.server[id]; ok && l.visible(entry) {
+			restored = append(restored, entry)
+		}
+	}
+	if len(removed) > 0 {
+		l.write(&packet.PlayerList{ActionType: packet.PlayerListActionRemove, Entries: removed})
+	}
+	if len(restored) > 0 {
+		l.add(restored)
+	}
+}
+
+// Entries returns the entries owned by the proxy.
+func (l *PlayerList) Entries() []protocol.PlayerListEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := make([]protocol.PlayerListEntry, 0, len(l.entries))
+	for _, entry := range l.entries {
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// SetFilter sets the filter applied to entries sent by the server. Entries for which the filter returns false
+// are hidden. If nil, all entries of the server are shown.
+func (l *PlayerList) SetFilter(filter func(entry protocol.PlayerListEntry) bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	before := make(map[uuid.UUID]bool, len(l.server))
+	for id, entry := range l.server {
+		before[id] = l.visible(entry)
+	}
+	l.filter = filter
+
+	var hidden, shown []protocol.PlayerListEntry
+	for id, entry := range l.server {
+		if after := l.visible(entry); before[id] && !after {
+			hidden = append(hidden, protocol.PlayerListEntry{UUID: id})
+		} else if !before[id] && after {
+			shown = append(shown, entry)
+		}
+	}
+
+	if len(hidden) > 0 {
+		l.write(&packet.PlayerList{ActionType: packet.PlayerListActionRemove, Entries: hidden})
+	}
+	if len(shown) > 0 {
+		l.add(shown)
+	}
+}
+
+// SetSorter sets the function used to sort the entries of the player list, which returns a negative number if
+// entry a is listed before entry b, a positive number if it is listed after it and zero if their order does not
+// matter. The header and footer are always kept at the top and bottom. If nil, entries are listed in the order
+// they were added.
+func (l *PlayerList) SetSorter(sorter func(a, b protocol.PlayerListEntry) int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sorter = sorter
+	if sorter != nil {
+		l.writeOrdered()
+	}
+}
+
+// Sort sorts the entries of the player list again, for example after the values the sorter depends on were
+// changed. It is a no-op if no sorter is set.
+func (l *PlayerList) Sort() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.sorter != nil {
+		l.writeOrdered()
+	}
+}
+
+// SetHeader sets the text shown at the top of the player list. If empty, the header is removed.
+func (l *PlayerList) SetHeader(text string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.header != "" {
+		l.write(&packet.PlayerList{ActionType: packet.PlayerListActionRemove, Entries: []protocol.PlayerListEntry{{UUID: headerUUID}}})
+	}
+	l.header = text
+	if text == "" {
+		return
+	}
+	// The header can only be moved to the top by adding all other entries again after it.
+	entries := append([]protocol.PlayerListEntry{l.textEntry(headerUUID, text)}, l.sorted()...)
+	l.write(&packet.PlayerList{ActionType: packet.PlayerListActionRemove, Entries: removals(entries[1:])})
+	l.write(&packet.PlayerList{ActionType: packet.PlayerListActionAdd, Entries: entries})
+	l.moveFooter()
+}
+
+// SetFooter sets the text shown at the bottom of the player list. If empty, the footer is removed.
+func (l *PlayerList) SetFooter(text string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.footer != "" {
+		l.write(&packet.PlayerList{ActionType: packet.PlayerListActionRemove, Entries: []protocol.PlayerListEntry{{UUID: footerUUID}}})
+	}
+	l.footer = text
+	if text != "" {
+		l.write(&packet.PlayerList{ActionType: packet.PlayerListActionAdd, Entries: []protocol.PlayerListEntry{l.textEntry(footerUUID, text)}})
+	}
+}
+
+// setVanished hides or shows the entry with the UUID passed, whether it is owned by the proxy or by the server,
+// as the player it represents vanished or reappeared.
+func (l *PlayerList) setVanished(id uuid.UUID, vanished bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.vanished[id]; ok == vanished {
+		return
+	}
+	if !vanished {
+		delete(l.vanished, id)
+	}
+	entry, ok := l.entries[id]
+	if !ok {
+		entry, ok = l.server[id]
+		ok = ok && l.visible(entry)
+	}
+	if vanished {
+		l.vanished[id] = struct{}{}
+	}
+	if !ok {
+		return
+	}
+	if vanished {
+		l.write(&packet.PlayerList{ActionType: packet.PlayerListActionRemove, Entries: []protocol.PlayerListEntry{{UUID: id}}})
+		return
+	}
+	l.add([]protocol.PlayerListEntry{entry})
+}
+
+// configure sets the header, footer and sorter of the player list as configured in the Opts passed.
+func (l *PlayerList) configure(opts Opts) {
+	if opts.PlayerListHeader != "" {
+		l.SetHeader(opts.PlayerListHeader)
+	}
+	if opts.PlayerListFooter != "" {
+		l.SetFooter(opts.PlayerListFooter)
+	}
+	if len(opts.PlayerListRanks) > 0 {
+		l.SetSorter(RankSorter(l.s.registry, opts.PlayerListRanks))
+	}
+}
+
+// handleServerPacket records the entries in a PlayerList packet sent by the server and removes entries that
+// are hidden or owned by the proxy from it. It returns the packets that must be written to the client in its
+// place.
+func (l *PlayerList) handleServerPacket(pk *packet.PlayerList) []packet.Packet {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := make([]protocol.PlayerListEntry, 0, len(pk.Entries))
+	for _, entry := range pk.Entries {
+		if pk.ActionType == packet.PlayerListActionAdd {
+			l.server[entry.UUID] = entry
+			if l.visible(entry) {
+				entries = append(entries, entry)
+			}
+			continue
+		}
+		if known, ok := l.server[entry.UUID]; ok {
+			delete(l.server, entry.UUID)
+			if l.visible(known) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	pk.Entries = entries
+	if pk.ActionType == packet.PlayerListActionAdd && l.sorter != nil {
+		return l.orderedPackets()
+	}
+
+	pks := []packet.Packet{pk}
+	if pk.ActionType == packet.PlayerListActionAdd && l.footer != "" {
+		pks = append(pks, l.footerPackets()...)
+	}
+	return pks
+}
+
+// reset resets the player list after a transfer. The entries of the previous server are removed from the
+// client, while the header, entries owned by the proxy and footer are shown again, as the client may have
+// forgotten entries of the proxy with the same UUID as entries of the previous server.
+func (l *PlayerList) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var removed []protocol.PlayerListEntry
+	for id, entry := range l.server {
+		if l.visible(entry) {
+			removed = append(removed, protocol.PlayerListEntry{UUID: id})
+		}
+	}
+	clear(l.server)
+	if len(removed) > 0 {
+		l.write(&packet.PlayerList{ActionType: packet.PlayerListActionRemove, Entries: removed})
+	}
+
+	entries := l.sorted()
+	if l.header != "" {
+		entries = append([]protocol.PlayerListEntry{l.textEntry(headerUUID, l.header)}, entries...)
+	}
+	if l.footer != "" {
+		entries = append(entries, l.textEntry(footerUUID, l.footer))
+	}
+	if len(entries) > 0 {
+		l.write(&packet.PlayerList{ActionType: packet.PlayerListActionAdd, Entries: entries})
+	}
+}
+
+// visible checks if the entry of the server passed is shown to the client. visible must be called with mu
+// held.
+func (l *PlayerList) visible(entry protocol.PlayerListEntry) bool {
+	if entry.UUID == headerUUID || entry.UUID == footerUUID {
+		return false
+	}
+	if _, ok := l.vanished[entry.UUID]; ok {
+		return false
+	}
+	if _, ok := l.entries[entry.UUID]; ok {
+		return false
+	}
+	return l.filter == nil || l.filter(entry)
+}
+
+// shown returns the entries owned by the proxy followed by the visible entries of the server. shown must be
+// called with mu held.
+func (l *PlayerList) shown() []protocol.PlayerListEntry {
+	entries := make([]protocol.PlayerListEntry, 0, len(l.entries)+len(l.server))
+	for id, entry := range l.entries {
+		if _, ok := l.vanished[id]; !ok {
+			entries = append(entries, entry)
+		}
+	}
+	for _, entry := range l.server {
+		if l.visible(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// sorted returns the entries returned by shown, sorted using the sorter if one is set. sorted must be called
+// with mu held.
+func (l *PlayerList) sorted() []protocol.PlayerListEntry {
+	entries := l.shown()
+	if l.sorter != nil {
+		slices.SortStableFunc(entries, l.sorter)
+	}
+	return entries
+}
+
+// add shows the entries passed, which were just added to the player list, on the client. If a sorter is set,
+// all entries are added again in order. add must be called with mu held.
+func (l *PlayerList) add(entries []protocol.PlayerListEntry) {
+	if l.sorter != nil {
+		l.writeOrdered()
+		return
+	}
+	l.write(&packet.PlayerList{ActionType: packet.PlayerListActionAdd, Entries: entries})
+	l.moveFooter()
+}
+
+// writeOrdered writes the packets returned by orderedPackets to the client. writeOrdered must be called with
+// mu held.
+func (l *PlayerList) writeOrdered() {
+	for _, pk := range l.orderedPackets() {
+		l.write(pk)
+	}
+}
+
+// orderedPackets returns the packets needed to list all entries in order: the header, the sorted entries and
+// the footer. orderedPackets must be called with mu held.
+func (l *PlayerList) orderedPackets() []packet.Packet {
+	entries := l.sorted()
+	if l.header != "" {
+		entries = append([]protocol.PlayerListEntry{l.textEntry(headerUUID, l.header)}, entries...)
+	}
+	if l.footer != "" {
+		entries = append(entries, l.textEntry(footerUUID, l.footer))
+	}
+	return []packet.Packet{
+		&packet.PlayerList{ActionType: packet.PlayerListActionRemove, Entries: removals(entries)},
+		&packet.PlayerList{ActionType: packet.PlayerListActionAdd, Entries: entries},
+	}
+}
+
+// moveFooter moves the footer back to the bottom of the player list after entries were added. moveFooter
+// must be called with mu held.
+func (l *PlayerList) moveFooter() {
+	if l.footer == "" {
+		return
+	}
+	for _, pk := range l.footerPackets() {
+		l.write(pk)
+	}
+}
+
+// footerPackets returns the packets needed to move the footer to the bottom of the player list. footerPackets
+// must be called with mu held.
+func (l *PlayerList) footerPackets() []packet.Packet {
+	return []packet.Packet{
+		&packet.PlayerList{ActionType: packet.PlayerListActionRemove, Entries: []protocol.PlayerListEntry{{UUID: footerUUID}}},
+		&packet.PlayerList{ActionType: packet.PlayerListActionAdd, Entries: []protocol.PlayerListEntry{l.textEntry(footerUUID, l.footer)}},
+	}
+}
+
+// textEntry returns an entry with the UUID passed that shows the text passed as its name.
+func (l *PlayerList) textEntry(id uuid.UUID, text string) protocol.PlayerListEntry {
+	return protocol.PlayerListEntry{
+		UUID:           id,
+		EntityUniqueID: l.s.nextEntityID(),
+		Username:       text,
+		Skin:           blankSkin(),
+	}
+}
+
+// write writes a packet to the client of the player list.
+func (l *PlayerList) write(pk packet.Packet) {
+	_ = l.s.Client().WritePacket(pk)
+}
+
+// removals returns entries removing the entries passed from the player list.
+func removals(entries []protocol.PlayerListEntry) []protocol.PlayerListEntry {
+	removed := make([]protocol.PlayerListEntry, len(entries))
+	for i, entry := range entries {
+		removed[i] = protocol.PlayerListEntry{UUID: entry.UUID}
+	}
+	return removed
+}
+
+// PlayerListEntry returns an entry for the player list representing the player of the session, as it would be
+// sent by a server.
+func (s *Session) PlayerListEntry() protocol.PlayerListEntry {
+	identity := s.Client().IdentityData()
+	clientData := s.clientData()
+	id, _ := uuid.Parse(identity.Identity)
+	return protocol.PlayerListEntry{
+		UUID:           id,
+		EntityUniqueID: server.EntityID(identity.XUID),
+		Username:       identity.DisplayName,
+		XUID:           identity.XUID,
+		PlatformChatID: clientData.PlatformOnlineID,
+		BuildPlatform:  int32(clientData.DeviceOS),
+		Skin:           clientSkin(clientData),
+	}
+}
+
+// announce adds the player of the session to the player lists of all sessions on the proxy, and the players
+// of all other sessions to the player list of the session.
+func (s *Session) announce() {
+	entry := s.PlayerListEntry()
+	s.registry.Range(func(other *Session) bool {
+		other.playerList.Add(entry)
+		if other != s {
+			s.playerList.Add(other.PlayerListEntry())
+		}
+		return true
+	})
+}
+
+// unannounce removes the player of the session from the player lists of all other sessions on the proxy.
+func (s *Session) unannounce() {
+	entry := s.PlayerListEntry()
+	s.registry.Range(func(other *Session) bool {
+		if other != s {
+			other.playerList.Remove(entry.UUID)
+		}
+		return true
+	})
+}
+
+// blankSkin returns a transparent skin, used for entries that do not represent a player.
+func blankSkin() protocol.Skin {
+	return protocol.Skin{
+		SkinID:            "spectrum:blank",
+		SkinResourcePatch: []byte(`{"geometry":{"default":"geometry.humanoid.custom"}}`),
+		SkinImageWidth:    64,
+		SkinImageHeight:   32,
+		SkinData:          make([]byte, 64*32*4),
+	}
+}
+
+// clientSkin converts the skin in the client data passed to a protocol.Skin.
+func clientSkin(data login.ClientData) protocol.Skin {
+	skin := protocol.Skin{
+		SkinID:                   data.SkinID,
+		PlayFabID:                data.PlayFabID,
+		SkinImageWidth:           uint32(data.SkinImageWidth),
+		SkinImageHeight:          uint32(data.SkinImageHeight),
+		CapeImageWidth:           uint32(data.CapeImageWidth),
+		CapeImageHeight:          uint32(data.CapeImageHeight),
+		PremiumSkin:              data.PremiumSkin,
+		PersonaSkin:              data.PersonaSkin,
+		PersonaCapeOnClassicSkin: data.CapeOnClassicSkin,
+		CapeID:                   data.CapeID,
+		FullID:                   data.SkinID,
+		SkinColour:               data.SkinColour,
+		ArmSize:                  data.ArmSize,
+		OverrideAppearance:       data.OverrideSkin,
+	}
+	skin.SkinResourcePatch, _ = base64.StdEncoding.DecodeString(data.SkinResourcePatch)
+	skin.SkinData, _ = base64.StdEncoding.DecodeString(data.SkinData)
+	skin.CapeData, _ = base64.StdEncoding.DecodeString(data.CapeData)
+	skin.SkinGeometry, _ = base64.StdEncoding.DecodeString(data.SkinGeometry)
+	skin.AnimationData, _ = base64.StdEncoding.DecodeString(data.SkinAnimationData)
+	skin.GeometryDataEngineVersion, _ = base64.StdEncoding.DecodeString(data.SkinGeometryVersion)
+	for _, anim := range data.AnimatedImageData {
+		image, _ := base64.StdEncoding.DecodeString(anim.Image)
+		skin.Animations = append(skin.Animations, protocol.SkinAnimation{
+			ImageWidth:     uint32(anim.ImageWidth),
+			ImageHeight:    uint32(anim.ImageHeight),
+			ImageData:      image,
+			AnimationType:  uint32(anim.Type),
+			FrameCount:     float32(anim.Frames),
+			ExpressionType: uint32(anim.AnimationExpression),
+		})
+	}
+	for _, piece := range data.PersonaPieces {
+		skin.PersonaPieces = append(skin.PersonaPieces, protocol.PersonaPiece{
+			PieceID:   piece.PieceID,
+			PieceType: piece.PieceType,
+			PackID:    piece.PackID,
+			Default:   piece.Default,
+			ProductID: piece.ProductID,
+		})
+	}
+	for _, tint := range data.PieceTintColours {
+		skin.PieceTintColours = append(skin.PieceTintColours, protocol.PersonaPieceTintColour{
+			PieceType: tint.PieceType,
+			Colours:   tint.Colours[:],
+		})
+	}
+	return skin
+}
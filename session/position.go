@@ -0,0 +1,36 @@
+package session
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// eyeHeight is the height of the eyes of a player above its feet, at which clients report their position.
+const eyeHeight = 1.62
+
+// Position returns the position of the feet of the player of the session, as last reported by the client.
+func (s *Session) Position() mgl32.Vec3 {
+	if pos := s.position.Load(); pos != nil {
+		return *pos
+	}
+	return s.Client().GameData().PlayerPosition.Sub(mgl32.Vec3{0, eyeHeight})
+}
+
+// Rotation returns the pitch and yaw of the player of the session, as last reported by the client.
+func (s *Session) Rotation() (pitch, yaw float32) {
+	if rot := s.rotation.Load(); rot != nil {
+		return rot[0], rot[1]
+	}
+	data := s.Client().GameData()
+	return data.Pitch, data.Yaw
+}
+
+// trackPosition records the position and rotation of the player from the packet passed, sent by the client.
+func (s *Session) trackPosition(pk packet.Packet) {
+	if input, ok := pk.(*packet.PlayerAuthInput); ok {
+		pos := input.Position.Sub(mgl32.Vec3{0, eyeHeight})
+		rot := mgl32.Vec2{input.Pitch, input.Yaw}
+		s.position.Store(&pos)
+		s.rotation.Store(&rot)
+	}
+}
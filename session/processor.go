@@ -1,9 +1,14 @@
 package session
 
 import (
+	"context"
 	"errors"
-	"github.com/spectrum-proxy/spectrum/event"
-	"github.com/spectrum-proxy/spectrum/server/packet"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/capture"
+	"github.com/spectrum-proxy/spectrum/command"
+	"github.com/spectrum-proxy/spectrum/script"
+	"github.com/spectrum-proxy/spectrum/server"
+	packet2 "github.com/spectrum-proxy/spectrum/server/packet"
 	"net"
 	"strings"
 	"time"
@@ -12,42 +17,62 @@ import (
 func handleIncoming(s *Session) {
 	defer s.Close()
 
+	var p *pipeline[serverBatch]
+	if s.opts.PipelineWorkers > 0 {
+		p = newPipeline[serverBatch](s.opts.PipelineWorkers * 4)
+		s.incoming.Store(p)
+		defer p.close()
+		go func() {
+			defer s.Close()
+			err := p.deliver(func(b serverBatch) error {
+				return s.deliverServerPackets(b.conn, b.pks)
+			})
+			if err != nil {
+				s.logger.Error("Failed to write packet to client", "err", err)
+			}
+		}()
+	}
+
 	for {
-		if s.transferring.Load() {
+		if s.paused() {
 			continue
 		}
 
 		server := s.Server()
 		pk, err := server.ReadPacket()
 		if err != nil {
-			if server != s.Server() {
+			if server != s.Server() || s.failover() {
 				continue
 			}
 
 			if !errors.Is(err, net.ErrClosed) {
-				s.logger.Errorf("Failed to read packet from server: %v", err)
+				s.logger.Error("Failed to read packet from server", "err", err)
 			}
 			return
 		}
+		clientboundPackets.Inc()
+		s.capturePacket(capture.Clientbound, pk)
+		s.bufferPacket(capture.Clientbound, pk)
 
 		switch pk := pk.(type) {
-		case *packet.Latency:
-			s.latency = pk.Latency
-		case *packet.Transfer:
-			if err := s.Transfer(pk.Addr); err != nil {
-				s.logger.Errorf("Failed to transfer: %v", err)
+		case *packet2.Latency:
+			s.serverLatency.Store(pk.Latency)
+		case *packet2.Transfer:
+			transfer := func() {
+				if err := s.Transfer(context.Background(), pk.Addr); err != nil {
+					s.logger.Error("Failed to transfer", "err", err)
+				}
 			}
-		default:
-			ctx := event.New()
-			s.handler.HandleIncoming(ctx, pk)
-
-			if ctx.Cancelled() {
+			if s.opts.TransferForwarding {
+				// The server keeps being read from while the session is transferred in the background.
+				go transfer()
 				continue
 			}
-
-			s.tracker.handlePacket(pk)
-			if err := s.clientConn.WritePacket(pk); err != nil {
-				s.logger.Errorf("Failed to write packet to client: %v", err)
+			transfer()
+		case *packet2.ControlRequest:
+			go s.handleControl(server, pk)
+		default:
+			if !s.forwardServerPacket(server, pk, p) {
 				return
 			}
 		}
@@ -57,49 +82,269 @@ func handleIncoming(s *Session) {
 func handleOutgoing(s *Session) {
 	defer s.Close()
 
+	var p *pipeline[[]packet.Packet]
+	if s.opts.PipelineWorkers > 0 {
+		p = newPipeline[[]packet.Packet](s.opts.PipelineWorkers * 4)
+		s.outgoing.Store(p)
+		defer p.close()
+		go func() {
+			defer s.Close()
+			err := p.deliver(func(pks []packet.Packet) error {
+				for _, pk := range pks {
+					if err := s.writeServerPacket(pk); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				s.logger.Error("Failed to write packet to server", "err", err)
+			}
+		}()
+	}
+
 	for {
-		if s.transferring.Load() {
+		if s.paused() {
 			continue
 		}
 
-		pk, err := s.clientConn.ReadPacket()
+		pk, err := s.Client().ReadPacket()
 		if err != nil {
 			if !strings.Contains(err.Error(), "use of closed network connection") {
-				s.logger.Errorf("Failed to read packet from client: %v", err)
+				s.logger.Error("Failed to read packet from client", "err", err)
+			}
+			if s.suspend() {
+				continue
 			}
 			return
 		}
+		s.lastRead.Store(time.Now().UnixNano())
+		if _, ok := pk.(*packet.Disconnect); ok {
+			s.clientLeft.Store(true)
+		}
+		serverboundPackets.Inc()
+		s.capturePacket(capture.Serverbound, pk)
+		s.bufferPacket(capture.Serverbound, pk)
+		if s.handleLatencyResponse(pk) {
+			continue
+		}
+		if !s.handleFlood(pk) {
+			if s.closed.Load() {
+				return
+			}
+			continue
+		}
+
+		pks := s.processClientPacket(pk)
+		if p != nil {
+			if !p.push(pks) {
+				return
+			}
+			continue
+		}
+		for _, pk := range pks {
+			if err := s.writeServerPacket(pk); err != nil {
+				s.logger.Error("Failed to write packet to server", "err", err)
+				return
+			}
+		}
+	}
+}
+
+// paused checks if forwarding packets between the client and the server is paused. This is the case during a
+// transfer, unless forwarding continues during transfers as configured in the Opts of the session.
+func (s *Session) paused() bool {
+	return s.transferring.Load() && !s.opts.TransferForwarding
+}
+
+// serverBatch holds the packets produced from a packet read from a server connection.
+type serverBatch struct {
+	conn *server.Conn
+	pks  []packet.Packet
+}
 
-		ctx := event.New()
-		s.handler.HandleOutgoing(ctx, pk)
+// forwardServerPacket processes a packet read from the server connection passed and writes the resulting
+// packets to the client, either directly or through the pipeline passed if it is not nil. Packets are always
+// processed by the goroutine reading them, so that handlers are called in order. The packet is dropped if the
+// session was switched over to another server after it was read. forwardServerPacket returns false if the
+// packets could not be written and the session must be closed.
+func (s *Session) forwardServerPacket(conn *server.Conn, pk packet.Packet, p *pipeline[serverBatch]) bool {
+	if conn != s.Server() {
+		return true
+	}
+
+	pks := s.processServerPacket(pk)
+	if p != nil {
+		return p.push(serverBatch{conn: conn, pks: pks})
+	}
+	if err := s.deliverServerPackets(conn, pks); err != nil {
+		s.logger.Error("Failed to write packet to client", "err", err)
+		return false
+	}
+	return true
+}
 
-		if ctx.Cancelled() {
+// deliverServerPackets writes packets produced from a packet read from the server connection passed to the
+// client. The packets are dropped if the session was switched over to another server in the meantime. Handlers
+// are not run while deliverMu is locked, so that they may transfer the session synchronously.
+func (s *Session) deliverServerPackets(conn *server.Conn, pks []packet.Packet) error {
+	s.deliverMu.Lock()
+	defer s.deliverMu.Unlock()
+	if conn != s.Server() {
+		return nil
+	}
+	for _, pk := range pks {
+		if err := s.writeClientPacket(pk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processServerPacket passes a packet sent by the server through the handler of the session and returns the
+// packets that should be written to the client in its place. Packets passed through undecoded are returned
+// as is.
+func (s *Session) processServerPacket(pk packet.Packet) []packet.Packet {
+	if _, ok := pk.(*packet.Unknown); ok {
+		return []packet.Packet{pk}
+	}
+
+	ctx := newContext(s)
+	s.handler.HandleServerPacket(ctx, pk)
+	if ctx.Cancelled() {
+		return nil
+	}
+
+	pks := ctx.Packets(pk)
+	filtered := make([]packet.Packet, 0, len(pks))
+	for _, pk := range pks {
+		if !s.filterVanished(pk) {
 			continue
 		}
+		switch pk := pk.(type) {
+		case *packet.AvailableCommands:
+			command.Inject(pk)
+		case *packet.PlayerList:
+			s.reconcileSkins(pk)
+			filtered = append(filtered, s.playerList.handleServerPacket(pk)...)
+			continue
+		case *packet.PlayerSkin:
+			s.reconcileSkins(pk)
+		case *packet.ScriptMessage:
+			if script.Dispatch(s, script.Clientbound, pk.Identifier, pk.Data) {
+				continue
+			}
+		}
+		if s.clampServerViewDistance(pk) {
+			filtered = append(filtered, pk)
+		}
+	}
+	return filtered
+}
 
-		if err := s.Server().WritePacket(pk); err != nil {
-			s.logger.Errorf("Failed to write packet to server: %v", err)
-			return
+// processClientPacket passes a packet sent by the client through the handler of the session and executes any
+// proxy commands. It returns the packets that should be written to the server in its place.
+func (s *Session) processClientPacket(pk packet.Packet) []packet.Packet {
+	ctx := newContext(s)
+	s.handler.HandleClientPacket(ctx, pk)
+	if ctx.Cancelled() {
+		return nil
+	}
+
+	pks := make([]packet.Packet, 0, 1)
+	for _, pk := range ctx.Packets(pk) {
+		s.inspect(pk)
+		if !s.handleMovement(pk) {
+			return nil
 		}
+		if s.handleNPCPacket(pk) || s.handleMenuPacket(pk) {
+			continue
+		}
+		switch pk := pk.(type) {
+		case *packet.CommandRequest:
+			if s.handleCommand(pk.CommandLine) {
+				continue
+			}
+		case *packet.ModalFormResponse:
+			if s.handleFormResponse(pk) {
+				continue
+			}
+		case *packet.PlayerSkin:
+			if s.skin.Load() != nil {
+				continue
+			}
+		case *packet.RequestChunkRadius:
+			s.clampClientViewDistance(pk)
+		case *packet.ScriptMessage:
+			if script.Dispatch(s, script.Serverbound, pk.Identifier, pk.Data) {
+				continue
+			}
+		case *packet.Text:
+			if pk.TextType == packet.TextTypeChat {
+				s.bridgeChat(pk.Message)
+			}
+		}
+		pks = append(pks, pk)
 	}
+	return pks
 }
 
-func handleLatency(s *Session, interval int64) {
-	ticker := time.NewTicker(time.Millisecond * time.Duration(interval))
-	for range ticker.C {
-		if s.transferring.Load() {
+// writeClientPacket translates and tracks the packet passed and writes it to the client. While the session is
+// suspended, the packet is queued instead.
+func (s *Session) writeClientPacket(pk packet.Packet) error {
+	if s.translator != nil {
+		s.translator.translateServerPacket(pk)
+	}
+	after := s.scoreboard.handleServerPacket(pk)
+	after = append(after, s.handleCosmeticPacket(pk)...)
+	if s.ping != nil {
+		s.ping.handleServerPacket(pk)
+	}
+	s.tracker.handlePacket(pk)
+	for _, pk := range append([]packet.Packet{pk}, after...) {
+		if s.enqueue(pk, false) {
+			continue
+		}
+		if s.pacer != nil && s.pacer.pace(pk) {
 			continue
 		}
+		if err := s.writeClient(pk); err != nil {
+			// The client may have lost its connection and is about to be suspended, in which case the packet
+			// is kept to be replayed.
+			if s.opts.ReconnectGrace > 0 && !s.closed.Load() && s.enqueue(pk, true) {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
 
-		err := s.Server().WritePacket(&packet.Latency{
-			Latency:   s.clientConn.Latency().Milliseconds(),
-			Timestamp: time.Now().UnixMilli(),
-		})
-		if err != nil {
-			if !errors.Is(err, net.ErrClosed) {
-				s.logger.Errorf("Failed to send latency packet: %v", err)
+// writeServerPacket tracks and translates the packet passed and writes it to the server. Errors caused by the server
+// connection being replaced are ignored.
+func (s *Session) writeServerPacket(pk packet.Packet) error {
+	if !s.tracker.handleClientPacket(pk) {
+		return nil
+	}
+	if s.translator != nil {
+		s.translator.translateClientPacket(pk)
+	}
+
+	server := s.Server()
+	if err := server.WritePacket(pk); err != nil {
+		if server != s.Server() {
+			// The server connection was replaced by a transfer, so the packet is dropped.
+			return nil
+		}
+		if s.fallback != nil {
+			// The server connection is about to be replaced by the fallback server, so the packet is dropped
+			// rather than closing the session. The error is only logged once per connection.
+			if s.writeFailed.Swap(server) != server {
+				s.logger.Error("Failed to write packet to server", "err", err)
 			}
-			return
+			return nil
 		}
+		return err
 	}
+	return nil
 }
@@ -0,0 +1,51 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"slices"
+	"strings"
+)
+
+// RankKey is the key in the Store of a session holding the rank prefix of its player, such as "[Admin]". It is
+// set through SetRank.
+const RankKey = "spectrum:rank"
+
+// SetRank sets the rank prefix of the player of the session and sorts the player lists of all sessions on the
+// proxy again, so that the player is listed by its new rank.
+func (s *Session) SetRank(rank string) {
+	s.store.Set(RankKey, rank)
+	s.registry.Range(func(other *Session) bool {
+		other.playerList.Sort()
+		return true
+	})
+}
+
+// Rank returns the rank prefix of the player of the session set through SetRank, or an empty string if it has
+// none.
+func (s *Session) Rank() string {
+	rank, _ := s.store.Get(RankKey)
+	str, _ := rank.(string)
+	return str
+}
+
+// RankSorter returns a sorter for PlayerList.SetSorter that lists players by their rank prefix, in the order of
+// the ranks passed, and alphabetically within the same rank. Players with a rank not in ranks are listed after
+// those with one, and entries that are not of players on the proxy, such as those of bots, are listed last.
+func RankSorter(registry *Registry, ranks []string) func(a, b protocol.PlayerListEntry) int {
+	order := func(entry protocol.PlayerListEntry) int {
+		s := registry.GetSession(entry.XUID)
+		if entry.XUID == "" || s == nil {
+			return len(ranks) + 1
+		}
+		if i := slices.Index(ranks, s.Rank()); i != -1 {
+			return i
+		}
+		return len(ranks)
+	}
+	return func(a, b protocol.PlayerListEntry) int {
+		if n := order(a) - order(b); n != 0 {
+			return n
+		}
+		return strings.Compare(strings.ToLower(a.Username), strings.ToLower(b.Username))
+	}
+}
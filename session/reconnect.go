@@ -0,0 +1,140 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"time"
+)
+
+const (
+	// reconnectQueueLimit is the maximum amount of packets sent by the server that are queued while the client
+	// of a session is reconnecting. The session is closed if the server sends more packets than that.
+	reconnectQueueLimit = 8192
+	// dropSilence is the minimum time the client of a session must not have sent any packets for before its
+	// connection was closed for the connection to be considered lost. Connections only time out after several
+	// seconds without any activity, while clients leaving close their connection right away.
+	dropSilence = time.Second * 3
+)
+
+// clientWriter writes packets to the current client connection of a session, which changes when the session
+// is resumed.
+type clientWriter struct {
+	s *Session
+}
+
+// WritePacket ...
+func (w clientWriter) WritePacket(pk packet.Packet) error {
+	return w.s.Client().WritePacket(pk)
+}
+
+// Suspended returns true if the client of the session lost its connection and the session is waiting for it
+// to reconnect.
+func (s *Session) Suspended() bool {
+	return s.suspended.Load()
+}
+
+// Resume resumes a suspended session with the connection of the client that reconnected. The game is started
+// for the new connection and the packets the server sent in the meantime are replayed. Note that the state the
+// client had before it lost its connection, such as chunks and entities, is only restored as far as the server
+// resends it.
+func (s *Session) Resume(conn *minecraft.Conn) error {
+	s.resumeMu.Lock()
+	defer s.resumeMu.Unlock()
+
+	if !s.suspended.Load() || s.closed.Load() {
+		return errors.New("session is not suspended")
+	}
+	if conn.IdentityData().XUID != s.Client().IdentityData().XUID {
+		return errors.New("connection belongs to another player")
+	}
+
+	s.serverMu.RLock()
+	gameData := s.serverConn.GameData()
+	s.serverMu.RUnlock()
+	if err := conn.StartGame(gameData); err != nil {
+		return fmt.Errorf("failed to start game: %v", err)
+	}
+
+	s.queueMu.Lock()
+	s.clientConn.Store(conn)
+	for _, pk := range s.queue {
+		_ = conn.WritePacket(pk)
+	}
+	s.queue = nil
+	s.suspended.Store(false)
+	s.queueMu.Unlock()
+
+	s.sendMetadata(false)
+	s.resumed <- struct{}{}
+	s.logger.Info("Resumed session")
+	return nil
+}
+
+// suspend suspends the session after its client lost its connection, if a reconnect grace period is
+// configured, and waits for the client to reconnect. It returns false if the session should be closed
+// instead, either because the grace period is disabled, because the client left rather than losing its
+// connection or because the client did not reconnect in time.
+func (s *Session) suspend() bool {
+	grace := time.Duration(s.opts.ReconnectGrace) * time.Millisecond
+	if grace <= 0 || s.closed.Load() || !s.dropped() {
+		return false
+	}
+	s.suspended.Store(true)
+	s.logger.Info("Lost connection to client, waiting for it to reconnect", "grace", grace)
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+
+	select {
+	case <-s.resumed:
+		return true
+	case <-s.ctx.Done():
+	case <-timer.C:
+	}
+
+	// The session is no longer suspended with resumeMu locked, so that it cannot be resumed anymore once it
+	// is about to be closed.
+	s.resumeMu.Lock()
+	defer s.resumeMu.Unlock()
+	select {
+	case <-s.resumed:
+		// The session was resumed right as the grace period ended.
+		return true
+	default:
+	}
+	s.suspended.Store(false)
+	if s.ctx.Err() == nil {
+		s.logger.Info("Client did not reconnect in time")
+	}
+	return false
+}
+
+// dropped checks if the client of the session lost its connection unexpectedly, rather than leaving by
+// disconnecting or closing its connection cleanly.
+func (s *Session) dropped() bool {
+	if s.clientLeft.Load() {
+		return false
+	}
+	return time.Since(time.Unix(0, s.lastRead.Load())) >= dropSilence
+}
+
+// enqueue queues the packet passed to be replayed once the session is resumed. It returns false if the session
+// is not suspended and the packet should be written to the client. The session is closed if too many packets
+// are queued.
+func (s *Session) enqueue(pk packet.Packet, force bool) bool {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	if !force && !s.suspended.Load() {
+		return false
+	}
+	if len(s.queue) >= reconnectQueueLimit {
+		s.queue = nil
+		go s.Close()
+		return true
+	}
+	s.queue = append(s.queue, pk)
+	return true
+}
@@ -1,25 +1,42 @@
 package session
 
 import (
+	"github.com/spectrum-proxy/spectrum/server"
+	"sort"
 	"strings"
 	"sync"
 )
 
 type Registry struct {
 	sessions map[string]*Session
+	names    map[string]*Session
+	// servers maps the address of a server to the sessions connected to it, keyed by XUID.
+	servers map[string]map[string]*Session
+	// addrs maps the XUID of a session to the address of the server it is connected to.
+	addrs map[string]string
+	// vanished maps the entity IDs servers assign to vanished players to their session.
+	vanished map[int64]*Session
 	mu       sync.RWMutex
 }
 
 func NewRegistry() *Registry {
 	return &Registry{
 		sessions: make(map[string]*Session),
+		names:    make(map[string]*Session),
+		servers:  make(map[string]map[string]*Session),
+		addrs:    make(map[string]string),
+		vanished: make(map[int64]*Session),
 	}
 }
 
 func (r *Registry) AddSession(xuid string, session *Session) {
+	addr := session.ServerAddr()
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.sessions[xuid] = session
+	r.names[strings.ToLower(session.Client().IdentityData().DisplayName)] = session
+	r.moveSession(xuid, session, addr)
 }
 
 func (r *Registry) GetSession(xuid string) *Session {
@@ -28,31 +45,167 @@ func (r *Registry) GetSession(xuid string) *Session {
 	return r.sessions[xuid]
 }
 
+// GetSessionByUsername looks up a session by the display name of its player. The lookup is case-insensitive.
 func (r *Registry) GetSessionByUsername(username string) *Session {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	return r.names[strings.ToLower(username)]
+}
 
-	for _, session := range r.sessions {
-		if strings.ToLower(session.clientConn.IdentityData().DisplayName) == strings.ToLower(username) {
-			return session
-		}
+// Lookup looks up a session by the XUID or the display name of its player, returning nil if no session was
+// found. It is intended for looking up players identified by external input, such as commands or remote
+// requests.
+func (r *Registry) Lookup(player string) *Session {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if session, ok := r.sessions[player]; ok {
+		return session
 	}
-	return nil
+	return r.names[strings.ToLower(player)]
 }
 
 func (r *Registry) RemoveSession(xuid string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+
+	session, ok := r.sessions[xuid]
+	if !ok {
+		return
+	}
 	delete(r.sessions, xuid)
+	delete(r.vanished, server.EntityID(xuid))
+	r.moveSession(xuid, session, "")
+
+	name := strings.ToLower(session.Client().IdentityData().DisplayName)
+	if r.names[name] == session {
+		delete(r.names, name)
+	}
+}
+
+// Sessions returns a snapshot of all sessions in the registry, sorted by the display name of their players.
+// The slice returned is not affected by sessions added or removed afterwards.
+func (r *Registry) Sessions() []*Session {
+	r.mu.RLock()
+	sessions := make([]*Session, 0, len(r.sessions))
+	for _, session := range r.sessions {
+		sessions = append(sessions, session)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Client().IdentityData().DisplayName < sessions[j].Client().IdentityData().DisplayName
+	})
+	return sessions
 }
 
+// GetSessions returns a snapshot of all sessions in the registry.
+//
+// Deprecated: Use Sessions instead.
 func (r *Registry) GetSessions() []*Session {
+	return r.Sessions()
+}
+
+// Range calls f for every session in the registry until f returns false. Sessions are iterated over a
+// snapshot, so f may safely add or remove sessions.
+func (r *Registry) Range(f func(*Session) bool) {
+	for _, session := range r.Sessions() {
+		if !f(session) {
+			return
+		}
+	}
+}
+
+// Count returns the amount of sessions in the registry.
+func (r *Registry) Count() int {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	return len(r.sessions)
+}
 
-	sessions := make([]*Session, 0)
-	for _, session := range r.sessions {
+// SessionsOn returns a snapshot of all sessions currently connected to the server with the address passed.
+func (r *Registry) SessionsOn(addr string) []*Session {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sessions := make([]*Session, 0, len(r.servers[addr]))
+	for _, session := range r.servers[addr] {
 		sessions = append(sessions, session)
 	}
 	return sessions
 }
+
+// CountOn returns the amount of sessions currently connected to the server with the address passed.
+func (r *Registry) CountOn(addr string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.servers[addr])
+}
+
+// ServerCounts returns the amount of sessions connected to each server, keyed by the address of the server.
+func (r *Registry) ServerCounts() map[string]int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int, len(r.servers))
+	for addr, sessions := range r.servers {
+		counts[addr] = len(sessions)
+	}
+	return counts
+}
+
+// updateServer records that the session with the XUID passed is now connected to the server at addr. It is a
+// no-op if the session is not in the registry.
+func (r *Registry) updateServer(xuid string, addr string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[xuid]
+	if !ok {
+		return
+	}
+	r.moveSession(xuid, session, addr)
+}
+
+// moveSession moves the session from the server it was previously recorded on to the server at addr. If addr
+// is empty, the session is only removed from its previous server. moveSession must be called with mu held.
+func (r *Registry) moveSession(xuid string, session *Session, addr string) {
+	if previous, ok := r.addrs[xuid]; ok {
+		delete(r.servers[previous], xuid)
+		if len(r.servers[previous]) == 0 {
+			delete(r.servers, previous)
+		}
+		delete(r.addrs, xuid)
+	}
+
+	if addr == "" {
+		return
+	}
+
+	if _, ok := r.servers[addr]; !ok {
+		r.servers[addr] = make(map[string]*Session)
+	}
+	r.servers[addr][xuid] = session
+	r.addrs[xuid] = addr
+}
+
+// setVanished records whether the session passed is vanished.
+func (r *Registry) setVanished(session *Session, vanished bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := server.EntityID(session.Client().IdentityData().XUID)
+	if vanished {
+		r.vanished[id] = session
+		return
+	}
+	delete(r.vanished, id)
+}
+
+// vanishedEntity returns the session of the vanished player with the entity ID assigned by servers passed, or
+// nil if no vanished player has that ID.
+func (r *Registry) vanishedEntity(id int64) *Session {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.vanished[id]
+}
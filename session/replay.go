@@ -0,0 +1,80 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/capture"
+	"io"
+	"os"
+	"time"
+)
+
+// maxReplaySize is the maximum size in bytes of the packets kept in memory per session for replays. Older
+// packets are discarded early if the packets of the ReplayWindow exceed it.
+const maxReplaySize = 8 << 20
+
+// replayPackets holds the IDs of the packets kept for replays: those showing what the player did and the world
+// and players around them, leaving out bulky packets such as chunks.
+var replayPackets = map[uint32]struct{}{
+	packet.IDActorEvent:           {},
+	packet.IDAddActor:             {},
+	packet.IDAddPlayer:            {},
+	packet.IDAnimate:              {},
+	packet.IDCommandRequest:       {},
+	packet.IDInteract:             {},
+	packet.IDInventoryTransaction: {},
+	packet.IDItemStackRequest:     {},
+	packet.IDMobEquipment:         {},
+	packet.IDMoveActorAbsolute:    {},
+	packet.IDMoveActorDelta:       {},
+	packet.IDMovePlayer:           {},
+	packet.IDPlayerAction:         {},
+	packet.IDPlayerAuthInput:      {},
+	packet.IDRemoveActor:          {},
+	packet.IDSetActorMotion:       {},
+	packet.IDSetHealth:            {},
+	packet.IDText:                 {},
+	packet.IDUpdateBlock:          {},
+}
+
+// bufferPacket keeps the packet passed in memory for replays if it is a key packet and a ReplayWindow is set.
+func (s *Session) bufferPacket(dir capture.Direction, pk packet.Packet) {
+	if s.recent == nil {
+		return
+	}
+	if _, ok := replayPackets[pk.ID()]; !ok {
+		return
+	}
+	if err := s.recent.Add(dir, time.Now(), pk, s.Server().ShieldID()); err != nil {
+		s.logger.Error("Failed to buffer packet for replay", "err", err)
+	}
+}
+
+// DumpReplay writes the key packets of the most recent gameplay of the session, as configured through the
+// ReplayWindow in its Opts, to the io.Writer passed as a capture. The capture may be replayed to a moderator
+// through Replay. An error is returned if no ReplayWindow is set.
+func (s *Session) DumpReplay(w io.Writer) error {
+	if s.recent == nil {
+		return errors.New("no replay window set")
+	}
+	return s.recent.Dump(w)
+}
+
+// SaveReplay saves the key packets of the most recent gameplay of the session to a file at the path passed,
+// for example when a handler flags an incident. See DumpReplay.
+func (s *Session) SaveReplay(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create replay: %v", err)
+	}
+	if err := s.DumpReplay(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write replay: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write replay: %v", err)
+	}
+	s.logger.Info("Saved replay", "path", path)
+	return nil
+}
@@ -0,0 +1,53 @@
+package session
+
+import (
+	"fmt"
+	"github.com/spectrum-proxy/spectrum/server"
+	"strings"
+)
+
+// checkResourcePacks returns an error if the client did not receive all resource packs required by the server
+// with the name and address passed. Clients can only download resource packs while logging in, so such a
+// server cannot be joined without reconnecting.
+func (s *Session) checkResourcePacks(name, addr string) error {
+	if s.opts.ResourcePacks == nil {
+		return nil
+	}
+
+	received := s.Client().ResourcePacks()
+	missing := s.opts.ResourcePacks.Missing(name, received)
+	if addr != name {
+		missing = append(missing, s.opts.ResourcePacks.Missing(addr, received)...)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("client is missing resource packs required by %v: %v", name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// checkServerPacks checks the resource packs announced by the server passed against the packs the client
+// received when joining the proxy. The negotiation with the server is completed on behalf of the client, so
+// packs the client already has are never sent again. Packs it lacks cannot be delivered either, as clients can
+// only download packs while logging in: an error is returned if the server requires them, and they are logged
+// otherwise.
+func (s *Session) checkServerPacks(conn *server.Conn) error {
+	received := make(map[string]struct{})
+	for _, pack := range s.Client().ResourcePacks() {
+		received[strings.ToLower(pack.UUID()+"_"+pack.Version())] = struct{}{}
+	}
+
+	var missing []string
+	for _, id := range conn.ResourcePacks() {
+		if _, ok := received[strings.ToLower(id)]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	if conn.ResourcePacksRequired() {
+		return fmt.Errorf("server requires resource packs the client did not receive: %v", strings.Join(missing, ", "))
+	}
+	s.logger.Warn("Server uses resource packs the client did not receive", "packs", missing)
+	return nil
+}
@@ -0,0 +1,215 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"sort"
+	"sync"
+)
+
+const (
+	// serverObjectivePrefix is prepended to the names of objectives sent by the server, so that they never
+	// collide with objectives owned by the proxy.
+	serverObjectivePrefix = "spectrum:server:"
+	// proxyObjective is the name of the objective the proxy shows in the sidebar when the server has none.
+	proxyObjective = "spectrum:proxy"
+	// proxyEntryOffset is the first scoreboard entry ID used for lines set by the proxy.
+	proxyEntryOffset = int64(1) << 62
+)
+
+// Scoreboard virtualizes the scoreboards of a session. Objectives sent by the server are scoped so that they
+// never collide with objectives of the proxy. Lines set through the Scoreboard are overlaid on the sidebar of
+// the server, or are shown on a sidebar owned by the proxy if the server does not display one. Lines set on
+// the Scoreboard survive transfers.
+type Scoreboard struct {
+	s  *Session
+	mu sync.Mutex
+
+	title string
+	lines map[int]string
+
+	// sidebar is the scoped name of the server objective displayed in the sidebar, or empty if the server does
+	// not display one.
+	sidebar string
+	// proxyShown specifies if the objective of the proxy is currently shown in the sidebar.
+	proxyShown bool
+}
+
+// newScoreboard returns a new Scoreboard for the session passed.
+func newScoreboard(s *Session) *Scoreboard {
+	return &Scoreboard{s: s, lines: make(map[int]string)}
+}
+
+// SetTitle sets the title of the sidebar shown by the proxy when the server does not display a sidebar.
+func (b *Scoreboard) SetTitle(title string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.title = title
+	if b.proxyShown {
+		b.write(&packet.RemoveObjective{ObjectiveName: proxyObjective})
+		b.proxyShown = false
+		b.showLines()
+	}
+}
+
+// SetLine sets the text of the line at the index passed. Lines are sorted by their index, with the line with
+// the lowest index at the top.
+func (b *Scoreboard) SetLine(index int, text string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.lines[index]; ok {
+		b.write(b.linePacket(packet.ScoreboardActionRemove, index))
+	}
+	b.lines[index] = text
+	if !b.proxyShown && b.sidebar == "" {
+		b.showLines()
+		return
+	}
+	b.write(b.linePacket(packet.ScoreboardActionModify, index))
+}
+
+// RemoveLine removes the line at the index passed.
+func (b *Scoreboard) RemoveLine(index int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.lines[index]; !ok {
+		return
+	}
+	b.write(b.linePacket(packet.ScoreboardActionRemove, index))
+	delete(b.lines, index)
+	if len(b.lines) == 0 && b.proxyShown {
+		b.write(&packet.RemoveObjective{ObjectiveName: proxyObjective})
+		b.proxyShown = false
+	}
+}
+
+// Clear removes all lines set by the proxy.
+func (b *Scoreboard) Clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for index := range b.lines {
+		b.write(b.linePacket(packet.ScoreboardActionRemove, index))
+	}
+	clear(b.lines)
+	if b.proxyShown {
+		b.write(&packet.RemoveObjective{ObjectiveName: proxyObjective})
+		b.proxyShown = false
+	}
+}
+
+// handleServerPacket scopes the objective names in a packet sent by the server. It returns packets that must
+// be written to the client after the packet passed.
+func (b *Scoreboard) handleServerPacket(pk packet.Packet) []packet.Packet {
+	switch pk := pk.(type) {
+	case *packet.SetDisplayObjective:
+		pk.ObjectiveName = serverObjectivePrefix + pk.ObjectiveName
+		if pk.DisplaySlot != packet.ScoreboardSlotSidebar {
+			return nil
+		}
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		var pks []packet.Packet
+		if b.proxyShown {
+			pks = append(pks, &packet.RemoveObjective{ObjectiveName: proxyObjective})
+			b.proxyShown = false
+		}
+		b.sidebar = pk.ObjectiveName
+		for _, index := range b.indices() {
+			pks = append(pks, b.linePacket(packet.ScoreboardActionModify, index))
+		}
+		return pks
+	case *packet.RemoveObjective:
+		pk.ObjectiveName = serverObjectivePrefix + pk.ObjectiveName
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if pk.ObjectiveName == b.sidebar {
+			b.sidebar = ""
+			return b.proxyLines()
+		}
+	case *packet.SetScore:
+		for i := range pk.Entries {
+			pk.Entries[i].ObjectiveName = serverObjectivePrefix + pk.Entries[i].ObjectiveName
+		}
+	}
+	return nil
+}
+
+// reset resets the scoreboard after the server objectives were cleared during a transfer, showing the lines
+// of the proxy on its own sidebar.
+func (b *Scoreboard) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sidebar = ""
+	b.showLines()
+}
+
+// showLines shows the lines of the proxy on its own sidebar. showLines must be called with mu held.
+func (b *Scoreboard) showLines() {
+	for _, pk := range b.proxyLines() {
+		b.write(pk)
+	}
+}
+
+// proxyLines returns the packets needed to show the lines of the proxy on its own sidebar. proxyLines must be
+// called with mu held.
+func (b *Scoreboard) proxyLines() []packet.Packet {
+	if len(b.lines) == 0 {
+		return nil
+	}
+
+	b.proxyShown = true
+	pks := []packet.Packet{&packet.SetDisplayObjective{
+		DisplaySlot:   packet.ScoreboardSlotSidebar,
+		ObjectiveName: proxyObjective,
+		DisplayName:   b.title,
+		CriteriaName:  "dummy",
+		SortOrder:     packet.ScoreboardSortOrderAscending,
+	}}
+	for _, index := range b.indices() {
+		pks = append(pks, b.linePacket(packet.ScoreboardActionModify, index))
+	}
+	return pks
+}
+
+// linePacket returns a SetScore packet modifying or removing the line at the index passed on the objective
+// currently displayed in the sidebar. linePacket must be called with mu held.
+func (b *Scoreboard) linePacket(action byte, index int) *packet.SetScore {
+	objective := b.sidebar
+	if objective == "" {
+		objective = proxyObjective
+	}
+
+	entry := protocol.ScoreboardEntry{
+		EntryID:       proxyEntryOffset + int64(index),
+		ObjectiveName: objective,
+		Score:         int32(index),
+	}
+	if action == packet.ScoreboardActionModify {
+		entry.IdentityType = protocol.ScoreboardIdentityFakePlayer
+		entry.DisplayName = b.lines[index]
+	}
+	return &packet.SetScore{ActionType: action, Entries: []protocol.ScoreboardEntry{entry}}
+}
+
+// indices returns the indices of all lines in ascending order. indices must be called with mu held.
+func (b *Scoreboard) indices() []int {
+	indices := make([]int, 0, len(b.lines))
+	for index := range b.lines {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+// write writes a packet to the client of the session.
+func (b *Scoreboard) write(pk packet.Packet) {
+	_ = b.s.Client().WritePacket(pk)
+}
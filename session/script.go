@@ -0,0 +1,22 @@
+package session
+
+import (
+	"errors"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/script"
+)
+
+// WriteScriptMessage writes a ScriptMessage packet with the channel and data passed to the server if the
+// direction is script.Serverbound, or to the client if it is script.Clientbound. It allows script.Send and
+// script.Request to exchange messages with behaviour packs through the session.
+func (s *Session) WriteScriptMessage(dir script.Direction, channel string, data []byte) error {
+	pk := &packet.ScriptMessage{Identifier: channel, Data: data}
+	if dir == script.Clientbound {
+		return s.Client().WritePacket(pk)
+	}
+	conn := s.Server()
+	if conn == nil {
+		return errors.New("session is not connected to a server")
+	}
+	return conn.WritePacket(pk)
+}
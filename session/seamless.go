@@ -0,0 +1,51 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/session/animation"
+	"slices"
+)
+
+// seamless returns true if the session can be transferred to a server with the game data passed without a
+// dimension change, which is the case if the animation passed is animation.Seamless and both servers are in
+// the same dimension.
+func (s *Session) seamless(anim animation.Animation, serverGameData minecraft.GameData) bool {
+	if _, ok := anim.(*animation.Seamless); !ok {
+		return false
+	}
+	return s.serverConn.GameData().Dimension == serverGameData.Dimension
+}
+
+// sendGameDataDiff sends the client the parts of the game data of the new server that differ from the game
+// data of the old server.
+func (s *Session) sendGameDataDiff(from, to minecraft.GameData) {
+	if from.Difficulty != to.Difficulty {
+		_ = s.Client().WritePacket(&packet.SetDifficulty{
+			Difficulty: uint32(to.Difficulty),
+		})
+	}
+	if from.PlayerGameMode != to.PlayerGameMode {
+		_ = s.Client().WritePacket(&packet.SetPlayerGameType{
+			GameType: to.PlayerGameMode,
+		})
+	}
+	if !slices.Equal(from.GameRules, to.GameRules) {
+		_ = s.Client().WritePacket(&packet.GameRulesChanged{
+			GameRules: to.GameRules,
+		})
+	}
+	if from.Time != to.Time {
+		_ = s.Client().WritePacket(&packet.SetTime{
+			Time: int32(to.Time),
+		})
+	}
+	if from.WorldSpawn != to.WorldSpawn {
+		_ = s.Client().WritePacket(&packet.SetSpawnPosition{
+			SpawnType:     packet.SpawnTypeWorld,
+			Position:      to.WorldSpawn,
+			Dimension:     to.Dimension,
+			SpawnPosition: to.WorldSpawn,
+		})
+	}
+}
@@ -1,212 +1,854 @@
 package session
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"github.com/go-gl/mathgl/mgl32"
 	"github.com/sandertv/gophertunnel/minecraft"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
-	"github.com/spectrum-proxy/spectrum/internal"
+	"github.com/spectrum-proxy/spectrum/audit"
+	"github.com/spectrum-proxy/spectrum/capture"
+	"github.com/spectrum-proxy/spectrum/detection"
+	"github.com/spectrum-proxy/spectrum/event"
 	"github.com/spectrum-proxy/spectrum/server"
 	"github.com/spectrum-proxy/spectrum/session/animation"
+	"github.com/spectrum-proxy/spectrum/session/camera"
+	"github.com/spectrum-proxy/spectrum/session/latency"
+	"log/slog"
+	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
+// startGameTimeout is the maximum time the client of a session may take to spawn after its game was started.
+const startGameTimeout = time.Minute
+
 type Session struct {
-	clientConn *minecraft.Conn
+	clientConn atomic.Pointer[minecraft.Conn]
 
-	serverAddr string
+	// serverAddr holds the address of the server as a string. It is stored atomically rather than guarded by
+	// serverMu, so that it may be read while logging during a transfer.
+	serverAddr atomic.Value
 	serverConn *server.Conn
 	serverMu   sync.RWMutex
+	// writeFailed holds the last server connection that a packet could not be written to.
+	writeFailed atomic.Pointer[server.Conn]
 
-	logger   internal.Logger
+	logger   *slog.Logger
 	registry *Registry
 
-	handler   Handler
-	tracker   *Tracker
-	animation animation.Animation
-
-	latency      int64
+	handler    *handlerChain
+	tracker    *Tracker
+	scoreboard *Scoreboard
+	playerList *PlayerList
+	ping       *pingDisplay
+	camera     *camera.Camera
+	translator *entityTranslator
+	flood      *floodLimiter
+	throttle   *throttler
+	pacer      *chunkPacer
+	center     chunkCenter
+	incoming   atomic.Pointer[pipeline[serverBatch]]
+	outgoing   atomic.Pointer[pipeline[[]packet.Packet]]
+	animation  animation.Animation
+	fallback   FallbackResolver
+	opts       Opts
+
+	forms      map[uint32]FormCallback
+	nextFormID uint32
+	formsMu    sync.Mutex
+
+	entityIDs  atomic.Int64
+	bossBars   map[int64]BossBar
+	bossBarsMu sync.Mutex
+
+	cosmetics   map[int64]Cosmetic
+	viewing     map[string]int64
+	hidden      map[string]int64
+	cosmeticsMu sync.Mutex
+	vanished    atomic.Bool
+
+	npcs   map[int64]NPC
+	npcsMu sync.Mutex
+
+	holograms   map[int64]*shownHologram
+	hologramsMu sync.Mutex
+
+	menu     *openMenu
+	menuMu   sync.Mutex
+	position atomic.Pointer[mgl32.Vec3]
+	rotation atomic.Pointer[mgl32.Vec2]
+
+	spectating atomic.Pointer[spectating]
+
+	analyzers   atomic.Pointer[[]MovementAnalyzer]
+	analyzersMu sync.Mutex
+	detector    *detection.Detector
+
+	joined    time.Time
+	transfers atomic.Uint64
+	skin      atomic.Pointer[protocol.Skin]
+	store     *Store
+	replyTo   atomic.Value
+	capturer  atomic.Pointer[capture.Writer]
+	recent    *capture.Buffer
+	traffic   *traffic
+
+	latency       *latency.Tracker
+	probe         latency.Probe
+	serverLatency atomic.Int64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	suspended atomic.Bool
+	resumed   chan struct{}
+	resumeMu  sync.Mutex
+	queue     []packet.Packet
+	queueMu   sync.Mutex
+	// lastRead holds the time in Unix nanoseconds the last packet was read from the client, and clientLeft
+	// whether the client sent a Disconnect packet.
+	lastRead   atomic.Int64
+	clientLeft atomic.Bool
+
+	ready        chan struct{}
+	joinErr      error
 	once         sync.Once
+	closed       atomic.Bool
 	transferring atomic.Bool
+
+	cancelTransfer   context.CancelCauseFunc
+	cancelTransferMu sync.Mutex
+	// deliverMu is locked while packets of the server are delivered to the client and while the session is
+	// switched over to another server during a transfer, so that packets of the previous server are never
+	// delivered after the switch.
+	deliverMu sync.Mutex
 }
 
-func NewSession(clientConn *minecraft.Conn, logger internal.Logger, registry *Registry, addr string, latencyInterval int64) (s *Session, err error) {
+// NewSession creates a session for the client connection passed and joins the server at the address passed
+// in the background, returning immediately. Session.Wait may be used to find out when joining completed and
+// if it succeeded.
+func NewSession(clientConn *minecraft.Conn, logger *slog.Logger, registry *Registry, addr string, opts Opts) (s *Session, err error) {
 	s = &Session{
-		clientConn: clientConn,
-
-		logger:   logger,
 		registry: registry,
 
-		handler:   NoopHandler{},
+		handler:   newHandlerChain(),
 		tracker:   NewTracker(),
-		animation: &animation.Dimension{},
-		latency:   0,
+		animation: defaultAnimation(opts),
+		fallback:  opts.FallbackResolver,
+		flood:     newFloodLimiter(opts.FloodLimits),
+		opts:      opts,
+		forms:     make(map[uint32]FormCallback),
+		bossBars:  make(map[int64]BossBar),
+		cosmetics: make(map[int64]Cosmetic),
+		viewing:   make(map[string]int64),
+		hidden:    make(map[string]int64),
+		npcs:      make(map[int64]NPC),
+		holograms: make(map[int64]*shownHologram),
+		latency:   latency.NewTracker(opts.LatencySmoothing),
+		resumed:   make(chan struct{}, 1),
+		ready:     make(chan struct{}),
+		store:     newStore(),
+		traffic:   newTraffic(),
+		detector:  detection.New(clientConn.IdentityData(), clientConn.ClientData()),
+		joined:    time.Now(),
+	}
+	s.clientConn.Store(clientConn)
+	s.lastRead.Store(time.Now().UnixNano())
+	if opts.ReplayWindow > 0 {
+		s.recent = capture.NewBuffer(time.Duration(opts.ReplayWindow)*time.Millisecond, maxReplaySize)
+	}
+	analyzers := slices.Clone(opts.MovementAnalyzers)
+	s.analyzers.Store(&analyzers)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.logger = newSessionLogger(s, logger)
+	s.scoreboard = newScoreboard(s)
+	s.playerList = newPlayerList(s)
+	s.throttle = newThrottler(s, opts.Throttle)
+	s.pacer = newChunkPacer(s, opts.ChunkPacing)
+	if opts.PingDisplay {
+		s.ping = newPingDisplay(s)
+	}
+	s.camera = camera.New(clientWriter{s: s})
+	if opts.TranslateEntityIDs {
+		s.translator = newEntityTranslator()
 	}
 
 	go func() {
-		serverConn, err := s.Dial(addr)
-		s.serverAddr = addr
-		s.serverConn = serverConn
+		j, err := s.join(addr)
+		s.joinErr = err
+		close(s.ready)
 		if err != nil {
-			s.Close()
-			s.logger.Errorf("Failed to dial server: %v", err)
 			return
 		}
+		for _, f := range j.started {
+			f(s)
+		}
+	}()
+	return
+}
 
-		if err := clientConn.StartGame(serverConn.GameData()); err != nil {
+// join passes the session through its JoinPipeline, connects it to the server selected and starts the game of
+// its client. The session is closed and an error is returned if any of these fails.
+func (s *Session) join(addr string) (*Join, error) {
+	clientConn, opts := s.Client(), s.opts
+	pipeline := opts.JoinPipeline
+	if pipeline == nil {
+		pipeline = DefaultJoinPipeline()
+	}
+	j := &Join{Session: s, Server: addr}
+	if err := pipeline.run(j); err != nil {
+		var rejection Rejection
+		if errors.As(err, &rejection) {
+			s.Disconnect(rejection.Message)
+		} else {
 			s.Close()
-			s.logger.Errorf("Failed to start game timeout: %v", err)
-			return
 		}
+		s.logger.Info("Rejected session", "err", err)
+		return nil, err
+	}
+	addr = s.resolveServer(j.Server)
 
-		s.sendMetadata(true)
-		for _, pk := range serverConn.ReadDeferred() {
-			_ = clientConn.WritePacket(pk)
-		}
+	serverConn, err := s.Dial(addr)
+	if err != nil && opts.Limbo != "" {
+		s.logger.Warn("Failed to dial server, parking session in limbo", "err", err)
+		addr = opts.Limbo
+		serverConn, err = s.Dial(addr)
+	}
+	if err != nil {
+		s.serverAddr.Store(addr)
+		s.failDial(addr, err)
+		return nil, fmt.Errorf("failed to dial server: %w", err)
+	}
 
-		go handleIncoming(s)
-		go handleOutgoing(s)
-		go handleLatency(s, latencyInterval)
+	// The client is kept on the loading screen until the connection to the server is logged in and the
+	// session is fully set up, so that nothing sent to or by the client observes a half-initialised session.
+	deferred := serverConn.ReadDeferred()
+	s.serverMu.Lock()
+	s.serverAddr.Store(addr)
+	s.serverConn = serverConn
+	s.serverMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(s.ctx, startGameTimeout)
+	defer cancel()
+	if err := clientConn.StartGameContext(ctx, serverConn.GameData()); err != nil {
+		s.Close()
+		s.logger.Error("Failed to start game", "err", err)
+		return nil, fmt.Errorf("failed to start game: %w", err)
+	}
 
-		s.registry.AddSession(clientConn.IdentityData().XUID, s)
-		s.logger.Infof("Successfully started session for %s", clientConn.IdentityData().DisplayName)
-	}()
-	return
+	s.sendMetadata(true)
+	for _, pk := range deferred {
+		_ = clientConn.WritePacket(pk)
+	}
+
+	if s.throttle != nil {
+		go s.throttle.run()
+	}
+	if s.pacer != nil {
+		s.pacer.activate()
+		go s.pacer.run()
+	}
+	go handleIncoming(s)
+	go handleOutgoing(s)
+	go handleLatency(s, opts.LatencyInterval)
+
+	s.registry.AddSession(clientConn.IdentityData().XUID, s)
+	s.playerList.configure(opts)
+	s.hideVanished()
+	if opts.NetworkPlayerList {
+		s.announce()
+	}
+	s.logger.Info("Successfully started session")
+	s.publish(SessionStart{s: s})
+	s.audit(audit.ActionJoin, "", addr, "")
+	return j, nil
 }
 
+// Dial connects to the server at the address passed on behalf of the client of the session, without switching
+// the session over to it.
 func (s *Session) Dial(addr string) (*server.Conn, error) {
-	clientConn := s.clientConn
+	return s.DialContext(context.Background(), addr)
+}
+
+// DialContext connects to the server at the address passed like Dial, aborting if the context passed is done
+// before the connection is logged in.
+func (s *Session) DialContext(ctx context.Context, addr string) (*server.Conn, error) {
+	clientConn := s.Client()
+	compression, _ := serverOption(s, s.opts.Compression, addr)
 	d := server.Dialer{
 		Origin:       clientConn.RemoteAddr().String(),
-		ClientData:   clientConn.ClientData(),
+		ClientData:   s.clientData(),
 		IdentityData: clientConn.IdentityData(),
+		Compression:  compression,
+		Pool:         s.opts.Pools[addr],
+		Breaker:      s.opts.Breaker,
+		DialTimeout:  time.Duration(s.opts.DialTimeout) * time.Millisecond,
+		LoginTimeout: time.Duration(s.opts.LoginTimeout) * time.Millisecond,
+		ChunkRadius:  s.maxViewDistance(addr),
+		Secret:       s.secret(addr),
+	}
+	if name, ok := serverOption(s, s.opts.Transports, addr); ok {
+		if d.Transport, ok = server.TransportByName(name); !ok {
+			return nil, fmt.Errorf("unknown transport %q", name)
+		}
+	}
+
+	conn, err := d.DialContext(ctx, addr)
+	if err != nil {
+		return conn, err
+	}
+	conn.SetPassthrough(s.passthroughFilter())
+	conn.SetTrafficObserver(s.observeTraffic)
+	if err := s.checkServerPacks(conn); err != nil {
+		conn.Close()
+		return nil, err
 	}
-	return d.Dial(addr)
+	return conn, nil
+}
+
+// Transfer transfers the session to the server at addr. If the server cannot be reached after the configured
+// amount of retries, the fallback addresses passed are tried in order until one of them succeeds. Servers may
+// also be referred to by their name in the server registry passed in the Opts of the session. The transfer is
+// aborted if the context passed is done before it completes.
+func (s *Session) Transfer(ctx context.Context, addr string, fallbacks ...string) error {
+	return s.TransferWith(ctx, addr, TransferOptions{Fallbacks: fallbacks})
+}
+
+// TransferWithAnimation transfers the session like Transfer, but plays the Animation passed instead of the
+// animation of the session. If anim is nil, the animation of the session is played.
+func (s *Session) TransferWithAnimation(ctx context.Context, addr string, anim animation.Animation, fallbacks ...string) error {
+	return s.TransferWith(ctx, addr, TransferOptions{Fallbacks: fallbacks, Animation: anim})
 }
 
-func (s *Session) Transfer(addr string) error {
+// TransferWith transfers the session like Transfer, using the TransferOptions passed.
+func (s *Session) TransferWith(ctx context.Context, addr string, opts TransferOptions) (err error) {
+	if opts.OnComplete != nil {
+		defer func() {
+			opts.OnComplete(err)
+		}()
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	if s.closed.Load() {
+		return ErrSessionClosed
+	}
+	select {
+	case <-s.ready:
+		if s.joinErr != nil {
+			return ErrSessionClosed
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.ctx.Done():
+		return ErrSessionClosed
+	}
+	if opts.Animation == nil {
+		opts.Animation = s.animation
+	}
 	if !s.transferring.CompareAndSwap(false, true) {
-		return errors.New("already transferring")
+		return ErrAlreadyTransferring
 	}
+	defer s.transferring.Store(false)
 
-	s.serverMu.Lock()
+	ctx, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(s.ctx, func() {
+		cancel(ErrTransferCancelled)
+	})
+	s.cancelTransferMu.Lock()
+	s.cancelTransfer = cancel
+	s.cancelTransferMu.Unlock()
 	defer func() {
-		s.serverMu.Unlock()
-		s.transferring.Store(false)
+		s.cancelTransferMu.Lock()
+		s.cancelTransfer = nil
+		s.cancelTransferMu.Unlock()
+		stop()
+		cancel(nil)
 	}()
 
-	s.sendMetadata(true)
-	conn, err := s.Dial(addr)
+	eventCtx := event.New()
+	s.handler.OnPreTransfer(eventCtx, &addr)
+	if eventCtx.Cancelled() {
+		return ErrTransferCancelled
+	}
+
+	from := s.ServerAddr()
+	targets := append([]string{addr}, opts.Fallbacks...)
+	s.publish(TransferStart{s: s, From: from, Targets: targets})
+	defer func() {
+		s.publish(TransferEnd{s: s, From: from, To: s.ServerAddr(), Err: err})
+		if err != nil {
+			s.audit(audit.ActionTransfer, from, addr, err.Error())
+		} else {
+			s.audit(audit.ActionTransfer, from, s.ServerAddr(), "")
+		}
+	}()
+
+	for i, name := range targets {
+		if ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+		if i > 0 {
+			// The handler was already called for the primary target above, before the transfer started.
+			eventCtx := event.New()
+			s.handler.OnPreTransfer(eventCtx, &name)
+			if eventCtx.Cancelled() {
+				err = ErrTransferCancelled
+				continue
+			}
+		}
+		target := s.resolveServer(name)
+		if s.opts.Health != nil && !s.opts.Health.Healthy(target) {
+			err = fmt.Errorf("%w: server %v is down", ErrBackendUnreachable, name)
+			s.logger.Error("Failed to transfer session", "target", target, "err", err)
+			continue
+		}
+		if s.full(target) {
+			err = fmt.Errorf("%w: %v", ErrBackendFull, name)
+			s.logger.Error("Failed to transfer session", "target", target, "err", err)
+			continue
+		}
+		if err = s.checkResourcePacks(name, target); err != nil {
+			s.logger.Error("Failed to transfer session", "target", target, "err", err)
+			continue
+		}
+		if err = s.transferRetry(ctx, target, opts); err == nil {
+			transfersTotal.Inc()
+			s.transfers.Add(1)
+			s.registry.updateServer(s.Client().IdentityData().XUID, target)
+			s.handler.OnPostTransfer(from, target)
+			return nil
+		}
+		transferFailuresTotal.Inc()
+		s.logger.Error("Failed to transfer session", "target", target, "err", err)
+	}
+	return err
+}
+
+// transferRetry attempts to transfer the session to addr, retrying up to the configured amount of times with
+// an exponential backoff between attempts.
+func (s *Session) transferRetry(ctx context.Context, addr string, opts TransferOptions) (err error) {
+	backoff := time.Duration(s.opts.TransferBackoff) * time.Millisecond
+	for attempt := 0; attempt <= s.opts.TransferRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return context.Cause(ctx)
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		if err = s.transfer(ctx, addr, opts); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+	}
+	return err
+}
+
+// CancelTransfer cancels the transfer of the session currently in progress, closing the connection to the
+// server transferred to if it was already opened. The session stays on the server it was on before, and the
+// transfer returns ErrTransferCancelled. CancelTransfer returns false if no transfer was in progress.
+func (s *Session) CancelTransfer() bool {
+	s.cancelTransferMu.Lock()
+	defer s.cancelTransferMu.Unlock()
+	if s.cancelTransfer == nil {
+		return false
+	}
+	s.cancelTransfer(ErrTransferCancelled)
+	return true
+}
+
+// transfer connects to the server at addr and switches the session over to it. The connection is opened
+// before serverMu is locked, so that the server the session is on remains readable and packets keep being
+// forwarded to it while dialing. Only switching over happens with serverMu locked. Transfers never overlap, as
+// they are guarded by the transferring flag of the session.
+func (s *Session) transfer(ctx context.Context, addr string, opts TransferOptions) error {
+	freeze := !s.opts.TransferForwarding
+	if freeze {
+		s.sendMetadata(true)
+	}
+	conn, err := s.DialContext(ctx, addr)
 	if err != nil {
-		s.sendMetadata(false)
-		s.logger.Errorf("Failed to dial server: %v", err)
+		if freeze {
+			s.sendMetadata(false)
+		}
 		return err
 	}
 
-	serverGameData := conn.GameData()
-	s.animation.Play(s.clientConn, serverGameData)
-
-	chunk := emptyChunk(serverGameData.Dimension)
-	pos := serverGameData.PlayerPosition
-	chunkX := int32(pos.X()) >> 4
-	chunkZ := int32(pos.Z()) >> 4
-	for x := chunkX - 4; x <= chunkX+4; x++ {
-		for z := chunkZ - 4; z <= chunkZ+4; z++ {
-			_ = s.clientConn.WritePacket(&packet.LevelChunk{
-				Dimension:     packet.DimensionNether,
-				Position:      protocol.ChunkPos{x, z},
-				SubChunkCount: 1,
-				RawPayload:    chunk,
-			})
+	s.deliverMu.Lock()
+	defer s.deliverMu.Unlock()
+	s.serverMu.Lock()
+	defer s.serverMu.Unlock()
+	if ctx.Err() != nil || s.closed.Load() {
+		// The transfer was cancelled or the session was closed after the connection was logged in, so it is
+		// closed again before the client is switched over to it.
+		conn.Close()
+		if freeze {
+			s.sendMetadata(false)
+		}
+		if err = context.Cause(ctx); err == nil {
+			err = ErrTransferCancelled
 		}
+		return err
 	}
 
+	serverGameData := conn.GameData()
+	anim := opts.Animation
+	seamless := opts.SkipChunkFlush || s.seamless(anim, serverGameData)
+	preserve := opts.PreserveEntities && seamless
+	if s.pacer != nil {
+		s.pacer.reset()
+		s.pacer.activate()
+	}
+	if s.throttle != nil {
+		s.throttle.reset()
+	}
+	s.center.reset()
+	s.tracker.clearContainers(s)
+	if !seamless && !opts.Silent {
+		anim.Play(s.Client(), serverGameData)
+	}
+
+	s.CloseMenu()
+	s.StopSpectating()
 	s.tracker.clearEffects(s)
+	s.clearCosmetics(!preserve)
 	s.tracker.clearEntities(s)
+	if s.translator != nil {
+		s.translator.reset()
+	}
+	s.tracker.clearInventories(s)
 	s.tracker.clearBossBars(s)
 	s.tracker.clearPlayers(s)
 	s.tracker.clearScoreboards(s)
+	s.scoreboard.reset()
+	s.playerList.reset()
+	if s.ping != nil {
+		s.ping.reset()
+	}
 
-	_ = s.clientConn.WritePacket(&packet.MovePlayer{
+	_ = s.Client().WritePacket(&packet.MovePlayer{
 		EntityRuntimeID: serverGameData.EntityRuntimeID,
 		Position:        serverGameData.PlayerPosition,
 		Pitch:           serverGameData.Pitch,
 		Yaw:             serverGameData.Yaw,
 		Mode:            packet.MoveModeReset,
 	})
+	pos := serverGameData.PlayerPosition.Sub(mgl32.Vec3{0, eyeHeight})
+	rot := mgl32.Vec2{serverGameData.Pitch, serverGameData.Yaw}
+	s.position.Store(&pos)
+	s.rotation.Store(&rot)
 
-	_ = s.clientConn.WritePacket(&packet.LevelEvent{
+	_ = s.Client().WritePacket(&packet.LevelEvent{
 		EventType: packet.LevelEventStopRaining,
 		EventData: 10_000,
 	})
-	_ = s.clientConn.WritePacket(&packet.LevelEvent{
+	_ = s.Client().WritePacket(&packet.LevelEvent{
 		EventType: packet.LevelEventStopThunderstorm,
 	})
 
-	_ = s.clientConn.WritePacket(&packet.SetDifficulty{
-		Difficulty: uint32(serverGameData.Difficulty),
-	})
-	_ = s.clientConn.WritePacket(&packet.SetPlayerGameType{
-		GameType: serverGameData.PlayerGameMode,
-	})
-
-	_ = s.clientConn.WritePacket(&packet.GameRulesChanged{
-		GameRules: serverGameData.GameRules,
-	})
-
-	s.animation.Clear(s.clientConn, serverGameData)
+	if seamless {
+		s.sendGameDataDiff(s.serverConn.GameData(), serverGameData)
+	} else {
+		_ = s.Client().WritePacket(&packet.SetDifficulty{
+			Difficulty: uint32(serverGameData.Difficulty),
+		})
+		_ = s.Client().WritePacket(&packet.SetPlayerGameType{
+			GameType: serverGameData.PlayerGameMode,
+		})
+
+		_ = s.Client().WritePacket(&packet.GameRulesChanged{
+			GameRules: serverGameData.GameRules,
+		})
+
+		if !opts.Silent {
+			anim.Clear(s.Client(), serverGameData)
+		}
+	}
+	if !preserve {
+		s.resendBossBars()
+		s.resendCosmetics()
+		s.resendNPCs()
+		s.resendHolograms()
+	}
+	s.tracker.cancelForms(s.serverConn)
 	s.serverConn.Close()
 
-	s.serverAddr = addr
+	s.serverAddr.Store(addr)
 	s.serverConn = conn
+	s.sendLatency(conn)
 
 	for _, pk := range conn.ReadDeferred() {
-		_ = s.clientConn.WritePacket(pk)
+		_ = s.Client().WritePacket(pk)
 	}
-	s.logger.Debugf("Transferred session for %s to %s", s.clientConn.IdentityData().DisplayName, addr)
+	s.logger.Debug("Transferred session", "target", addr)
 	return nil
 }
 
+// Camera returns the Camera of the session, which may be used to control the camera of the client.
+func (s *Session) Camera() *camera.Camera {
+	return s.camera
+}
+
+// defaultAnimation returns the Animation played when transferring a session created with the Opts passed.
+func defaultAnimation(opts Opts) animation.Animation {
+	if opts.SeamlessTransfer {
+		return &animation.Seamless{}
+	}
+	if anim, ok := animation.ByName(opts.Animation); ok {
+		return anim
+	}
+	return &animation.Dimension{}
+}
+
+// Scoreboard returns the Scoreboard of the session, which may be used to show lines in the sidebar of the
+// client independently of the server it is connected to.
+func (s *Session) Scoreboard() *Scoreboard {
+	return s.scoreboard
+}
+
+// PlayerList returns the PlayerList of the session, which may be used to add entries to the player list of the
+// client independently of the server it is connected to, or to hide entries sent by the server.
+func (s *Session) PlayerList() *PlayerList {
+	return s.playerList
+}
+
+// SetHandler detaches all handlers of the session and attaches the Handler passed with PriorityNormal.
 func (s *Session) SetHandler(handler Handler) {
-	s.handler = handler
+	s.handler.set(handler)
+}
+
+// Attach attaches the Handler passed to the session, in addition to the handlers already attached. Handlers
+// are called in order of descending priority, and in the order they were attached if their priorities are
+// equal. Once a handler cancels an event, handlers after it are not called for that event.
+func (s *Session) Attach(handler Handler, priority int) {
+	s.handler.attach(handler, priority)
+}
+
+// Detach detaches the Handler passed from the session. The handler is compared with the attached handlers
+// using ==, so it should be a comparable value such as a pointer. Detach returns false if the handler was not
+// attached.
+func (s *Session) Detach(handler Handler) bool {
+	return s.handler.detach(handler)
 }
 
 func (s *Session) SetAnimation(animation animation.Animation) {
 	s.animation = animation
 }
 
+// SetFallbackResolver sets the FallbackResolver used to find a new server for the session when the
+// connection to its current server is lost. Passing nil disables automatic failover.
+func (s *Session) SetFallbackResolver(resolver FallbackResolver) {
+	s.fallback = resolver
+}
+
+// Disconnect disconnects the client of the session with the message passed, which is translated to the locale
+// of the session if it is the key of a translated message, and closes the session.
 func (s *Session) Disconnect(message string) {
-	_ = s.clientConn.WritePacket(&packet.Disconnect{
-		Message: message,
+	s.audit(audit.ActionKick, s.ServerAddr(), "", message)
+	_ = s.Client().WritePacket(&packet.Disconnect{
+		Message: s.localize(message),
 	})
 	s.Close()
 }
 
+// Ready returns a channel that is closed once the session joined its first server and the game of its client
+// was started, or once joining failed. Transfers requested before then wait for the channel to be closed. Wait
+// may be used to find out if joining succeeded.
+func (s *Session) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Wait blocks until the session joined its first server and the game of its client was started. If joining
+// failed, for example because the session was rejected by its JoinPipeline or the server could not be reached,
+// the error is returned and the session is closed. Wait returns the error of the context passed if it is done
+// first.
+func (s *Session) Wait(ctx context.Context) error {
+	select {
+	case <-s.ready:
+		return s.joinErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Region returns the region of the player, as resolved when the player connected. It is empty if no region
+// could be resolved.
+func (s *Session) Region() string {
+	return s.opts.Region
+}
+
+// Protocol returns the ID of the protocol the client of the session logged in with.
+func (s *Session) Protocol() int32 {
+	if s.opts.Protocol == 0 {
+		return protocol.CurrentProtocol
+	}
+	return s.opts.Protocol
+}
+
+// Client returns the connection of the client of the session. The connection changes if the session is
+// resumed after the client reconnected.
+func (s *Session) Client() *minecraft.Conn {
+	return s.clientConn.Load()
+}
+
 func (s *Session) Server() *server.Conn {
 	s.serverMu.RLock()
 	defer s.serverMu.RUnlock()
 	return s.serverConn
 }
 
+// Joined returns the time the session was created.
+func (s *Session) Joined() time.Time {
+	return s.joined
+}
+
+// ServerAddr returns the address of the server the session is currently connected to.
+func (s *Session) ServerAddr() string {
+	addr, _ := s.serverAddr.Load().(string)
+	return addr
+}
+
+// Latency returns the latency of the player in milliseconds, which is the smoothed latency between the client
+// and the proxy, measured using the configured latency.Strategy, plus the latency between the proxy and the
+// server as reported by the server.
 func (s *Session) Latency() int64 {
-	return s.clientConn.Latency().Milliseconds() + s.latency
+	return s.latency.Latency().Milliseconds() + s.serverLatency.Load()
+}
+
+// Jitter returns the jitter of the latency between the client and the proxy in milliseconds.
+func (s *Session) Jitter() int64 {
+	return s.latency.Jitter().Milliseconds()
+}
+
+// LatencyP99 returns the 99th percentile of the recent latency samples between the client and the proxy in
+// milliseconds.
+func (s *Session) LatencyP99() int64 {
+	return s.latency.P99().Milliseconds()
 }
 
 func (s *Session) Close() {
 	s.once.Do(func() {
-		_ = s.clientConn.Close()
+		s.closed.Store(true)
+		s.cancel()
+		_ = s.Client().Close()
 
 		if s.serverConn != nil {
 			s.serverConn.Close()
 		}
 
-		identity := s.clientConn.IdentityData()
+		s.hideCosmetics()
+		identity := s.Client().IdentityData()
 		s.registry.RemoveSession(identity.XUID)
-		s.logger.Infof("Closed session for %s", identity.DisplayName)
+		if s.opts.NetworkPlayerList {
+			s.unannounce()
+		}
+		s.logger.Info("Closed session")
+		s.publish(SessionClose{s: s})
+		s.audit(audit.ActionQuit, s.ServerAddr(), "", "")
 	})
 }
 
+// Context returns the context of the session, which is cancelled when the session is closed. It may be used
+// to bind work started for the session to its lifetime.
+func (s *Session) Context() context.Context {
+	return s.ctx
+}
+
+// resolveServer resolves the name of a server to its address using the server registry of the session. If the
+// name is not known, it is returned as is.
+func (s *Session) resolveServer(name string) string {
+	if s.opts.Servers == nil {
+		return name
+	}
+	return s.opts.Servers.Resolve(name)
+}
+
+// secret returns the secret the Connect packet sent to the server at the address passed is signed with, or
+// nil if it is not signed.
+func (s *Session) secret(addr string) []byte {
+	if s.opts.Secrets == nil {
+		return nil
+	}
+	return s.opts.Secrets.Secret(addr)
+}
+
+// full checks if the server at the address passed is at its capacity. The session itself does not count
+// towards the players of the server if it is already connected to it.
+func (s *Session) full(addr string) bool {
+	capacity, ok := serverOption(s, s.opts.Capacities, addr)
+	if !ok || capacity <= 0 {
+		return false
+	}
+	count := s.registry.CountOn(addr)
+	if s.ServerAddr() == addr {
+		count--
+	}
+	return count >= capacity
+}
+
+// serverOption looks up the option configured for the server at the address passed in the map passed.
+// Options may be configured by the address or by the name of the server.
+func serverOption[T any](s *Session, options map[string]T, addr string) (T, bool) {
+	if option, ok := options[addr]; ok {
+		return option, true
+	}
+	if s.opts.Servers != nil {
+		for _, name := range s.opts.Servers.Names() {
+			if option, ok := options[name]; ok && s.opts.Servers.Resolve(name) == addr {
+				return option, true
+			}
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// failover moves the session to the server resolved by its FallbackResolver after the connection to its
+// current server was lost. If no fallback server is available, the session is parked in limbo if enabled. It
+// returns true if the session was transferred successfully.
+func (s *Session) failover() bool {
+	if s.closed.Load() {
+		return false
+	}
+	if s.fallback != nil {
+		addr, err := s.fallback.Resolve(s)
+		if err != nil {
+			s.logger.Error("Failed to resolve fallback server", "err", err)
+		} else if err := s.Transfer(context.Background(), addr); err != nil {
+			s.logger.Error("Failed to transfer to fallback server", "err", err)
+		} else {
+			s.logger.Info("Moved session to fallback server", "target", addr)
+			return true
+		}
+	}
+	return s.park()
+}
+
+// park moves the session to the limbo server, if enabled. It returns true if the session was transferred
+// successfully.
+func (s *Session) park() bool {
+	if s.opts.Limbo == "" || s.ServerAddr() == s.opts.Limbo {
+		return false
+	}
+	if err := s.Transfer(context.Background(), s.opts.Limbo); err != nil {
+		s.logger.Error("Failed to park session in limbo", "err", err)
+		return false
+	}
+	s.logger.Info("Parked session in limbo")
+	return true
+}
+
 func (s *Session) sendMetadata(noAI bool) {
 	metadata := protocol.NewEntityMetadata()
 	if noAI {
@@ -214,8 +856,8 @@ func (s *Session) sendMetadata(noAI bool) {
 	}
 	metadata.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagBreathing)
 	metadata.SetFlag(protocol.EntityDataKeyFlags, protocol.EntityDataFlagHasGravity)
-	_ = s.clientConn.WritePacket(&packet.SetActorData{
-		EntityRuntimeID: s.clientConn.GameData().EntityRuntimeID,
+	_ = s.Client().WritePacket(&packet.SetActorData{
+		EntityRuntimeID: s.Client().GameData().EntityRuntimeID,
 		EntityMetadata:  metadata,
 	})
 }
@@ -0,0 +1,128 @@
+package session
+
+import (
+	"encoding/base64"
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// SetSkin overrides the skin of the player of the session network-wide. The skin is shown to the player and
+// all other players on the proxy, replaces the skin the server sends for the player, and is forwarded to the
+// servers the player is transferred to in place of the skin the player joined with. Skins the client sends
+// while its skin is overridden are dropped.
+func (s *Session) SetSkin(skin protocol.Skin) {
+	s.skin.Store(&skin)
+	s.broadcastSkin(skin)
+}
+
+// ResetSkin restores the skin the player joined with, removing the skin set through SetSkin.
+func (s *Session) ResetSkin() {
+	if s.skin.Swap(nil) == nil {
+		return
+	}
+	s.broadcastSkin(clientSkin(s.clientData()))
+}
+
+// Skin returns the skin of the player of the session, which is the skin set through SetSkin, if any, or the
+// skin the player joined with otherwise.
+func (s *Session) Skin() protocol.Skin {
+	if skin := s.skin.Load(); skin != nil {
+		return *skin
+	}
+	return clientSkin(s.clientData())
+}
+
+// broadcastSkin shows the skin passed on the player of the session to the player and all other players on
+// the proxy, and updates it on the server the player is connected to.
+func (s *Session) broadcastSkin(skin protocol.Skin) {
+	id, _ := uuid.Parse(s.Client().IdentityData().Identity)
+	pk := &packet.PlayerSkin{UUID: id, Skin: skin, NewSkinName: skin.SkinID}
+	if conn := s.Server(); conn != nil {
+		_ = conn.WritePacket(pk)
+	}
+	s.registry.Range(func(other *Session) bool {
+		_ = other.Client().WritePacket(pk)
+		return true
+	})
+}
+
+// reconcileSkins replaces the skins in the packet passed, sent by the server, of players whose skin was
+// overridden through SetSkin.
+func (s *Session) reconcileSkins(pk packet.Packet) {
+	switch pk := pk.(type) {
+	case *packet.PlayerList:
+		if pk.ActionType != packet.PlayerListActionAdd {
+			return
+		}
+		for i, entry := range pk.Entries {
+			if other := s.registry.GetSession(entry.XUID); other != nil {
+				if skin := other.skin.Load(); skin != nil {
+					pk.Entries[i].Skin = *skin
+				}
+			}
+		}
+	case *packet.PlayerSkin:
+		s.registry.Range(func(other *Session) bool {
+			if other.Client().IdentityData().Identity != pk.UUID.String() {
+				return true
+			}
+			if skin := other.skin.Load(); skin != nil {
+				pk.Skin = *skin
+			}
+			return false
+		})
+	}
+}
+
+// applySkin sets the skin fields of the ClientData passed to the skin passed, so that the skin is forwarded to
+// servers as if the client joined with it.
+func applySkin(data *login.ClientData, skin protocol.Skin) {
+	data.SkinID = skin.SkinID
+	data.PlayFabID = skin.PlayFabID
+	data.SkinImageWidth, data.SkinImageHeight = int(skin.SkinImageWidth), int(skin.SkinImageHeight)
+	data.CapeImageWidth, data.CapeImageHeight = int(skin.CapeImageWidth), int(skin.CapeImageHeight)
+	data.PremiumSkin = skin.PremiumSkin
+	data.PersonaSkin = skin.PersonaSkin
+	data.CapeOnClassicSkin = skin.PersonaCapeOnClassicSkin
+	data.CapeID = skin.CapeID
+	data.SkinColour = skin.SkinColour
+	data.ArmSize = skin.ArmSize
+	data.OverrideSkin = skin.OverrideAppearance
+
+	data.SkinResourcePatch = base64.StdEncoding.EncodeToString(skin.SkinResourcePatch)
+	data.SkinData = base64.StdEncoding.EncodeToString(skin.SkinData)
+	data.CapeData = base64.StdEncoding.EncodeToString(skin.CapeData)
+	data.SkinGeometry = base64.StdEncoding.EncodeToString(skin.SkinGeometry)
+	data.SkinAnimationData = base64.StdEncoding.EncodeToString(skin.AnimationData)
+	data.SkinGeometryVersion = base64.StdEncoding.EncodeToString(skin.GeometryDataEngineVersion)
+
+	data.AnimatedImageData = make([]login.SkinAnimation, 0, len(skin.Animations))
+	for _, anim := range skin.Animations {
+		data.AnimatedImageData = append(data.AnimatedImageData, login.SkinAnimation{
+			Image:               base64.StdEncoding.EncodeToString(anim.ImageData),
+			ImageWidth:          int(anim.ImageWidth),
+			ImageHeight:         int(anim.ImageHeight),
+			Type:                int(anim.AnimationType),
+			Frames:              float64(anim.FrameCount),
+			AnimationExpression: int(anim.ExpressionType),
+		})
+	}
+	data.PersonaPieces = make([]login.PersonaPiece, 0, len(skin.PersonaPieces))
+	for _, piece := range skin.PersonaPieces {
+		data.PersonaPieces = append(data.PersonaPieces, login.PersonaPiece{
+			PieceID:   piece.PieceID,
+			PieceType: piece.PieceType,
+			PackID:    piece.PackID,
+			Default:   piece.Default,
+			ProductID: piece.ProductID,
+		})
+	}
+	data.PieceTintColours = make([]login.PersonaPieceTintColour, 0, len(skin.PieceTintColours))
+	for _, tint := range skin.PieceTintColours {
+		colours := login.PersonaPieceTintColour{PieceType: tint.PieceType}
+		copy(colours.Colours[:], tint.Colours)
+		data.PieceTintColours = append(data.PieceTintColours, colours)
+	}
+}
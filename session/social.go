@@ -0,0 +1,90 @@
+package session
+
+import (
+	"fmt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/event"
+)
+
+// SendPrivateMessage sends a private message from the player of the session to the player of the target
+// session passed, which may be connected to any server. Both players may reply to the message afterwards
+// through Reply. An error is returned if the message could not be delivered.
+func (s *Session) SendPrivateMessage(target *Session, message string) error {
+	if target == s {
+		return fmt.Errorf("you cannot message yourself")
+	}
+	from, to := s.Client().IdentityData(), target.Client().IdentityData()
+	if target.ignores(from.XUID) {
+		return fmt.Errorf("%v is not accepting your messages", to.DisplayName)
+	}
+
+	ctx := event.New()
+	s.handler.HandlePrivateMessage(ctx, target, &message)
+	if ctx.Cancelled() {
+		return fmt.Errorf("your message to %v was blocked", to.DisplayName)
+	}
+
+	s.replyTo.Store(to.XUID)
+	target.replyTo.Store(from.XUID)
+	s.sendRawText(fmt.Sprintf("§7[me -> %v]§r %v", to.DisplayName, message))
+	target.sendRawText(fmt.Sprintf("§7[%v -> me]§r %v", from.DisplayName, message))
+	return nil
+}
+
+// Reply sends a private message to the player the player of the session last exchanged a private message
+// with. An error is returned if there is no such player or they are no longer online.
+func (s *Session) Reply(message string) error {
+	xuid, _ := s.replyTo.Load().(string)
+	if xuid == "" {
+		return fmt.Errorf("you have nobody to reply to")
+	}
+	target := s.registry.GetSession(xuid)
+	if target == nil {
+		return fmt.Errorf("the player you last messaged is no longer online")
+	}
+	return s.SendPrivateMessage(target, message)
+}
+
+// Ignore makes the player of the session ignore the player with the XUID passed, so that private messages
+// and bridged chat messages of that player are no longer shown to them.
+func (s *Session) Ignore(xuid string) error {
+	if s.opts.Ignores == nil {
+		return fmt.Errorf("ignoring players is not enabled")
+	}
+	return s.opts.Ignores.Ignore(s.Client().IdentityData().XUID, xuid)
+}
+
+// Unignore makes the player of the session stop ignoring the player with the XUID passed.
+func (s *Session) Unignore(xuid string) error {
+	if s.opts.Ignores == nil {
+		return fmt.Errorf("ignoring players is not enabled")
+	}
+	return s.opts.Ignores.Unignore(s.Client().IdentityData().XUID, xuid)
+}
+
+// Ignored returns the XUIDs of the players ignored by the player of the session.
+func (s *Session) Ignored() ([]string, error) {
+	if s.opts.Ignores == nil {
+		return nil, nil
+	}
+	return s.opts.Ignores.Ignored(s.Client().IdentityData().XUID)
+}
+
+// ignores checks if the player of the session ignores the player with the XUID passed. Errors of the store
+// are logged and treated as the player not being ignored.
+func (s *Session) ignores(xuid string) bool {
+	if s.opts.Ignores == nil {
+		return false
+	}
+	ok, err := s.opts.Ignores.Ignores(s.Client().IdentityData().XUID, xuid)
+	if err != nil {
+		s.logger.Error("Failed to look up ignored players", "err", err)
+	}
+	return ok
+}
+
+// sendRawText writes a raw Text packet with the message passed to the client. Unlike SendMessage, the message
+// is never translated, so it is used for messages written by players.
+func (s *Session) sendRawText(message string) {
+	_ = s.Client().WritePacket(&packet.Text{TextType: packet.TextTypeRaw, Message: message})
+}
@@ -0,0 +1,55 @@
+package session
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// PlaySound plays the sound with the name passed, such as "random.orb", to the player at its own position, at
+// full volume and normal pitch.
+func (s *Session) PlaySound(name string) {
+	s.PlaySoundAt(name, s.Position(), 1, 1)
+}
+
+// PlaySoundAt plays the sound with the name passed to the player at the position passed, with the volume and
+// pitch passed. A volume and pitch of 1 play the sound as is.
+func (s *Session) PlaySoundAt(name string, pos mgl32.Vec3, volume, pitch float32) {
+	_ = s.Client().WritePacket(&packet.PlaySound{
+		SoundName: name,
+		Position:  pos,
+		Volume:    volume,
+		Pitch:     pitch,
+	})
+}
+
+// StopSound stops the sound with the name passed for the player. If the name is empty, all sounds are stopped.
+func (s *Session) StopSound(name string) {
+	_ = s.Client().WritePacket(&packet.StopSound{SoundName: name, StopAll: name == ""})
+}
+
+// PlayLevelSound plays the built-in sound event passed, such as packet.SoundEventLevelUp, to the player at its
+// own position.
+func (s *Session) PlayLevelSound(event uint32) {
+	_ = s.Client().WritePacket(&packet.LevelSoundEvent{
+		SoundType:             event,
+		Position:              s.Position(),
+		ExtraData:             -1,
+		EntityType:            ":",
+		DisableRelativeVolume: true,
+	})
+}
+
+// SpawnParticle spawns the particle effect with the name passed, such as "minecraft:heart_particle", for the
+// player at the position passed.
+func (s *Session) SpawnParticle(name string, pos mgl32.Vec3) {
+	dimension := s.Client().GameData().Dimension
+	if conn := s.Server(); conn != nil {
+		dimension = conn.GameData().Dimension
+	}
+	_ = s.Client().WritePacket(&packet.SpawnParticleEffect{
+		Dimension:      byte(dimension),
+		EntityUniqueID: -1,
+		Position:       pos,
+		ParticleName:   name,
+	})
+}
@@ -0,0 +1,112 @@
+package session
+
+import (
+	"errors"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"time"
+)
+
+const (
+	// spectateInterval is the interval at which the camera of a spectating session follows its target.
+	spectateInterval = time.Second / 20
+	// spectateTeleportDistance is the distance in blocks from its target beyond which a spectating player is
+	// teleported to the target, so that the client has the chunks around the target loaded.
+	spectateTeleportDistance = 48
+)
+
+// spectating holds the target of a spectating session and the channel closed to stop following it.
+type spectating struct {
+	target *Session
+	stop   chan struct{}
+}
+
+// Spectate attaches the camera of the player of the session to the player of the target session, which must be
+// on the same server, showing the world through the eyes of the target until StopSpectating is called or
+// either session is transferred. The player is teleported to the target when it is too far away for the
+// chunks around the target to be loaded. The player should typically be vanished through SetVanished while
+// spectating.
+func (s *Session) Spectate(target *Session) error {
+	if target == s {
+		return errors.New("cannot spectate self")
+	}
+	if target.ServerAddr() != s.ServerAddr() {
+		return errors.New("target is on another server")
+	}
+
+	spec := &spectating{target: target, stop: make(chan struct{})}
+	if previous := s.spectating.Swap(spec); previous != nil {
+		close(previous.stop)
+	}
+	s.teleport(target.Position())
+	go s.followTarget(spec)
+	return nil
+}
+
+// StopSpectating detaches the camera of the player from the target set through Spectate, returning it to the
+// player. It is a no-op if the player is not spectating.
+func (s *Session) StopSpectating() {
+	if spec := s.spectating.Swap(nil); spec != nil {
+		close(spec.stop)
+		s.camera.Reset()
+	}
+}
+
+// Spectating returns the session the player of the session is spectating, or nil if it is not spectating.
+func (s *Session) Spectating() *Session {
+	if spec := s.spectating.Load(); spec != nil {
+		return spec.target
+	}
+	return nil
+}
+
+// followTarget moves the camera of the client to the eyes of the target of the spectating passed at every
+// spectateInterval, until spectating is stopped, either session is closed or the target leaves the server.
+func (s *Session) followTarget(spec *spectating) {
+	t := time.NewTicker(spectateInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-spec.stop:
+			return
+		case <-s.ctx.Done():
+			return
+		case <-spec.target.Context().Done():
+			s.stopSpectating(spec)
+			return
+		case <-t.C:
+			if spec.target.ServerAddr() != s.ServerAddr() {
+				s.stopSpectating(spec)
+				return
+			}
+			pos := spec.target.Position()
+			if pos.Sub(s.Position()).Len() > spectateTeleportDistance {
+				s.teleport(pos)
+			}
+			pitch, yaw := spec.target.Rotation()
+			s.camera.EaseTo(pos.Add(mgl32.Vec3{0, eyeHeight}), pitch, yaw, protocol.EasingTypeLinear, spectateInterval)
+		}
+	}
+}
+
+// stopSpectating stops the spectating passed if it is still the current spectating of the session.
+func (s *Session) stopSpectating(spec *spectating) {
+	if s.spectating.CompareAndSwap(spec, nil) {
+		s.camera.Reset()
+	}
+}
+
+// teleport teleports the player of the session to the position passed on the client.
+func (s *Session) teleport(pos mgl32.Vec3) {
+	pitch, yaw := s.Rotation()
+	_ = s.Client().WritePacket(&packet.MovePlayer{
+		EntityRuntimeID: s.Client().GameData().EntityRuntimeID,
+		Position:        pos.Add(mgl32.Vec3{0, eyeHeight}),
+		Pitch:           pitch,
+		Yaw:             yaw,
+		HeadYaw:         yaw,
+		Mode:            packet.MoveModeTeleport,
+	})
+}
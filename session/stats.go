@@ -0,0 +1,36 @@
+package session
+
+import "time"
+
+// Stats holds statistics of a session since it was created.
+type Stats struct {
+	// Joined is the time the session was created.
+	Joined time.Time `json:"joined"`
+	// Transfers is the amount of successful transfers of the session.
+	Transfers uint64 `json:"transfers"`
+	// Server is the address of the server the session is currently connected to.
+	Server string `json:"server"`
+	// Clientbound and Serverbound hold the total amount of packets and bytes sent by servers to the client and
+	// by the client to servers respectively.
+	Clientbound PacketTraffic `json:"clientbound"`
+	Serverbound PacketTraffic `json:"serverbound"`
+}
+
+// Uptime returns the time that passed since the session was created.
+func (s Stats) Uptime() time.Duration {
+	return time.Since(s.Joined)
+}
+
+// Stats returns statistics of the session since it was created.
+func (s *Session) Stats() Stats {
+	load := func(c *counter) PacketTraffic {
+		return PacketTraffic{Count: c.count.Load(), Bytes: c.bytes.Load()}
+	}
+	return Stats{
+		Joined:      s.joined,
+		Transfers:   s.transfers.Load(),
+		Server:      s.ServerAddr(),
+		Clientbound: load(&s.traffic.total[0]),
+		Serverbound: load(&s.traffic.total[1]),
+	}
+}
@@ -0,0 +1,105 @@
+package session
+
+import (
+	"maps"
+	"sort"
+	"sync"
+)
+
+// Store holds arbitrary values attached to a session by handlers and plugins, such as the party of a player
+// or its selected kit. Values are kept for the lifetime of the session, so they survive transfers. A Store may
+// be used concurrently.
+type Store struct {
+	mu     sync.RWMutex
+	values map[string]any
+}
+
+// newStore returns a new empty Store.
+func newStore() *Store {
+	return &Store{values: make(map[string]any)}
+}
+
+// Set sets the value of the key passed, replacing any previous value.
+func (st *Store) Set(key string, value any) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.values[key] = value
+}
+
+// Get returns the value of the key passed and whether it was set.
+func (st *Store) Get(key string) (any, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	value, ok := st.values[key]
+	return value, ok
+}
+
+// Delete removes the value of the key passed.
+func (st *Store) Delete(key string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	delete(st.values, key)
+}
+
+// Keys returns the keys that have a value set, sorted alphabetically.
+func (st *Store) Keys() []string {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	keys := make([]string, 0, len(st.values))
+	for key := range st.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// All returns a copy of all values in the store, keyed by their key.
+func (st *Store) All() map[string]any {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return maps.Clone(st.values)
+}
+
+// String returns the value of the key passed if it is a string.
+func (st *Store) String(key string) (string, bool) {
+	return Load[string](st, key)
+}
+
+// Int returns the value of the key passed if it is an int.
+func (st *Store) Int(key string) (int, bool) {
+	return Load[int](st, key)
+}
+
+// Bool returns the value of the key passed if it is a bool.
+func (st *Store) Bool(key string) (bool, bool) {
+	return Load[bool](st, key)
+}
+
+// Load returns the value of the key passed in the Store if it is set and of type T.
+func Load[T any](st *Store, key string) (T, bool) {
+	value, ok := st.Get(key)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	v, ok := value.(T)
+	return v, ok
+}
+
+// Update atomically replaces the value of the key passed in the Store with the value returned by f, which is
+// passed the current value, or the zero value of T if none of type T is set.
+func Update[T any](st *Store, key string, f func(T) T) T {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	current, _ := st.values[key].(T)
+	value := f(current)
+	st.values[key] = value
+	return value
+}
+
+// Store returns the Store holding values attached to the session by handlers and plugins.
+func (s *Session) Store() *Store {
+	return s.store
+}
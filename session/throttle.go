@@ -0,0 +1,141 @@
+package session
+
+import (
+	"bytes"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/internal"
+	"sync/atomic"
+	"time"
+)
+
+// throttleQueueSize is the amount of packets queued by a throttler before writing further packets blocks.
+const throttleQueueSize = 1024
+
+// Throttle limits the bandwidth used to send packets to a client, so that a server sending large amounts of
+// data, such as chunks, does not saturate the connection of clients on slow networks. Movement and other
+// latency sensitive packets bypass the limit while no other packets are waiting to be sent.
+type Throttle struct {
+	// Rate is the amount of bytes per second sent to the client. If zero, the bandwidth is not limited.
+	Rate int `yaml:"rate"`
+	// Burst is the amount of bytes that may be sent at once before Rate applies.
+	Burst int `yaml:"burst"`
+}
+
+// priorityPackets holds the IDs of packets that are sent to the client immediately, regardless of the
+// Throttle of a session, unless other packets are still queued. They are queued behind those packets, as they
+// might refer to them, such as a MoveActorAbsolute packet for an entity added by a queued AddActor packet.
+var priorityPackets = map[uint32]struct{}{
+	packet.IDMovePlayer:                  {},
+	packet.IDMoveActorAbsolute:           {},
+	packet.IDMoveActorDelta:              {},
+	packet.IDSetActorMotion:              {},
+	packet.IDCorrectPlayerMovePrediction: {},
+	packet.IDNetworkStackLatency:         {},
+	packet.IDPlayStatus:                  {},
+	packet.IDDisconnect:                  {},
+	packet.IDTransfer:                    {},
+	packet.IDText:                        {},
+}
+
+// throttler queues packets to the client of a session that exceed its Throttle and writes them once the
+// bandwidth allows it.
+type throttler struct {
+	s      *Session
+	bucket *internal.TokenBucket
+	queue  chan throttledPacket
+
+	// pending is the amount of packets queued or being written by run.
+	pending atomic.Int32
+	// generation is incremented by reset, so that run drops packets it took from the queue before it was reset.
+	generation atomic.Uint64
+}
+
+// throttledPacket is a packet queued by a throttler, along with the generation of the throttler it was queued
+// in.
+type throttledPacket struct {
+	pk         packet.Packet
+	generation uint64
+}
+
+// newThrottler returns a throttler for the session passed, or nil if the Throttle passed does not limit the
+// bandwidth.
+func newThrottler(s *Session, t Throttle) *throttler {
+	if t.Rate <= 0 {
+		return nil
+	}
+	return &throttler{
+		s:      s,
+		bucket: internal.NewTokenBucket(float64(t.Rate), max(t.Burst, t.Rate)),
+		queue:  make(chan throttledPacket, throttleQueueSize),
+	}
+}
+
+// write writes the packet passed to the client if it is a priority packet and no packets are pending, or
+// queues it otherwise. It blocks if the queue is full.
+func (t *throttler) write(pk packet.Packet) error {
+	if _, ok := priorityPackets[pk.ID()]; ok && t.pending.Load() == 0 {
+		return t.s.Client().WritePacket(pk)
+	}
+	t.pending.Add(1)
+	select {
+	case t.queue <- throttledPacket{pk: pk, generation: t.generation.Load()}:
+		return nil
+	case <-t.s.ctx.Done():
+		t.pending.Add(-1)
+		return t.s.ctx.Err()
+	}
+}
+
+// reset drops all queued packets, which no longer apply after the session was transferred to another server.
+func (t *throttler) reset() {
+	t.generation.Add(1)
+	for {
+		select {
+		case <-t.queue:
+			t.pending.Add(-1)
+		default:
+			return
+		}
+	}
+}
+
+// run writes the queued packets to the client at the rate of the throttle until the session is closed.
+func (t *throttler) run() {
+	buf := bytes.NewBuffer(make([]byte, 0, 4096))
+	for {
+		select {
+		case <-t.s.ctx.Done():
+			return
+		case queued := <-t.queue:
+			if delay := t.bucket.Reserve(packetSize(buf, queued.pk)); delay > 0 {
+				time.Sleep(delay)
+			}
+			if queued.generation == t.generation.Load() {
+				if err := t.s.Client().WritePacket(queued.pk); err != nil && !t.s.Suspended() {
+					t.s.logger.Error("Failed to write packet to client", "err", err)
+					return
+				}
+			}
+			t.pending.Add(-1)
+		}
+	}
+}
+
+// writeClient writes the packet passed to the client, subject to the Throttle of the session.
+func (s *Session) writeClient(pk packet.Packet) error {
+	if s.throttle != nil {
+		return s.throttle.write(pk)
+	}
+	return s.Client().WritePacket(pk)
+}
+
+// packetSize returns the size of the packet passed once encoded, using the buffer passed to encode it.
+func packetSize(buf *bytes.Buffer, pk packet.Packet) int {
+	if pk, ok := pk.(*packet.Unknown); ok {
+		return len(pk.Payload) + 1
+	}
+	buf.Reset()
+	pk.Marshal(protocol.NewWriter(buf, 0))
+	return buf.Len() + 1
+}
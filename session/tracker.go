@@ -7,27 +7,52 @@ import (
 	"github.com/scylladb/go-set/i32set"
 	"github.com/scylladb/go-set/i64set"
 	"github.com/scylladb/go-set/strset"
+	"github.com/scylladb/go-set/u32set"
+	"github.com/scylladb/go-set/u8set"
+	"github.com/spectrum-proxy/spectrum/server"
+	"sync"
 )
 
 type Tracker struct {
+	// mu guards all state of the tracker except for forms. Packets are tracked by the goroutine delivering
+	// packets to the client, while the state is cleared by transfers.
+	mu sync.Mutex
+
 	bossBars    *i64set.Set
+	containers  *u8set.Set
 	effects     *i32set.Set
 	entities    *i64set.Set
 	players     *b16set.Set
 	scoreboards *strset.Set
+	// inventories maps the window IDs of inventories the client received the content of to their size.
+	inventories map[uint32]uint32
+
+	// forms holds the IDs of forms sent by the current server that the client has not yet responded to, and
+	// staleForms holds those of forms sent by previous servers. forms are accessed by both packet loops, so
+	// they are guarded by formsMu.
+	forms      *u32set.Set
+	staleForms *u32set.Set
+	formsMu    sync.Mutex
 }
 
 func NewTracker() *Tracker {
 	return &Tracker{
 		bossBars:    i64set.New(),
+		containers:  u8set.New(),
 		effects:     i32set.New(),
 		entities:    i64set.New(),
 		players:     b16set.New(),
 		scoreboards: strset.New(),
+		inventories: make(map[uint32]uint32),
+		forms:       u32set.New(),
+		staleForms:  u32set.New(),
 	}
 }
 
 func (t *Tracker) handlePacket(pk packet.Packet) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	switch pk := pk.(type) {
 	case *packet.AddActor:
 		t.entities.Add(pk.EntityUniqueID)
@@ -39,6 +64,19 @@ func (t *Tracker) handlePacket(pk packet.Packet) {
 		t.entities.Add(pk.AbilityData.EntityUniqueID)
 	case *packet.BossEvent:
 		t.bossBars.Add(pk.BossEntityUniqueID)
+	case *packet.ContainerClose:
+		t.containers.Remove(pk.WindowID)
+	case *packet.ContainerOpen:
+		t.containers.Add(pk.WindowID)
+	case *packet.InventoryContent:
+		t.inventories[pk.WindowID] = max(t.inventories[pk.WindowID], uint32(len(pk.Content)))
+	case *packet.InventorySlot:
+		t.inventories[pk.WindowID] = max(t.inventories[pk.WindowID], pk.Slot+1)
+	case *packet.ModalFormRequest:
+		t.formsMu.Lock()
+		t.forms.Add(pk.FormID)
+		t.staleForms.Remove(pk.FormID)
+		t.formsMu.Unlock()
 	case *packet.MobEffect:
 		if pk.Operation == packet.MobEffectAdd {
 			t.effects.Add(pk.EffectType)
@@ -62,20 +100,94 @@ func (t *Tracker) handlePacket(pk packet.Packet) {
 	}
 }
 
+// handleClientPacket tracks a packet sent by the client. It returns false if the packet must not be forwarded
+// to the server, which is the case for responses to forms sent by a previous server.
+func (t *Tracker) handleClientPacket(pk packet.Packet) bool {
+	if pk, ok := pk.(*packet.ModalFormResponse); ok {
+		t.formsMu.Lock()
+		defer t.formsMu.Unlock()
+
+		if t.staleForms.Has(pk.FormID) {
+			t.staleForms.Remove(pk.FormID)
+			return false
+		}
+		t.forms.Remove(pk.FormID)
+	}
+	return true
+}
+
+// cancelForms responds to all forms the client has not yet responded to as if the client closed them, so that
+// the server passed does not wait for a response. Responses to these forms sent by the client later are
+// dropped.
+func (t *Tracker) cancelForms(conn *server.Conn) {
+	t.formsMu.Lock()
+	defer t.formsMu.Unlock()
+
+	t.forms.Each(func(i uint32) bool {
+		_ = conn.WritePacket(&packet.ModalFormResponse{
+			FormID:       i,
+			CancelReason: protocol.Option[uint8](packet.ModalFormCancelReasonUserClosed),
+		})
+		t.staleForms.Add(i)
+		return true
+	})
+	t.forms.Clear()
+}
+
 func (t *Tracker) clearBossBars(s *Session) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	t.bossBars.Each(func(i int64) bool {
-		_ = s.clientConn.WritePacket(&packet.BossEvent{
+		_ = s.Client().WritePacket(&packet.BossEvent{
 			BossEntityUniqueID: i,
+			EventType:          packet.BossEventHide,
 		})
 		return true
 	})
 	t.bossBars.Clear()
 }
 
+func (t *Tracker) clearContainers(s *Session) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.containers.Each(func(i byte) bool {
+		_ = s.Client().WritePacket(&packet.ContainerClose{
+			WindowID:   i,
+			ServerSide: true,
+		})
+		return true
+	})
+	t.containers.Clear()
+}
+
+func (t *Tracker) clearInventories(s *Session) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for windowID, size := range t.inventories {
+		_ = s.Client().WritePacket(&packet.InventoryContent{
+			WindowID: windowID,
+			Content:  make([]protocol.ItemInstance, size),
+		})
+	}
+	clear(t.inventories)
+
+	_ = s.Client().WritePacket(&packet.PlayerHotBar{
+		SelectedHotBarSlot: 0,
+		WindowID:           protocol.WindowIDInventory,
+		SelectHotBarSlot:   true,
+	})
+}
+
 func (t *Tracker) clearEffects(s *Session) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	t.effects.Each(func(i int32) bool {
-		_ = s.clientConn.WritePacket(&packet.MobEffect{
-			EntityRuntimeID: s.clientConn.GameData().EntityRuntimeID,
+		_ = s.Client().WritePacket(&packet.MobEffect{
+			EntityRuntimeID: s.Client().GameData().EntityRuntimeID,
 			EffectType:      i,
 			Operation:       packet.MobEffectRemove,
 		})
@@ -85,8 +197,11 @@ func (t *Tracker) clearEffects(s *Session) {
 }
 
 func (t *Tracker) clearEntities(s *Session) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	t.entities.Each(func(i int64) bool {
-		_ = s.clientConn.WritePacket(&packet.RemoveActor{
+		_ = s.Client().WritePacket(&packet.RemoveActor{
 			EntityUniqueID: i,
 		})
 		return true
@@ -95,6 +210,9 @@ func (t *Tracker) clearEntities(s *Session) {
 }
 
 func (t *Tracker) clearPlayers(s *Session) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	entries := make([]protocol.PlayerListEntry, 0)
 	t.players.Each(func(i [16]byte) bool {
 		entries = append(entries, protocol.PlayerListEntry{
@@ -104,15 +222,18 @@ func (t *Tracker) clearPlayers(s *Session) {
 	})
 	t.players.Clear()
 
-	_ = s.clientConn.WritePacket(&packet.PlayerList{
+	_ = s.Client().WritePacket(&packet.PlayerList{
 		ActionType: packet.PlayerListActionRemove,
 		Entries:    entries,
 	})
 }
 
 func (t *Tracker) clearScoreboards(s *Session) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	t.scoreboards.Each(func(i string) bool {
-		_ = s.clientConn.WritePacket(&packet.RemoveObjective{
+		_ = s.Client().WritePacket(&packet.RemoveObjective{
 			ObjectiveName: i,
 		})
 		return true
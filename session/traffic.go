@@ -0,0 +1,131 @@
+package session
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// PacketTraffic holds the amount of packets with a specific ID sent in one direction and their total size in
+// bytes before compression.
+type PacketTraffic struct {
+	Count uint64 `json:"count"`
+	Bytes uint64 `json:"bytes"`
+}
+
+// TrafficStats holds the traffic per packet ID of a session or of all sessions, split by direction. Only
+// packets exchanged with servers are counted, including packets passed through undecoded.
+type TrafficStats struct {
+	Clientbound map[uint32]PacketTraffic `json:"clientbound"`
+	Serverbound map[uint32]PacketTraffic `json:"serverbound"`
+}
+
+// trafficIDs is the amount of packet IDs counted without locking in the global traffic, which is updated by
+// all sessions. Packets with higher IDs are rare and are counted in a map guarded by a mutex instead.
+const trafficIDs = 1024
+
+// counter counts the packets of a single ID.
+type counter struct {
+	count, bytes atomic.Uint64
+}
+
+// traffic counts the traffic per packet ID in both directions, indexed by 0 for clientbound and 1 for
+// serverbound packets. It may be used concurrently.
+type traffic struct {
+	// fixed holds the counters of packet IDs below trafficIDs. It is nil for the traffic of single sessions,
+	// which is not updated concurrently often enough to be worth the memory.
+	fixed *[2][trafficIDs]counter
+	// total holds the counters of all packets regardless of their ID.
+	total [2]counter
+
+	mu    sync.Mutex
+	other [2]map[uint32]*counter
+}
+
+// globalTraffic counts the traffic of all sessions.
+var globalTraffic = func() *traffic {
+	t := newTraffic()
+	t.fixed = &[2][trafficIDs]counter{}
+	return t
+}()
+
+// newTraffic returns a new traffic without any packets counted.
+func newTraffic() *traffic {
+	return &traffic{other: [2]map[uint32]*counter{make(map[uint32]*counter), make(map[uint32]*counter)}}
+}
+
+// observe counts a packet with the ID and size passed, sent to the server if serverbound is true.
+func (t *traffic) observe(id uint32, size int, serverbound bool) {
+	c := t.counter(id, serverbound)
+	c.count.Add(1)
+	c.bytes.Add(uint64(size))
+
+	total := &t.total[0]
+	if serverbound {
+		total = &t.total[1]
+	}
+	total.count.Add(1)
+	total.bytes.Add(uint64(size))
+}
+
+// counter returns the counter of the packet ID and direction passed.
+func (t *traffic) counter(id uint32, serverbound bool) *counter {
+	dir := 0
+	if serverbound {
+		dir = 1
+	}
+	if t.fixed != nil && id < trafficIDs {
+		return &t.fixed[dir][id]
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c, ok := t.other[dir][id]
+	if !ok {
+		c = &counter{}
+		t.other[dir][id] = c
+	}
+	return c
+}
+
+// stats returns a snapshot of the traffic counted.
+func (t *traffic) stats() TrafficStats {
+	stats := TrafficStats{Clientbound: make(map[uint32]PacketTraffic), Serverbound: make(map[uint32]PacketTraffic)}
+	add := func(m map[uint32]PacketTraffic, id uint32, c *counter) {
+		if count := c.count.Load(); count > 0 {
+			m[id] = PacketTraffic{Count: count, Bytes: c.bytes.Load()}
+		}
+	}
+	if t.fixed != nil {
+		for id := range t.fixed[0] {
+			add(stats.Clientbound, uint32(id), &t.fixed[0][id])
+			add(stats.Serverbound, uint32(id), &t.fixed[1][id])
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, c := range t.other[0] {
+		add(stats.Clientbound, id, c)
+	}
+	for id, c := range t.other[1] {
+		add(stats.Serverbound, id, c)
+	}
+	return stats
+}
+
+// Traffic returns the traffic per packet ID of the session since it was created.
+func (s *Session) Traffic() TrafficStats {
+	return s.traffic.stats()
+}
+
+// GlobalTraffic returns the traffic per packet ID of all sessions since the proxy was started.
+func GlobalTraffic() TrafficStats {
+	return globalTraffic.stats()
+}
+
+// observeTraffic counts a packet exchanged with the server of the session. It is set as the
+// server.TrafficObserver of every connection to a server.
+func (s *Session) observeTraffic(id uint32, size int, serverbound bool) {
+	s.traffic.observe(id, size, serverbound)
+	globalTraffic.observe(id, size, serverbound)
+}
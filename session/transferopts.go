@@ -0,0 +1,30 @@
+package session
+
+import (
+	"github.com/spectrum-proxy/spectrum/session/animation"
+	"time"
+)
+
+// TransferOptions holds options of a transfer performed through Session.TransferWith. The zero value transfers
+// the session like Session.Transfer.
+type TransferOptions struct {
+	// Fallbacks holds the names or addresses of the servers tried in order if the server transferred to
+	// cannot be reached.
+	Fallbacks []string
+	// Animation is the Animation played during the transfer. If nil, the animation of the session is played.
+	Animation animation.Animation
+	// SkipChunkFlush transfers the session without a dimension change, keeping the world of the previous
+	// server on the client until the new server overwrites it, as if the animation.Seamless were played.
+	SkipChunkFlush bool
+	// PreserveEntities keeps the entities owned by the proxy, such as NPCs, holograms, cosmetics and boss
+	// bars, spawned on the client rather than removing and spawning them again. It only has an effect if the
+	// transfer happens without a dimension change, after which the client has forgotten all entities.
+	PreserveEntities bool
+	// Timeout is the maximum time the transfer may take, including retries and fallbacks. If zero, the
+	// transfer is only limited by the context passed.
+	Timeout time.Duration
+	// Silent transfers the session without playing any Animation.
+	Silent bool
+	// OnComplete is called with the result of the transfer once it completed or failed. It may be nil.
+	OnComplete func(err error)
+}
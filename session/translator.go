@@ -0,0 +1,239 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"sync"
+)
+
+// translatedEntityOffset is the first entity ID handed out to the client for entities of a server. It lies
+// outside the range of IDs assigned by servers and below proxyEntityOffset, so that translated IDs collide
+// neither with IDs that were not translated nor with entities owned by the proxy.
+const translatedEntityOffset = int64(1) << 61
+
+// translatedPackets holds the IDs of packets sent by the server that reference entities and are translated
+// when entity translation is enabled.
+var translatedPackets = []uint32{
+	packet.IDActorEvent,
+	packet.IDAnimate,
+	packet.IDMobArmourEquipment,
+	packet.IDMobEquipment,
+	packet.IDMotionPredictionHints,
+	packet.IDMoveActorAbsolute,
+	packet.IDMoveActorDelta,
+	packet.IDMovePlayer,
+	packet.IDSetActorData,
+	packet.IDSetActorLink,
+	packet.IDSetActorMotion,
+	packet.IDTakeItemActor,
+	packet.IDUpdateAbilities,
+	packet.IDUpdateAttributes,
+}
+
+// translatedEntity holds the IDs a server assigned to an entity.
+type translatedEntity struct {
+	uniqueID  int64
+	runtimeID uint64
+}
+
+// entityTranslator translates the IDs servers assign to entities to IDs that are stable for the client. Every
+// entity spawned by a server is handed a new ID that is never reused, so that servers assigning overlapping
+// IDs cannot corrupt entities on the client when a session is transferred between them. The entity of the
+// player itself is never translated, as its IDs are the same on every server.
+type entityTranslator struct {
+	mu sync.Mutex
+
+	next     int64
+	uniques  map[int64]int64
+	runtimes map[uint64]int64
+	entities map[int64]translatedEntity
+}
+
+// newEntityTranslator returns a new entityTranslator without any entities.
+func newEntityTranslator() *entityTranslator {
+	return &entityTranslator{
+		next:     translatedEntityOffset,
+		uniques:  make(map[int64]int64),
+		runtimes: make(map[uint64]int64),
+		entities: make(map[int64]translatedEntity),
+	}
+}
+
+// reset forgets all entities of the current server. It is called when the session is transferred, after the
+// entities were removed from the client.
+func (t *entityTranslator) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	clear(t.uniques)
+	clear(t.runtimes)
+	clear(t.entities)
+}
+
+// translateServerPacket replaces the server entity IDs in a packet sent by the server with their client IDs.
+// IDs of entities that were not spawned through the translator are left untouched.
+func (t *entityTranslator) translateServerPacket(pk packet.Packet) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch pk := pk.(type) {
+	case *packet.AddActor:
+		pk.EntityUniqueID, pk.EntityRuntimeID = t.add(pk.EntityUniqueID, pk.EntityRuntimeID)
+		t.translateMetadata(pk.EntityMetadata)
+		t.translateLinks(pk.EntityLinks)
+	case *packet.AddItemActor:
+		pk.EntityUniqueID, pk.EntityRuntimeID = t.add(pk.EntityUniqueID, pk.EntityRuntimeID)
+	case *packet.AddPainting:
+		pk.EntityUniqueID, pk.EntityRuntimeID = t.add(pk.EntityUniqueID, pk.EntityRuntimeID)
+	case *packet.AddPlayer:
+		pk.AbilityData.EntityUniqueID, pk.EntityRuntimeID = t.add(pk.AbilityData.EntityUniqueID, pk.EntityRuntimeID)
+		t.translateMetadata(pk.EntityMetadata)
+		t.translateLinks(pk.EntityLinks)
+	case *packet.RemoveActor:
+		pk.EntityUniqueID = t.remove(pk.EntityUniqueID)
+	case *packet.ActorEvent:
+		pk.EntityRuntimeID = t.clientRuntimeID(pk.EntityRuntimeID)
+	case *packet.Animate:
+		pk.EntityRuntimeID = t.clientRuntimeID(pk.EntityRuntimeID)
+	case *packet.BossEvent:
+		pk.BossEntityUniqueID = t.clientUniqueID(pk.BossEntityUniqueID)
+		pk.PlayerUniqueID = t.clientUniqueID(pk.PlayerUniqueID)
+	case *packet.MobArmourEquipment:
+		pk.EntityRuntimeID = t.clientRuntimeID(pk.EntityRuntimeID)
+	case *packet.MobEffect:
+		pk.EntityRuntimeID = t.clientRuntimeID(pk.EntityRuntimeID)
+	case *packet.MobEquipment:
+		pk.EntityRuntimeID = t.clientRuntimeID(pk.EntityRuntimeID)
+	case *packet.MotionPredictionHints:
+		pk.EntityRuntimeID = t.clientRuntimeID(pk.EntityRuntimeID)
+	case *packet.MoveActorAbsolute:
+		pk.EntityRuntimeID = t.clientRuntimeID(pk.EntityRuntimeID)
+	case *packet.MoveActorDelta:
+		pk.EntityRuntimeID = t.clientRuntimeID(pk.EntityRuntimeID)
+	case *packet.MovePlayer:
+		pk.EntityRuntimeID = t.clientRuntimeID(pk.EntityRuntimeID)
+		pk.RiddenEntityRuntimeID = t.clientRuntimeID(pk.RiddenEntityRuntimeID)
+	case *packet.SetActorData:
+		pk.EntityRuntimeID = t.clientRuntimeID(pk.EntityRuntimeID)
+		t.translateMetadata(pk.EntityMetadata)
+	case *packet.SetActorLink:
+		pk.EntityLink.RiddenEntityUniqueID = t.clientUniqueID(pk.EntityLink.RiddenEntityUniqueID)
+		pk.EntityLink.RiderEntityUniqueID = t.clientUniqueID(pk.EntityLink.RiderEntityUniqueID)
+	case *packet.SetActorMotion:
+		pk.EntityRuntimeID = t.clientRuntimeID(pk.EntityRuntimeID)
+	case *packet.TakeItemActor:
+		pk.ItemEntityRuntimeID = t.clientRuntimeID(pk.ItemEntityRuntimeID)
+		pk.TakerEntityRuntimeID = t.clientRuntimeID(pk.TakerEntityRuntimeID)
+	case *packet.UpdateAbilities:
+		pk.AbilityData.EntityUniqueID = t.clientUniqueID(pk.AbilityData.EntityUniqueID)
+	case *packet.UpdateAttributes:
+		pk.EntityRuntimeID = t.clientRuntimeID(pk.EntityRuntimeID)
+	}
+}
+
+// translateClientPacket replaces the client entity IDs in a packet sent by the client with the IDs the server
+// assigned to the entities.
+func (t *entityTranslator) translateClientPacket(pk packet.Packet) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch pk := pk.(type) {
+	case *packet.ActorPickRequest:
+		pk.EntityUniqueID = t.serverUniqueID(pk.EntityUniqueID)
+	case *packet.Animate:
+		pk.EntityRuntimeID = t.serverRuntimeID(pk.EntityRuntimeID)
+	case *packet.Interact:
+		pk.TargetEntityRuntimeID = t.serverRuntimeID(pk.TargetEntityRuntimeID)
+	case *packet.InventoryTransaction:
+		if data, ok := pk.TransactionData.(*protocol.UseItemOnEntityTransactionData); ok {
+			data.TargetEntityRuntimeID = t.serverRuntimeID(data.TargetEntityRuntimeID)
+		}
+	case *packet.MoveActorAbsolute:
+		pk.EntityRuntimeID = t.serverRuntimeID(pk.EntityRuntimeID)
+	}
+}
+
+// add hands out a new client ID for an entity spawned by the server and returns it as unique and runtime ID.
+// add must be called with mu held.
+func (t *entityTranslator) add(uniqueID int64, runtimeID uint64) (int64, uint64) {
+	if previous, ok := t.uniques[uniqueID]; ok {
+		// The server spawned an entity with an ID that is still in use. The previous entity is replaced.
+		delete(t.runtimes, t.entities[previous].runtimeID)
+		delete(t.entities, previous)
+	}
+
+	t.next++
+	id := t.next
+	t.uniques[uniqueID] = id
+	t.runtimes[runtimeID] = id
+	t.entities[id] = translatedEntity{uniqueID: uniqueID, runtimeID: runtimeID}
+	return id, uint64(id)
+}
+
+// remove forgets the entity with the server unique ID passed and returns its client ID. remove must be called
+// with mu held.
+func (t *entityTranslator) remove(uniqueID int64) int64 {
+	id, ok := t.uniques[uniqueID]
+	if !ok {
+		return uniqueID
+	}
+	delete(t.runtimes, t.entities[id].runtimeID)
+	delete(t.uniques, uniqueID)
+	delete(t.entities, id)
+	return id
+}
+
+// clientUniqueID returns the client ID of the entity with the server unique ID passed. clientUniqueID must be
+// called with mu held.
+func (t *entityTranslator) clientUniqueID(uniqueID int64) int64 {
+	if id, ok := t.uniques[uniqueID]; ok {
+		return id
+	}
+	return uniqueID
+}
+
+// clientRuntimeID returns the client ID of the entity with the server runtime ID passed. clientRuntimeID must
+// be called with mu held.
+func (t *entityTranslator) clientRuntimeID(runtimeID uint64) uint64 {
+	if id, ok := t.runtimes[runtimeID]; ok {
+		return uint64(id)
+	}
+	return runtimeID
+}
+
+// serverUniqueID returns the server unique ID of the entity with the client ID passed. serverUniqueID must be
+// called with mu held.
+func (t *entityTranslator) serverUniqueID(id int64) int64 {
+	if entity, ok := t.entities[id]; ok {
+		return entity.uniqueID
+	}
+	return id
+}
+
+// serverRuntimeID returns the server runtime ID of the entity with the client ID passed. serverRuntimeID must
+// be called with mu held.
+func (t *entityTranslator) serverRuntimeID(id uint64) uint64 {
+	if entity, ok := t.entities[int64(id)]; ok {
+		return entity.runtimeID
+	}
+	return id
+}
+
+// translateMetadata translates the entity metadata values that reference other entities. translateMetadata
+// must be called with mu held.
+func (t *entityTranslator) translateMetadata(metadata protocol.EntityMetadata) {
+	for _, key := range []uint32{protocol.EntityDataKeyOwner, protocol.EntityDataKeyTarget, protocol.EntityDataKeyLeashHolder} {
+		if v, ok := metadata[key].(int64); ok {
+			metadata[key] = t.clientUniqueID(v)
+		}
+	}
+}
+
+// translateLinks translates the entities referenced by the entity links passed. translateLinks must be called
+// with mu held.
+func (t *entityTranslator) translateLinks(links []protocol.EntityLink) {
+	for i := range links {
+		links[i].RiddenEntityUniqueID = t.clientUniqueID(links[i].RiddenEntityUniqueID)
+		links[i].RiderEntityUniqueID = t.clientUniqueID(links[i].RiderEntityUniqueID)
+	}
+}
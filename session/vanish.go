@@ -0,0 +1,154 @@
+package session
+
+import (
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/spectrum-proxy/spectrum/server"
+)
+
+// SetVanished hides the player of the session from all other players on the proxy, or shows it again. While
+// vanished, the entry of the player is removed from the player lists of other players, and the packets its
+// server sends to other players to spawn and move the player are dropped, so that servers do not need to
+// support vanishing players themselves. In passthrough mode, packets moving the player are only dropped if
+// they are decoded, which is harmless as other players do not have the player spawned.
+func (s *Session) SetVanished(vanished bool) {
+	if s.vanished.Swap(vanished) == vanished {
+		return
+	}
+	s.registry.setVanished(s, vanished)
+
+	id, _ := uuid.Parse(s.Client().IdentityData().Identity)
+	for _, other := range s.registry.Sessions() {
+		if other != s {
+			other.playerList.setVanished(id, vanished)
+		}
+	}
+	for _, other := range s.registry.SessionsOn(s.ServerAddr()) {
+		if other == s {
+			continue
+		}
+		if vanished {
+			other.hidePlayer(s)
+		} else {
+			other.showPlayer(s)
+		}
+	}
+}
+
+// Vanished checks if the player of the session is vanished through SetVanished.
+func (s *Session) Vanished() bool {
+	return s.vanished.Load()
+}
+
+// hidePlayer removes the player of the vanished session passed and its cosmetics from the client of the
+// session, remembering the entity ID of the player so that it may be spawned again.
+func (s *Session) hidePlayer(vanished *Session) {
+	xuid := vanished.Client().IdentityData().XUID
+	s.cosmeticsMu.Lock()
+	id, ok := s.viewing[xuid]
+	if ok {
+		delete(s.viewing, xuid)
+		s.hidden[xuid] = id
+	}
+	s.cosmeticsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	_ = s.Client().WritePacket(&packet.RemoveActor{EntityUniqueID: id})
+	for cosmetic := range vanished.Cosmetics() {
+		_ = s.Client().WritePacket(&packet.RemoveActor{EntityUniqueID: cosmetic})
+	}
+}
+
+// showPlayer spawns the player of the session passed, which is no longer vanished, on the client of the
+// session, if its server spawned the player while it was vanished.
+func (s *Session) showPlayer(other *Session) {
+	xuid := other.Client().IdentityData().XUID
+	s.cosmeticsMu.Lock()
+	id, ok := s.hidden[xuid]
+	delete(s.hidden, xuid)
+	s.cosmeticsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	identity, data := other.Client().IdentityData(), other.clientData()
+	playerID, _ := uuid.Parse(identity.Identity)
+	metadata := protocol.NewEntityMetadata()
+	metadata[protocol.EntityDataKeyName] = identity.DisplayName
+	metadata[protocol.EntityDataKeyScale] = float32(1)
+
+	pk := &packet.AddPlayer{
+		UUID:            playerID,
+		Username:        identity.DisplayName,
+		EntityRuntimeID: uint64(id),
+		PlatformChatID:  data.PlatformOnlineID,
+		Position:        other.Position(),
+		EntityMetadata:  metadata,
+		AbilityData:     protocol.AbilityData{EntityUniqueID: id},
+		DeviceID:        data.DeviceID,
+		BuildPlatform:   int32(data.DeviceOS),
+	}
+	_ = s.Client().WritePacket(pk)
+	for _, pk := range s.handleCosmeticPacket(pk) {
+		_ = s.Client().WritePacket(pk)
+	}
+}
+
+// filterVanished checks if the packet passed, sent by the server, spawns or moves a vanished player other than
+// the player of the session. If so, it returns false and the packet must be dropped.
+func (s *Session) filterVanished(pk packet.Packet) bool {
+	var id int64
+	switch pk := pk.(type) {
+	case *packet.AddPlayer:
+		vanished := s.registry.vanishedEntity(pk.AbilityData.EntityUniqueID)
+		if vanished == nil || vanished == s {
+			return true
+		}
+		// The player is spawned once it reappears. Its ID is not translated, as the packet never reaches the
+		// entity translator.
+		s.cosmeticsMu.Lock()
+		s.hidden[vanished.Client().IdentityData().XUID] = pk.AbilityData.EntityUniqueID
+		s.cosmeticsMu.Unlock()
+		return false
+	case *packet.ActorEvent:
+		id = int64(pk.EntityRuntimeID)
+	case *packet.Animate:
+		id = int64(pk.EntityRuntimeID)
+	case *packet.MobArmourEquipment:
+		id = int64(pk.EntityRuntimeID)
+	case *packet.MobEquipment:
+		id = int64(pk.EntityRuntimeID)
+	case *packet.MoveActorAbsolute:
+		id = int64(pk.EntityRuntimeID)
+	case *packet.MoveActorDelta:
+		id = int64(pk.EntityRuntimeID)
+	case *packet.MovePlayer:
+		id = int64(pk.EntityRuntimeID)
+	case *packet.SetActorData:
+		id = int64(pk.EntityRuntimeID)
+	case *packet.SetActorMotion:
+		id = int64(pk.EntityRuntimeID)
+	case *packet.UpdateAttributes:
+		id = int64(pk.EntityRuntimeID)
+	default:
+		return true
+	}
+	if id == server.EntityID(s.Client().IdentityData().XUID) {
+		return true
+	}
+	return s.registry.vanishedEntity(id) == nil
+}
+
+// hideVanished hides the entries of all vanished players from the player list of the session. It is called
+// when the session starts.
+func (s *Session) hideVanished() {
+	for _, other := range s.registry.Sessions() {
+		if other != s && other.Vanished() {
+			id, _ := uuid.Parse(other.Client().IdentityData().Identity)
+			s.playerList.setVanished(id, true)
+		}
+	}
+}
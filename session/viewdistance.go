@@ -0,0 +1,83 @@
+package session
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"sync"
+)
+
+// viewDistancePackets holds the IDs of packets sent by the server that are decoded in passthrough mode when the
+// view distance of a server is clamped.
+var viewDistancePackets = []uint32{
+	packet.IDChunkRadiusUpdated,
+	packet.IDLevelChunk,
+	packet.IDNetworkChunkPublisherUpdate,
+	packet.IDSubChunk,
+}
+
+// chunkCenter holds the chunk around which the server of a session publishes chunks.
+type chunkCenter struct {
+	mu    sync.Mutex
+	x, z  int32
+	known bool
+}
+
+// reset forgets the center, which no longer applies after the session was transferred to another server.
+func (c *chunkCenter) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.known = false
+}
+
+// maxViewDistance returns the maximum view distance in chunks configured for the server at the address
+// passed, or zero if it is not limited.
+func (s *Session) maxViewDistance(addr string) int32 {
+	distance, _ := serverOption(s, s.opts.ViewDistances, addr)
+	return distance
+}
+
+// clampClientViewDistance clamps the chunk radius requested by the client to the maximum view distance of its
+// server.
+func (s *Session) clampClientViewDistance(pk *packet.RequestChunkRadius) {
+	if limit := s.maxViewDistance(s.ServerAddr()); limit > 0 {
+		pk.ChunkRadius = min(pk.ChunkRadius, limit)
+		pk.MaxChunkRadius = min(pk.MaxChunkRadius, limit)
+	}
+}
+
+// clampServerViewDistance clamps the view distance in the packet sent by the server passed to the maximum view
+// distance of the server. It returns false if the packet is a chunk outside the view distance, which must be
+// dropped.
+func (s *Session) clampServerViewDistance(pk packet.Packet) bool {
+	limit := s.maxViewDistance(s.ServerAddr())
+	if limit <= 0 {
+		return true
+	}
+
+	switch pk := pk.(type) {
+	case *packet.ChunkRadiusUpdated:
+		pk.ChunkRadius = min(pk.ChunkRadius, limit)
+	case *packet.NetworkChunkPublisherUpdate:
+		pk.Radius = min(pk.Radius, uint32(limit)<<4)
+		s.center.mu.Lock()
+		s.center.x, s.center.z, s.center.known = pk.Position.X()>>4, pk.Position.Z()>>4, true
+		s.center.mu.Unlock()
+	case *packet.LevelChunk:
+		return s.withinViewDistance(pk.Position.X(), pk.Position.Z(), limit)
+	case *packet.SubChunk:
+		return s.withinViewDistance(pk.Position.X(), pk.Position.Z(), limit)
+	}
+	return true
+}
+
+// withinViewDistance returns true if the chunk at the coordinates passed lies within the view distance passed
+// around the chunk center of the session. If the center is not yet known, all chunks are considered within.
+func (s *Session) withinViewDistance(x, z, limit int32) bool {
+	s.center.mu.Lock()
+	defer s.center.mu.Unlock()
+
+	if !s.center.known {
+		return true
+	}
+	dx, dz := int64(x-s.center.x), int64(z-s.center.z)
+	return dx*dx+dz*dz <= int64(limit)*int64(limit)
+}
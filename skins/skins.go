@@ -0,0 +1,75 @@
+package skins
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/spectrum-proxy/spectrum/event"
+	"github.com/spectrum-proxy/spectrum/session"
+	"sync"
+)
+
+// Manager overrides the skins of players network-wide, for example for cosmetics or disguises implemented
+// by the proxy. Overrides are kept by the XUID of the player, so that they are applied again when the player
+// rejoins the proxy, until they are restored.
+type Manager struct {
+	registry *session.Registry
+
+	mu        sync.RWMutex
+	overrides map[string]protocol.Skin
+
+	unsubscribe func()
+}
+
+// New returns a new Manager overriding the skins of the sessions in the registry passed. Overrides are applied
+// to sessions when they start, as published on the bus passed.
+func New(registry *session.Registry, events *event.Bus[session.Event]) *Manager {
+	m := &Manager{registry: registry, overrides: make(map[string]protocol.Skin)}
+	m.unsubscribe = events.Subscribe(m.handleEvent)
+	return m
+}
+
+// Close stops the Manager from following the events of sessions. Skins already overridden are kept.
+func (m *Manager) Close() {
+	m.unsubscribe()
+}
+
+// Override overrides the skin of the player with the XUID passed with the skin passed. If the player is
+// online, the skin is shown immediately.
+func (m *Manager) Override(xuid string, skin protocol.Skin) {
+	m.mu.Lock()
+	m.overrides[xuid] = skin
+	m.mu.Unlock()
+
+	if s := m.registry.GetSession(xuid); s != nil {
+		s.SetSkin(skin)
+	}
+}
+
+// Restore removes the override of the skin of the player with the XUID passed, restoring the skin the player
+// joined with if they are online.
+func (m *Manager) Restore(xuid string) {
+	m.mu.Lock()
+	delete(m.overrides, xuid)
+	m.mu.Unlock()
+
+	if s := m.registry.GetSession(xuid); s != nil {
+		s.ResetSkin()
+	}
+}
+
+// Skin returns the skin overriding the skin of the player with the XUID passed, if any.
+func (m *Manager) Skin(xuid string) (protocol.Skin, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	skin, ok := m.overrides[xuid]
+	return skin, ok
+}
+
+// handleEvent applies the override of the skin of a player to their session when it starts.
+func (m *Manager) handleEvent(e session.Event) {
+	if _, ok := e.(session.SessionStart); !ok {
+		return
+	}
+	if skin, ok := m.Skin(e.Session().Client().IdentityData().XUID); ok {
+		e.Session().SetSkin(skin)
+	}
+}
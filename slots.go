@@ -0,0 +1,88 @@
+package spectrum
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/spectrum-proxy/spectrum/session"
+	"slices"
+)
+
+const (
+	// KickPolicyNone rejects priority players joining when the proxy is full.
+	KickPolicyNone = ""
+	// KickPolicyNewest kicks the player without priority that joined the latest to make room for a priority
+	// player joining when the proxy is full.
+	KickPolicyNewest = "newest"
+	// KickPolicyOldest kicks the player without priority that joined the earliest to make room for a priority
+	// player joining when the proxy is full.
+	KickPolicyOldest = "oldest"
+)
+
+// reserveSlot reserves a slot for the player with the identity passed, returning false if the MaxPlayers of the
+// proxy does not allow the player to join. Players without priority may only use the slots not reserved
+// through PrioritySlots. If the proxy is full, a player without priority is kicked to make room for a priority
+// player according to the KickPolicy. A slot reserved must be released using releaseSlot.
+func (s *Spectrum) reserveSlot(identity login.IdentityData) bool {
+	s.optsMu.RLock()
+	maxPlayers, reserved, policy := s.opts.MaxPlayers, s.opts.PrioritySlots, s.opts.KickPolicy
+	s.optsMu.RUnlock()
+
+	s.slotsMu.Lock()
+	defer s.slotsMu.Unlock()
+	if maxPlayers <= 0 {
+		s.slots++
+		return true
+	}
+
+	count := s.slots
+	if !s.priority(identity) {
+		if count >= maxPlayers-reserved {
+			return false
+		}
+		s.slots++
+		return true
+	}
+	if count < maxPlayers {
+		s.slots++
+		return true
+	}
+	if policy == KickPolicyNone {
+		return false
+	}
+
+	var victim *session.Session
+	for _, sess := range s.registry.Sessions() {
+		if s.priority(sess.Client().IdentityData()) {
+			continue
+		}
+		switch {
+		case victim == nil,
+			policy == KickPolicyNewest && sess.Joined().After(victim.Joined()),
+			policy == KickPolicyOldest && sess.Joined().Before(victim.Joined()):
+			victim = sess
+		}
+	}
+	if victim == nil {
+		return false
+	}
+	s.logger.Info("Kicked player to make room for priority player", "name", victim.Client().IdentityData().DisplayName, "priority", identity.DisplayName)
+	// The slot of the player kicked is released once its session is closed, so the priority player briefly
+	// takes a slot beyond MaxPlayers.
+	victim.Disconnect(messagePriorityKick)
+	s.slots++
+	return true
+}
+
+// releaseSlot releases a slot reserved using reserveSlot.
+func (s *Spectrum) releaseSlot() {
+	s.slotsMu.Lock()
+	defer s.slotsMu.Unlock()
+	s.slots--
+}
+
+// priority checks if the player with the identity passed is a priority player, who may use the reserved
+// slots of the proxy.
+func (s *Spectrum) priority(identity login.IdentityData) bool {
+	s.optsMu.RLock()
+	defer s.optsMu.RUnlock()
+	return slices.Contains(s.opts.PriorityPlayers, identity.XUID) || slices.Contains(s.opts.PriorityPlayers, identity.DisplayName)
+}
@@ -0,0 +1,92 @@
+package social
+
+import (
+	"sort"
+	"sync"
+)
+
+// IgnoreStore stores the players that players ignore, identified by their XUID. Players do not receive
+// private messages from players they ignore. Implementations must be safe for concurrent use.
+type IgnoreStore interface {
+	// Ignore makes the player passed ignore the target passed. Ignoring a target twice is not an error.
+	Ignore(player, target string) error
+	// Unignore makes the player passed stop ignoring the target passed. Unignoring a target that is not
+	// ignored is not an error.
+	Unignore(player, target string) error
+	// Ignores checks if the player passed ignores the target passed.
+	Ignores(player, target string) (bool, error)
+	// Ignored returns the targets ignored by the player passed.
+	Ignored(player string) ([]string, error)
+}
+
+// MemoryIgnoreStore is an IgnoreStore holding ignore lists in memory. They are lost when the process exits.
+type MemoryIgnoreStore struct {
+	mu      sync.RWMutex
+	ignored map[string]map[string]struct{}
+}
+
+// NewMemoryIgnoreStore returns an empty MemoryIgnoreStore.
+func NewMemoryIgnoreStore() *MemoryIgnoreStore {
+	return &MemoryIgnoreStore{ignored: make(map[string]map[string]struct{})}
+}
+
+// Ignore ...
+func (s *MemoryIgnoreStore) Ignore(player, target string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ignored[player] == nil {
+		s.ignored[player] = make(map[string]struct{})
+	}
+	s.ignored[player][target] = struct{}{}
+	return nil
+}
+
+// Unignore ...
+func (s *MemoryIgnoreStore) Unignore(player, target string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.ignored[player], target)
+	if len(s.ignored[player]) == 0 {
+		delete(s.ignored, player)
+	}
+	return nil
+}
+
+// Ignores ...
+func (s *MemoryIgnoreStore) Ignores(player, target string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.ignored[player][target]
+	return ok, nil
+}
+
+// Ignored returns the targets ignored by the player passed, sorted.
+func (s *MemoryIgnoreStore) Ignored(player string) ([]string, error) {
+	s.mu.RLock()
+	targets := make([]string, 0, len(s.ignored[player]))
+	for target := range s.ignored[player] {
+		targets = append(targets, target)
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(targets)
+	return targets, nil
+}
+
+// all returns the ignore lists of all players.
+func (s *MemoryIgnoreStore) all() map[string][]string {
+	s.mu.RLock()
+	players := make([]string, 0, len(s.ignored))
+	for player := range s.ignored {
+		players = append(players, player)
+	}
+	s.mu.RUnlock()
+
+	all := make(map[string][]string, len(players))
+	for _, player := range players {
+		all[player], _ = s.Ignored(player)
+	}
+	return all
+}
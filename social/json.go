@@ -0,0 +1,106 @@
+package social
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// JSONIgnoreStore is an IgnoreStore that persists ignore lists to a JSON file. The file is rewritten whenever
+// a player ignores or unignores another player.
+type JSONIgnoreStore struct {
+	path string
+
+	// mu serialises writes to the file.
+	mu     sync.Mutex
+	memory *MemoryIgnoreStore
+}
+
+// NewJSONIgnoreStore returns a JSONIgnoreStore persisting ignore lists to the file at the path passed.
+// Existing ignore lists are loaded from the file if it exists.
+func NewJSONIgnoreStore(path string) (*JSONIgnoreStore, error) {
+	s := &JSONIgnoreStore{path: path, memory: NewMemoryIgnoreStore()}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ignore lists: %v", err)
+	}
+
+	var ignored map[string][]string
+	if err := json.Unmarshal(data, &ignored); err != nil {
+		return nil, fmt.Errorf("failed to decode ignore lists: %v", err)
+	}
+	for player, targets := range ignored {
+		for _, target := range targets {
+			_ = s.memory.Ignore(player, target)
+		}
+	}
+	return s, nil
+}
+
+// Ignore ...
+func (s *JSONIgnoreStore) Ignore(player, target string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ok, _ := s.memory.Ignores(player, target); ok {
+		return nil
+	}
+	_ = s.memory.Ignore(player, target)
+	return s.save()
+}
+
+// Unignore ...
+func (s *JSONIgnoreStore) Unignore(player, target string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ok, _ := s.memory.Ignores(player, target); !ok {
+		return nil
+	}
+	_ = s.memory.Unignore(player, target)
+	return s.save()
+}
+
+// Ignores ...
+func (s *JSONIgnoreStore) Ignores(player, target string) (bool, error) {
+	return s.memory.Ignores(player, target)
+}
+
+// Ignored ...
+func (s *JSONIgnoreStore) Ignored(player string) ([]string, error) {
+	return s.memory.Ignored(player)
+}
+
+// save writes all ignore lists to the file of the store. The file is replaced atomically, so that it is never
+// left partially written. save must be called with mu held.
+func (s *JSONIgnoreStore) save() error {
+	data, err := json.MarshalIndent(s.memory.all(), "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to encode ignore lists: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write ignore lists: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write ignore lists: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write ignore lists: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write ignore lists: %v", err)
+	}
+	return nil
+}
@@ -1,75 +1,606 @@
 package spectrum
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/sandertv/gophertunnel/minecraft"
-	"github.com/spectrum-proxy/spectrum/internal"
+	"github.com/spectrum-proxy/spectrum/audit"
+	"github.com/spectrum-proxy/spectrum/ban"
+	"github.com/spectrum-proxy/spectrum/cluster"
+	"github.com/spectrum-proxy/spectrum/command/builtin"
+	"github.com/spectrum-proxy/spectrum/detection"
+	"github.com/spectrum-proxy/spectrum/event"
+	"github.com/spectrum-proxy/spectrum/geoip"
+	"github.com/spectrum-proxy/spectrum/healthcheck"
+	"github.com/spectrum-proxy/spectrum/holograms"
+	"github.com/spectrum-proxy/spectrum/locale"
+	"github.com/spectrum-proxy/spectrum/motd"
+	"github.com/spectrum-proxy/spectrum/npc"
+	"github.com/spectrum-proxy/spectrum/party"
+	"github.com/spectrum-proxy/spectrum/queue"
+	"github.com/spectrum-proxy/spectrum/resourcepack"
 	"github.com/spectrum-proxy/spectrum/server"
 	"github.com/spectrum-proxy/spectrum/session"
+	"github.com/spectrum-proxy/spectrum/session/latency"
+	"github.com/spectrum-proxy/spectrum/skins"
+	"github.com/spectrum-proxy/spectrum/social"
+	"github.com/spectrum-proxy/spectrum/version"
+	"github.com/spectrum-proxy/spectrum/whitelist"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type Spectrum struct {
-	logger   internal.Logger
+	logger   *slog.Logger
 	registry *session.Registry
+	servers  *server.Registry
 
-	listener  *minecraft.Listener
+	listeners map[string]*listener
+	listenMu  sync.Mutex
+	network   string
+	incoming  chan net.Conn
 	discovery server.Discovery
-	opts      *Opts
+	fallback  session.FallbackResolver
+	limiter   *loginLimiter
+	bans      ban.Store
+	ignores   social.IgnoreStore
+	analyzers []session.MovementAnalyzer
+	audit     audit.Sink
+	whitelist *whitelist.List
+	pipeline  *session.JoinPipeline
+	packs     *resourcepack.Manager
+	pools     map[string]*server.Pool
+	health    *healthcheck.Checker
+	breaker   *server.Breaker
+	secrets   *server.Secrets
+	events    *event.Bus[session.Event]
+	parties   *party.Manager
+	skins     *skins.Manager
+	npcs      *npc.Manager
+	holograms *holograms.Manager
+	queue     *queue.Queue
+	locales   *locale.Translator
+	motd      *motd.Provider
+	geoip     geoip.Resolver
+	metrics   *http.Server
+	debug     *http.Server
+	cluster   *cluster.Cluster
+	gossip    *http.Server
+	loader    func() (*Opts, error)
+	// loadErr holds the error that occurred loading state required to start, such as the bans. Listen fails
+	// with it if it is not nil.
+	loadErr error
+
+	opts   *Opts
+	optsMu sync.RWMutex
+
+	// slots is the amount of player slots reserved by sessions that were not yet closed.
+	slots   int
+	slotsMu sync.Mutex
+
+	maintenance atomic.Bool
+	closed      chan struct{}
+	closeOnce   sync.Once
 }
 
-func NewSpectrum(discovery server.Discovery, logger internal.Logger, opts *Opts) *Spectrum {
+// NewSpectrum returns a new Spectrum that discovers the servers of players using the Discovery passed. If the
+// logger passed is nil, a logger is created from the Opts using NewLogger.
+func NewSpectrum(discovery server.Discovery, logger *slog.Logger, opts *Opts) *Spectrum {
 	if opts == nil {
 		opts = DefaultOpts()
 	}
+	if logger == nil {
+		logger = NewLogger(opts)
+	}
 
-	return &Spectrum{
+	s := &Spectrum{
 		logger:   logger,
 		registry: session.NewRegistry(),
+		servers:  server.NewRegistry(opts.Servers),
+
+		listeners: make(map[string]*listener),
+		incoming:  make(chan net.Conn),
 
 		discovery: discovery,
+		limiter:   newLoginLimiter(opts),
+		ignores:   newIgnoreStore(logger, opts),
+		audit:     newAuditSink(logger, opts),
+		whitelist: whitelist.New(),
+		packs:     newResourcePacks(logger, opts),
+		events:    event.NewBus[session.Event](),
+		locales:   newTranslator(logger, opts),
 		opts:      opts,
+
+		closed: make(chan struct{}),
+	}
+	s.bans, s.loadErr = newBanStore(opts)
+	s.maintenance.Store(opts.Maintenance)
+	s.pools = newPools(logger, s.servers, opts)
+	s.parties = party.NewManager(s.events)
+	s.skins = skins.New(s.registry, s.events)
+	s.npcs = npc.New(s.registry, s.events)
+	s.holograms = holograms.New(s.registry, s.events)
+	s.pipeline = s.newJoinPipeline()
+	s.secrets = server.NewSecrets(nil, nil)
+	s.updateSecrets(opts)
+	s.queue = s.newQueue()
+	s.registerLimbo()
+	s.registerTransports()
+	s.network = s.registerNetwork()
+	if opts.HealthCheckInterval > 0 {
+		s.health = healthcheck.New(s.servers, nil, opts.HealthCheckThreshold)
+	}
+	if opts.CircuitBreakerThreshold > 0 {
+		s.breaker = server.NewBreaker(opts.CircuitBreakerThreshold, time.Duration(opts.CircuitBreakerCooldown)*time.Millisecond)
 	}
+	builtin.Register(s.servers, s.healthChecker(), s.parties, s.registry)
+	registerMetrics(s.registry)
+	s.registerBreakerMetrics()
+	if len(opts.GeoIPRanges) > 0 {
+		resolver, err := geoip.NewCIDRResolver(opts.GeoIPRanges)
+		if err != nil {
+			logger.Error("Failed to load GeoIP ranges", "err", err)
+		} else {
+			s.geoip = resolver
+		}
+	}
+	if opts.WhitelistFile != "" {
+		list, err := whitelist.Load(opts.WhitelistFile)
+		if err != nil {
+			logger.Error("Failed to load whitelist", "err", err)
+		} else {
+			s.whitelist = list
+			go list.Watch(time.Second*5, s.closed, func(err error) {
+				logger.Error("Failed to reload whitelist", "err", err)
+			})
+		}
+	}
+	return s
 }
 
 func (s *Spectrum) Listen(config minecraft.ListenConfig) (err error) {
-	listener, err := config.Listen("raknet", s.opts.Addr)
-	if err != nil {
-		s.logger.Errorf("Failed to start s: %v", err)
+	if s.loadErr != nil {
+		s.logger.Error("Failed to start spectrum", "err", s.loadErr)
+		return s.loadErr
+	}
+	if config.StatusProvider == nil && len(s.opts.MOTD) > 0 {
+		s.motd = motd.New()
+		s.motd.SetPlayerCounter(s.registry.Count)
+		s.updateMOTD(s.opts)
+		config.StatusProvider = s.motd
+	}
+
+	if err := s.listenAll(config); err != nil {
+		s.logger.Error("Failed to start spectrum", "err", err)
+		s.closeListeners()
 		return err
 	}
 
-	s.logger.Infof("Started sprectrum on %v", listener.Addr())
-	s.listener = listener
+	s.logger.Info("Started spectrum")
+	s.serveMetrics()
+	s.serveDebug()
+	s.joinCluster()
+	s.checkHealth()
+	go s.queue.Run(queueInterval, s.closed)
+	if s.opts.Limbo {
+		go s.rescueLimbo(limboInterval)
+	}
 	return nil
 }
 
+// Accept accepts the next player connecting and returns their session. If a player reconnects while their
+// previous session is suspended, the previous session is resumed and Accept continues with the next player
+// connecting, so that every session is only returned once.
 func (s *Spectrum) Accept() (*session.Session, error) {
-	conn, err := s.listener.Accept()
-	if err != nil {
-		s.logger.Errorf("Failed to accept session: %v", err)
+	var conn net.Conn
+	select {
+	case conn = <-s.incoming:
+	case <-s.closed:
+		err := errors.New("proxy closed")
+		s.logger.Error("Failed to accept session", "err", err)
 		return nil, err
 	}
+	connectionsTotal.Inc()
+	proto := version.Take(conn.(*minecraft.Conn))
+
+	if s.Maintenance() {
+		rejectionsTotal.With("maintenance").Inc()
+		s.disconnect(conn.(*minecraft.Conn), s.maintenanceMessage())
+		return nil, fmt.Errorf("rejected %v during maintenance", conn.RemoteAddr())
+	}
+
+	identity := conn.(*minecraft.Conn).IdentityData()
+	if policy := s.options().ClientDataPolicy; policy.RejectInvalid {
+		if err := policy.Validate(conn.(*minecraft.Conn).ClientData()); err != nil {
+			rejectionsTotal.With("client_data").Inc()
+			s.disconnect(conn.(*minecraft.Conn), messageInvalidClient)
+			return nil, fmt.Errorf("%s has invalid client data: %v", identity.DisplayName, err)
+		}
+	}
+
+	if entry, ok := s.banRisky(conn.(*minecraft.Conn)); ok {
+		rejectionsTotal.With("risk").Inc()
+		s.disconnect(conn.(*minecraft.Conn), entry.Message())
+		return nil, fmt.Errorf("%s was banned for a modified client", identity.DisplayName)
+	}
+	if err := s.admit(conn.(*minecraft.Conn)); err != nil {
+		var rejection session.Rejection
+		if errors.As(err, &rejection) {
+			s.disconnect(conn.(*minecraft.Conn), rejection.Message)
+		} else {
+			_ = conn.Close()
+		}
+		return nil, fmt.Errorf("rejected %s: %v", identity.DisplayName, err)
+	}
+
+	if suspended := s.registry.GetSession(identity.XUID); suspended != nil && suspended.Suspended() {
+		if suspended.Protocol() == proto {
+			if err := suspended.Resume(conn.(*minecraft.Conn)); err != nil {
+				s.logger.Error("Failed to resume session", "name", identity.DisplayName, "err", err)
+				_ = conn.Close()
+				return nil, err
+			}
+			return s.Accept()
+		}
+		// The connection to the server of the session decodes packets for the protocol of the previous client,
+		// so the session cannot be resumed by a client on another protocol.
+		suspended.Close()
+	}
 
 	serverConn, err := s.discovery.Discover(conn.(*minecraft.Conn))
 	if err != nil {
-		_ = conn.Close()
-		return nil, err
+		if serverConn = s.limboAddr(); serverConn == "" {
+			_ = conn.Close()
+			return nil, err
+		}
+		s.logger.Warn("Failed to discover server, parking session in limbo", "name", identity.DisplayName, "err", err)
+	}
+	var queued string
+	if addr := s.servers.Resolve(serverConn); !s.queue.Available(addr) {
+		fallback := s.queueFallback()
+		if fallback == "" {
+			fallback = s.limboAddr()
+		}
+		if fallback == "" {
+			rejectionsTotal.With("full").Inc()
+			s.disconnect(conn.(*minecraft.Conn), messageServerFull)
+			return nil, fmt.Errorf("server %v is full", addr)
+		}
+		queued, serverConn = addr, fallback
 	}
 
-	newSession, err := session.NewSession(conn.(*minecraft.Conn), s.logger, s.registry, serverConn, s.opts.LatencyInterval)
+	if !s.reserveSlot(identity) {
+		rejectionsTotal.With("full").Inc()
+		s.disconnect(conn.(*minecraft.Conn), messageProxyFull)
+		return nil, fmt.Errorf("proxy is full, rejected %s", identity.DisplayName)
+	}
+
+	opts := s.sessionOpts()
+	opts.Protocol = proto
+	if s.geoip != nil {
+		if opts.Region, err = geoip.Region(s.geoip, conn.RemoteAddr()); err != nil {
+			s.logger.Error("Failed to resolve region", "name", identity.DisplayName, "err", err)
+		}
+	}
+
+	newSession, err := session.NewSession(conn.(*minecraft.Conn), s.logger, s.registry, serverConn, opts)
 	if err != nil {
-		s.logger.Errorf("Failed to create session: %v", err)
+		s.logger.Error("Failed to create session", "err", err)
+		s.releaseSlot()
 		_ = conn.Close()
 		return nil, err
 	}
+	context.AfterFunc(newSession.Context(), s.releaseSlot)
 
-	s.logger.Debugf("Accepted session from %v", conn.RemoteAddr())
+	if queued != "" {
+		position := s.queue.Enqueue(newSession, queued, s.queueTier(identity))
+		s.logger.Debug("Queued session", "name", identity.DisplayName, "server", queued, "position", position)
+	}
+	s.logger.Debug("Accepted session", "addr", conn.RemoteAddr())
 	return newSession, nil
 }
 
 func (s *Spectrum) Close() error {
-	return s.listener.Close()
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		if s.metrics != nil {
+			_ = s.metrics.Close()
+		}
+		if s.debug != nil {
+			_ = s.debug.Close()
+		}
+		if s.gossip != nil {
+			_ = s.gossip.Close()
+		}
+		for _, pool := range s.pools {
+			pool.Close()
+		}
+		if s.audit != nil {
+			_ = s.audit.Close()
+		}
+		s.closeListeners()
+	})
+	return nil
+}
+
+// closeListeners stops listening on all listeners of the proxy.
+func (s *Spectrum) closeListeners() {
+	s.listenMu.Lock()
+	defer s.listenMu.Unlock()
+	for _, l := range s.listeners {
+		l.close()
+	}
+}
+
+// SetFallbackResolver sets the FallbackResolver passed to sessions accepted after the call. Sessions are moved
+// to the server it resolves when the connection to their server is lost.
+func (s *Spectrum) SetFallbackResolver(resolver session.FallbackResolver) {
+	s.fallback = resolver
+}
+
+// SetAuditSink sets the sink that joins, quits, transfers and kicks of sessions accepted after the call are
+// recorded in. Passing nil disables the audit log.
+func (s *Spectrum) SetAuditSink(sink audit.Sink) {
+	s.audit = sink
+}
+
+// SetBanStore sets the store that the bans of players are looked up in when they connect.
+func (s *Spectrum) SetBanStore(store ban.Store) {
+	s.bans = store
+}
+
+// banRisky bans the player of the connection passed if the risk that its client was modified reaches the
+// RiskBanScore, returning the ban added.
+func (s *Spectrum) banRisky(conn *minecraft.Conn) (ban.Entry, bool) {
+	opts := s.options()
+	if opts.RiskBanScore <= 0 {
+		return ban.Entry{}, false
+	}
+	risk := detection.Inspect(conn.IdentityData(), conn.ClientData())
+	if risk.Score < opts.RiskBanScore {
+		return ban.Entry{}, false
+	}
+
+	entry := ban.Entry{Type: ban.TypeXUID, Target: conn.IdentityData().XUID, Reason: "Modified client", Created: time.Now()}
+	if entry.Target == "" {
+		entry.Type, entry.Target = ban.TypeIP, ban.IP(conn.RemoteAddr())
+	}
+	if opts.RiskBanDuration > 0 {
+		entry.Expiry = entry.Created.Add(time.Duration(opts.RiskBanDuration) * time.Millisecond)
+	}
+	if err := s.bans.Add(entry); err != nil {
+		s.logger.Error("Failed to add ban", "name", conn.IdentityData().DisplayName, "err", err)
+	}
+	s.logger.Info("Banned player for modified client", "name", conn.IdentityData().DisplayName, "score", risk.Score, "signals", risk.Signals)
+	return entry, true
+}
+
+// Bans returns the store that the bans of players are looked up in when they connect.
+func (s *Spectrum) Bans() ban.Store {
+	return s.bans
+}
+
+// SetIgnoreStore sets the store holding the players that players ignore. It only applies to sessions accepted
+// afterwards.
+func (s *Spectrum) SetIgnoreStore(store social.IgnoreStore) {
+	s.ignores = store
+}
+
+// Ignores returns the store holding the players that players ignore.
+func (s *Spectrum) Ignores() social.IgnoreStore {
+	return s.ignores
+}
+
+// AddMovementAnalyzer adds an analyzer that the movement of players accepted after the call is passed to,
+// for example to detect cheats. Analyzers may be added to single sessions through
+// session.Session.AddMovementAnalyzer.
+func (s *Spectrum) AddMovementAnalyzer(a session.MovementAnalyzer) {
+	s.analyzers = append(s.analyzers, a)
+}
+
+// JoinPipeline returns the steps players pass through when they join the proxy, before their game is started.
+// By default, players are checked against the bans, the whitelist and the slots of the proxy, after which the server they join is
+// selected and their resource packs are checked. Steps may be added, replaced or removed, for example to show
+// players the rules of the proxy through session.Rules.
+func (s *Spectrum) JoinPipeline() *session.JoinPipeline {
+	return s.pipeline
+}
+
+// Whitelist returns the whitelist checked when players connect. The whitelist is disabled unless a file is
+// configured in the Opts of the proxy or it is enabled through whitelist.List.SetEnabled.
+func (s *Spectrum) Whitelist() *whitelist.List {
+	return s.whitelist
+}
+
+// SetGeoIPResolver sets the Resolver used to resolve the region of players when they connect. Passing nil
+// disables resolving regions.
+func (s *Spectrum) SetGeoIPResolver(resolver geoip.Resolver) {
+	s.geoip = resolver
 }
 
 func (s *Spectrum) Registry() *session.Registry {
 	return s.registry
 }
+
+// Parties returns the manager of the parties on the proxy. Members of a party follow its leader when the
+// leader is transferred to another server.
+func (s *Spectrum) Parties() *party.Manager {
+	return s.parties
+}
+
+// Skins returns the manager overriding the skins of players network-wide.
+func (s *Spectrum) Skins() *skins.Manager {
+	return s.skins
+}
+
+// NPCs returns the manager of the NPCs shown to players, such as server selectors in lobbies.
+func (s *Spectrum) NPCs() *npc.Manager {
+	return s.npcs
+}
+
+// Holograms returns the manager of the holograms shown to players.
+func (s *Spectrum) Holograms() *holograms.Manager {
+	return s.holograms
+}
+
+// Events returns the bus on which lifecycle events of all sessions, such as session.SessionStart and
+// session.TransferEnd, are published. Subscribers are called synchronously and should not block.
+func (s *Spectrum) Events() *event.Bus[session.Event] {
+	return s.events
+}
+
+// ResourcePacks returns the resourcepack.Manager holding the resource packs sent to players when they join and
+// the packs required by servers. Packs must be added before Listen is called.
+func (s *Spectrum) ResourcePacks() *resourcepack.Manager {
+	return s.packs
+}
+
+// Servers returns the server.Registry holding the named servers known to the proxy.
+func (s *Spectrum) Servers() *server.Registry {
+	return s.servers
+}
+
+func (s *Spectrum) sessionOpts() session.Opts {
+	s.optsMu.RLock()
+	defer s.optsMu.RUnlock()
+	dialFailure := s.opts.DialFailureMessage
+	if dialFailure == "" {
+		dialFailure = messageDialFailed
+	}
+	return session.Opts{
+		LatencyInterval:    s.opts.LatencyInterval,
+		LatencyStrategy:    latency.Strategy(s.opts.LatencyStrategy),
+		LatencySmoothing:   s.opts.LatencySmoothing,
+		PingDisplay:        s.opts.PingDisplay,
+		TransferRetries:    s.opts.TransferRetries,
+		TransferBackoff:    s.opts.TransferBackoff,
+		PipelineWorkers:    s.opts.PipelineWorkers,
+		Compression:        s.opts.ServerCompression,
+		Transports:         s.transports(),
+		Pools:              s.pools,
+		Capacities:         s.opts.ServerCapacities,
+		Health:             s.healthChecker(),
+		Breaker:            s.breaker,
+		Secrets:            s.secrets,
+		ControlAllPlayers:  s.opts.ControlAllPlayers,
+		ClientDataPolicy:   s.opts.ClientDataPolicy,
+		DialTimeout:        s.opts.DialTimeout,
+		LoginTimeout:       s.opts.LoginTimeout,
+		Events:             s.events,
+		ReconnectGrace:     s.opts.ReconnectGrace,
+		ReplayWindow:       s.opts.ReplayWindow,
+		JoinPipeline:       s.pipeline,
+		DialFailureMessage: dialFailure,
+		Translator:         s.locales,
+		Audit:              s.audit,
+		Throttle:           s.opts.Throttle,
+		ChunkPacing:        s.opts.ChunkPacing,
+		ViewDistances:      s.opts.ServerViewDistances,
+
+		NetworkPlayerList: s.opts.NetworkPlayerList,
+		PlayerListHeader:  s.opts.PlayerListHeader,
+		PlayerListFooter:  s.opts.PlayerListFooter,
+		PlayerListRanks:   s.opts.PlayerListRanks,
+		ChatChannels:      s.opts.ChatChannels,
+		Ignores:           s.ignores,
+		MovementAnalyzers: s.analyzers,
+		Limbo:             s.limboAddr(),
+
+		Passthrough:       s.opts.Passthrough,
+		PassthroughDecode: s.opts.PassthroughDecode,
+
+		TranslateEntityIDs: s.opts.TranslateEntityIDs,
+		SeamlessTransfer:   s.opts.SeamlessTransfer,
+		TransferForwarding: s.opts.TransferForwarding,
+		Animation:          s.opts.Animation,
+		FloodLimits:        s.opts.FloodLimits,
+
+		ResourcePacks:    s.packs,
+		FallbackResolver: s.fallback,
+		Servers:          s.servers,
+	}
+}
+
+// newBanStore returns the ban store configured in the Opts passed. If no file is configured, bans are only kept
+// in memory. An error is returned if the file configured cannot be loaded.
+func newBanStore(opts *Opts) (ban.Store, error) {
+	if opts.BansFile == "" {
+		return ban.NewMemoryStore(), nil
+	}
+
+	store, err := ban.NewJSONStore(opts.BansFile)
+	if err != nil {
+		// Bans are still checked against an empty store, but the proxy refuses to start, as bans would
+		// otherwise silently stop being persisted.
+		return ban.NewMemoryStore(), err
+	}
+	return store, nil
+}
+
+// newIgnoreStore returns the ignore store configured in the Opts passed. If no file is configured, or the file
+// cannot be loaded, ignore lists are only kept in memory.
+func newIgnoreStore(logger *slog.Logger, opts *Opts) social.IgnoreStore {
+	if opts.IgnoresFile == "" {
+		return social.NewMemoryIgnoreStore()
+	}
+
+	store, err := social.NewJSONIgnoreStore(opts.IgnoresFile)
+	if err != nil {
+		logger.Error("Failed to load ignore lists", "err", err)
+		return social.NewMemoryIgnoreStore()
+	}
+	return store
+}
+
+// newAuditSink returns the audit.Sink writing to the audit log configured in the Opts passed, or nil if no
+// audit log is configured.
+func newAuditSink(logger *slog.Logger, opts *Opts) audit.Sink {
+	if opts.AuditFile == "" {
+		return nil
+	}
+
+	sink, err := audit.NewFileSink(opts.AuditFile, opts.AuditMaxSize, opts.AuditBackups)
+	if err != nil {
+		logger.Error("Failed to open audit log", "err", err)
+		return nil
+	}
+	return sink
+}
+
+// newResourcePacks returns a resourcepack.Manager holding the resource packs configured in the Opts passed.
+func newResourcePacks(logger *slog.Logger, opts *Opts) *resourcepack.Manager {
+	packs := resourcepack.NewManager()
+	if opts.ResourcePacksDir != "" {
+		if err := packs.LoadDirectory(opts.ResourcePacksDir, opts.ResourcePackKeys); err != nil {
+			logger.Error("Failed to load resource packs", "err", err)
+		}
+	}
+	for server, uuids := range opts.ServerResourcePacks {
+		packs.SetServerPacks(server, uuids...)
+	}
+	return packs
+}
+
+// newPools returns the pools of spare connections configured in the Opts passed, keyed by the address of the
+// server.
+func newPools(logger *slog.Logger, servers *server.Registry, opts *Opts) map[string]*server.Pool {
+	pools := make(map[string]*server.Pool, len(opts.ServerPools))
+	for name, config := range opts.ServerPools {
+		addr := servers.Resolve(name)
+
+		var transport server.Transport
+		if transportName, ok := opts.ServerTransports[name]; ok {
+			if transport, ok = server.TransportByName(transportName); !ok {
+				logger.Error("Unknown transport of connection pool", "server", name, "transport", transportName)
+				continue
+			}
+		}
+		pools[addr] = server.NewPool(addr, transport, opts.ServerCompression[name], config)
+	}
+	return pools
+}
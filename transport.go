@@ -0,0 +1,63 @@
+package spectrum
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/spectrum-proxy/spectrum/server"
+	"os"
+)
+
+// LinkTLS configures the TLS used by the "tls" transport to secure the connections to servers.
+type LinkTLS struct {
+	// CAFile is the path of a PEM file holding the certificates of the authorities the certificates of servers
+	// are verified against. If empty, the root certificates of the system are used.
+	CAFile string `yaml:"ca_file"`
+	// CertFile and KeyFile are the paths of the PEM encoded certificate and key the proxy authenticates itself
+	// to servers with. If empty, the proxy does not present a certificate.
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	// ServerName is the name the certificates of servers are verified against. If empty, the host of the
+	// address of the server is used.
+	ServerName string `yaml:"server_name"`
+}
+
+// registerTransports registers the transports configured in the Opts of the proxy: the "aead" transport if a
+// LinkKey is set, and the "tls" transport with the LinkTLS configuration if set.
+func (s *Spectrum) registerTransports() {
+	if s.opts.LinkKey != "" {
+		server.RegisterTransport("aead", server.AEAD{Key: []byte(s.opts.LinkKey)})
+	}
+	if s.opts.LinkTLS != (LinkTLS{}) {
+		config, err := s.opts.LinkTLS.config()
+		if err != nil {
+			s.logger.Error("Failed to configure TLS transport", "err", err)
+			return
+		}
+		server.RegisterTransport("tls", server.TLS{Config: config})
+	}
+}
+
+// config returns the tls.Config described by the LinkTLS.
+func (t LinkTLS) config() (*tls.Config, error) {
+	config := &tls.Config{ServerName: t.ServerName, MinVersion: tls.VersionTLS12}
+	if t.CAFile != "" {
+		data, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %v", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(data) {
+			return nil, errors.New("no certificates found in CA file")
+		}
+	}
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
@@ -0,0 +1,11 @@
+package version
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// v622 is the protocol of Minecraft 1.20.40. It shares the conversions of protocol 630, but cannot receive the
+// packets added in 1.20.50.
+var v622 = New(622, "1.20.40", extend(v630, map[uint32]Conversion{
+	packet.IDSetPlayerInventoryOptions: drop,
+}))
@@ -0,0 +1,11 @@
+package version
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// v630 is the protocol of Minecraft 1.20.50. It shares the conversions of protocol 649, but cannot receive the
+// packets added in 1.20.60.
+var v630 = New(630, "1.20.50", extend(v649, map[uint32]Conversion{
+	packet.IDSetHud: drop,
+}))
@@ -0,0 +1,126 @@
+package version
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// v649 is the protocol of Minecraft 1.20.60.
+var v649 = New(649, "1.20.60", map[uint32]Conversion{
+	packet.IDLecternUpdate: {
+		New: func() packet.Packet { return &lecternUpdate649{} },
+		ToLatest: func(pk packet.Packet) []packet.Packet {
+			return []packet.Packet{&pk.(*lecternUpdate649).LecternUpdate}
+		},
+	},
+	packet.IDPlayerAuthInput: {
+		New: func() packet.Packet { return &playerAuthInput649{} },
+		ToLatest: func(pk packet.Packet) []packet.Packet {
+			return []packet.Packet{&pk.(*playerAuthInput649).PlayerAuthInput}
+		},
+	},
+	packet.IDMobEffect: {
+		New: func() packet.Packet { return &mobEffect649{} },
+		FromLatest: func(pk packet.Packet) []packet.Packet {
+			return []packet.Packet{&mobEffect649{MobEffect: *pk.(*packet.MobEffect)}}
+		},
+	},
+	packet.IDResourcePacksInfo: {
+		New: func() packet.Packet { return &resourcePacksInfo649{} },
+		FromLatest: func(pk packet.Packet) []packet.Packet {
+			return []packet.Packet{&resourcePacksInfo649{ResourcePacksInfo: *pk.(*packet.ResourcePacksInfo)}}
+		},
+	},
+	packet.IDSetActorMotion: {
+		New: func() packet.Packet { return &setActorMotion649{} },
+		FromLatest: func(pk packet.Packet) []packet.Packet {
+			return []packet.Packet{&setActorMotion649{SetActorMotion: *pk.(*packet.SetActorMotion)}}
+		},
+	},
+})
+
+// lecternUpdate649 is the LecternUpdate packet of protocol 649, which still held whether the book was dropped.
+type lecternUpdate649 struct {
+	packet.LecternUpdate
+	DropBook bool
+}
+
+func (pk *lecternUpdate649) Marshal(io protocol.IO) {
+	io.Uint8(&pk.Page)
+	io.Uint8(&pk.PageCount)
+	io.UBlockPos(&pk.Position)
+	io.Bool(&pk.DropBook)
+}
+
+// mobEffect649 is the MobEffect packet of protocol 649, which did not yet hold the server tick.
+type mobEffect649 struct {
+	packet.MobEffect
+}
+
+func (pk *mobEffect649) Marshal(io protocol.IO) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Uint8(&pk.Operation)
+	io.Varint32(&pk.EffectType)
+	io.Varint32(&pk.Amplifier)
+	io.Bool(&pk.Particles)
+	io.Varint32(&pk.Duration)
+}
+
+// playerAuthInput649 is the PlayerAuthInput packet of protocol 649, which did not yet hold the rotation of a
+// vehicle predicted by the client.
+type playerAuthInput649 struct {
+	packet.PlayerAuthInput
+}
+
+func (pk *playerAuthInput649) Marshal(io protocol.IO) {
+	io.Float32(&pk.Pitch)
+	io.Float32(&pk.Yaw)
+	io.Vec3(&pk.Position)
+	io.Vec2(&pk.MoveVector)
+	io.Float32(&pk.HeadYaw)
+	io.Varuint64(&pk.InputData)
+	io.Varuint32(&pk.InputMode)
+	io.Varuint32(&pk.PlayMode)
+	io.Varint32(&pk.InteractionModel)
+	if pk.PlayMode == packet.PlayModeReality {
+		io.Vec3(&pk.GazeDirection)
+	}
+	io.Varuint64(&pk.Tick)
+	io.Vec3(&pk.Delta)
+
+	if pk.InputData&packet.InputFlagPerformItemInteraction != 0 {
+		io.PlayerInventoryAction(&pk.ItemInteractionData)
+	}
+	if pk.InputData&packet.InputFlagPerformItemStackRequest != 0 {
+		protocol.Single(io, &pk.ItemStackRequest)
+	}
+	if pk.InputData&packet.InputFlagPerformBlockActions != 0 {
+		protocol.SliceVarint32Length(io, &pk.BlockActions)
+	}
+	io.Vec2(&pk.AnalogueMoveVector)
+}
+
+// resourcePacksInfo649 is the ResourcePacksInfo packet of protocol 649, which did not yet hold whether the
+// server has add-ons.
+type resourcePacksInfo649 struct {
+	packet.ResourcePacksInfo
+}
+
+func (pk *resourcePacksInfo649) Marshal(io protocol.IO) {
+	io.Bool(&pk.TexturePackRequired)
+	io.Bool(&pk.HasScripts)
+	io.Bool(&pk.ForcingServerPacks)
+	protocol.SliceUint16Length(io, &pk.BehaviourPacks)
+	protocol.SliceUint16Length(io, &pk.TexturePacks)
+	protocol.Slice(io, &pk.PackURLs)
+}
+
+// setActorMotion649 is the SetActorMotion packet of protocol 649, which did not yet hold the server tick.
+type setActorMotion649 struct {
+	packet.SetActorMotion
+}
+
+func (pk *setActorMotion649) Marshal(io protocol.IO) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Vec3(&pk.Velocity)
+}
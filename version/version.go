@@ -0,0 +1,143 @@
+// Package version allows clients on older protocol versions to join the proxy. Packets of older clients are
+// converted to and from the latest protocol, which is the protocol spoken with servers, using a conversion
+// table per protocol version.
+package version
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"net"
+	"sync"
+)
+
+// Conversion converts a packet that changed in a protocol version compared to the latest protocol.
+type Conversion struct {
+	// New returns a new packet of the protocol version. If nil, the packet of the latest protocol is used.
+	New func() packet.Packet
+	// ToLatest converts a packet sent by the client to packets of the latest protocol. If nil, the packet is
+	// passed on as is.
+	ToLatest func(pk packet.Packet) []packet.Packet
+	// FromLatest converts a packet of the latest protocol to packets of the protocol version before it is sent
+	// to the client. If nil, the packet is sent as is.
+	FromLatest func(pk packet.Packet) []packet.Packet
+}
+
+// Protocol is a minecraft.Protocol for an older protocol version, converting the packets that changed since
+// using a table of Conversions.
+type Protocol struct {
+	id          int32
+	ver         string
+	conversions map[uint32]Conversion
+}
+
+// New returns a new Protocol with the ID and version passed, converting packets using the Conversions passed,
+// keyed by packet ID.
+func New(id int32, ver string, conversions map[uint32]Conversion) *Protocol {
+	return &Protocol{id: id, ver: ver, conversions: conversions}
+}
+
+// ID ...
+func (p *Protocol) ID() int32 {
+	return p.id
+}
+
+// Ver ...
+func (p *Protocol) Ver() string {
+	return p.ver
+}
+
+// Packets ...
+func (p *Protocol) Packets(listener bool) packet.Pool {
+	pool := packet.NewServerPool()
+	if listener {
+		pool = packet.NewClientPool()
+	}
+	for id, conversion := range p.conversions {
+		if _, ok := pool[id]; ok && conversion.New != nil {
+			pool[id] = conversion.New
+		}
+	}
+	return pool
+}
+
+// NewReader ...
+func (p *Protocol) NewReader(r minecraft.ByteReader, shieldID int32, enableLimits bool) protocol.IO {
+	return protocol.NewReader(r, shieldID, enableLimits)
+}
+
+// NewWriter ...
+func (p *Protocol) NewWriter(w minecraft.ByteWriter, shieldID int32) protocol.IO {
+	return protocol.NewWriter(w, shieldID)
+}
+
+// ConvertToLatest ...
+func (p *Protocol) ConvertToLatest(pk packet.Packet, conn *minecraft.Conn) []packet.Packet {
+	if response, ok := pk.(*packet.ResourcePackClientResponse); ok && response.Response == packet.PackResponseCompleted {
+		// The client finished logging in and is about to be accepted by the listener, so the protocol it
+		// joined with is recorded until it is taken by Take.
+		conns.Store(conn.RemoteAddr().String(), p.id)
+	}
+	if conversion, ok := p.conversions[pk.ID()]; ok && conversion.ToLatest != nil {
+		return conversion.ToLatest(pk)
+	}
+	return []packet.Packet{pk}
+}
+
+// ConvertFromLatest ...
+func (p *Protocol) ConvertFromLatest(pk packet.Packet, _ *minecraft.Conn) []packet.Packet {
+	if conversion, ok := p.conversions[pk.ID()]; ok && conversion.FromLatest != nil {
+		return conversion.FromLatest(pk)
+	}
+	return []packet.Packet{pk}
+}
+
+// Protocols returns all older protocols supported, from newest to oldest. They may be passed to the
+// AcceptedProtocols of a minecraft.ListenConfig.
+func Protocols() []minecraft.Protocol {
+	return []minecraft.Protocol{v649, v630, v622}
+}
+
+// conns holds the protocol IDs of connections that logged in with an older protocol and were not yet taken,
+// keyed by the remote address of the connection.
+var conns sync.Map
+
+// Take returns the ID of the protocol the connection passed logged in with and forgets about the connection.
+// It must be called once for every connection accepted by a listener with the Protocols of this package. The
+// ID of the latest protocol is returned for connections that did not log in with an older protocol.
+func Take(conn *minecraft.Conn) int32 {
+	if id, ok := conns.LoadAndDelete(conn.RemoteAddr().String()); ok {
+		return id.(int32)
+	}
+	return protocol.CurrentProtocol
+}
+
+// Forget forgets the protocol of the connection with the remote address passed. It must be called when a
+// connection is closed, so that connections closed before they were passed to Take are not remembered.
+func Forget(addr net.Addr) {
+	conns.Delete(addr.String())
+}
+
+// Latest returns true if the protocol ID passed is the ID of the latest protocol.
+func Latest(id int32) bool {
+	return id == protocol.CurrentProtocol
+}
+
+// extend returns the Conversions of the Protocol passed with the Conversions passed added to them. It is used
+// by protocols older than the Protocol passed, which share the conversions of the packets that changed since.
+func extend(p *Protocol, conversions map[uint32]Conversion) map[uint32]Conversion {
+	m := make(map[uint32]Conversion, len(p.conversions)+len(conversions))
+	for id, conversion := range p.conversions {
+		m[id] = conversion
+	}
+	for id, conversion := range conversions {
+		m[id] = conversion
+	}
+	return m
+}
+
+// drop is the Conversion of packets that do not exist in a protocol version. They are dropped instead of
+// being sent to the client.
+var drop = Conversion{
+	FromLatest: func(packet.Packet) []packet.Packet { return nil },
+}
@@ -0,0 +1,47 @@
+package whitelist
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTPChecker is a Checker that looks players up through an HTTP service. A GET request is sent to URL with
+// the xuid and name query parameters set. A 200 response allows the player, and a 404 response denies them.
+type HTTPChecker struct {
+	// URL is the URL of the service.
+	URL string
+	// Client is the client used to send requests. If nil, a client with a timeout of 5 seconds is used.
+	Client *http.Client
+}
+
+// Whitelisted ...
+func (c *HTTPChecker) Whitelisted(xuid, name string) (bool, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse whitelist URL: %v", err)
+	}
+	query := u.Query()
+	query.Set("xuid", xuid)
+	query.Set("name", name)
+	u.RawQuery = query.Encode()
+
+	client := c.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to query whitelist: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, fmt.Errorf("unexpected whitelist response status %v", resp.Status)
+}
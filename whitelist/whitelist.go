@@ -0,0 +1,184 @@
+package whitelist
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Checker is a source that decides if a player is whitelisted, such as an HTTP service.
+type Checker interface {
+	// Whitelisted returns true if the player with the XUID and gamertag passed is whitelisted.
+	Whitelisted(xuid, name string) (bool, error)
+}
+
+// List is a whitelist. Players are allowed to join if their XUID or gamertag is in the list, or if any of
+// the Checkers of the list allows them. A List is safe for concurrent use.
+type List struct {
+	enabled atomic.Bool
+
+	mu       sync.RWMutex
+	entries  map[string]struct{}
+	checkers []Checker
+
+	path    string
+	modTime time.Time
+}
+
+// New returns an empty, disabled List.
+func New() *List {
+	return &List{entries: make(map[string]struct{})}
+}
+
+// Load returns an enabled List holding the entries of the file at the path passed. The file holds one XUID or
+// gamertag per line. Empty lines and lines starting with # are ignored. If the file does not exist, the List
+// is empty.
+func Load(path string) (*List, error) {
+	l := New()
+	l.path = path
+	if err := l.Reload(); err != nil {
+		return nil, err
+	}
+	l.enabled.Store(true)
+	return l, nil
+}
+
+// Reload reloads the entries of the list from its file. Entries added through Add since the last reload are
+// discarded. Reload is a no-op if the list was not loaded from a file.
+func (l *List) Reload() error {
+	if l.path == "" {
+		return nil
+	}
+
+	entries := make(map[string]struct{})
+	f, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		l.mu.Lock()
+		l.entries, l.modTime = entries, time.Time{}
+		l.mu.Unlock()
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open whitelist: %v", err)
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat whitelist: %v", err)
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read whitelist: %v", err)
+	}
+
+	l.mu.Lock()
+	l.entries, l.modTime = entries, stat.ModTime()
+	l.mu.Unlock()
+	return nil
+}
+
+// Watch reloads the list from its file whenever the file is modified, checking for modifications at the
+// interval passed. Errors that occur while reloading are passed to onError, which may be nil. Watch blocks
+// until the channel passed is closed.
+func (l *List) Watch(interval time.Duration, done <-chan struct{}, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			stat, err := os.Stat(l.path)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				if onError != nil {
+					onError(err)
+				}
+				continue
+			}
+
+			l.mu.RLock()
+			modTime := l.modTime
+			l.mu.RUnlock()
+			if stat != nil && stat.ModTime().Equal(modTime) {
+				continue
+			}
+			if err := l.Reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// SetEnabled enables or disables the list. A disabled list allows every player.
+func (l *List) SetEnabled(enabled bool) {
+	l.enabled.Store(enabled)
+}
+
+// Enabled returns true if the list is enabled.
+func (l *List) Enabled() bool {
+	return l.enabled.Load()
+}
+
+// Add adds a XUID or gamertag to the list. Entries added are not written to the file of the list.
+func (l *List) Add(entry string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[strings.ToLower(entry)] = struct{}{}
+}
+
+// Remove removes a XUID or gamertag from the list.
+func (l *List) Remove(entry string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.entries, strings.ToLower(entry))
+}
+
+// AddChecker adds a Checker that is consulted for players that are not in the list.
+func (l *List) AddChecker(checker Checker) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.checkers = append(l.checkers, checker)
+}
+
+// Allowed returns true if the player with the XUID and gamertag passed is allowed to join. All players are
+// allowed if the list is disabled.
+func (l *List) Allowed(xuid, name string) (bool, error) {
+	if !l.Enabled() {
+		return true, nil
+	}
+
+	l.mu.RLock()
+	_, xuidOk := l.entries[xuid]
+	_, nameOk := l.entries[strings.ToLower(name)]
+	checkers := l.checkers
+	l.mu.RUnlock()
+	if (xuid != "" && xuidOk) || nameOk {
+		return true, nil
+	}
+
+	for _, checker := range checkers {
+		ok, err := checker.Whitelisted(xuid, name)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}